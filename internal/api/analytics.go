@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/rs/zerolog/log"
+)
+
+// analyticsPageSize is the number of API calls retrieved per page
+const analyticsPageSize = 1000
+
+type Analytics struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// APICallData contains the analytics details of a single call of an APIProxy. ResponseTime is in milliseconds.
+type APICallData struct {
+	APIProxy     string `json:"apiproxy"`
+	APIResource  string `json:"api_resource"`
+	RequestVerb  string `json:"request_verb"`
+	StatusCode   int    `json:"response_status_code"`
+	ResponseTime int64  `json:"total_response_time"`
+	IsError      bool   `json:"is_error"`
+}
+
+func NewAnalytics(exe *httpclnt.HTTPExecuter) *Analytics {
+	a := new(Analytics)
+	a.exe = exe
+	return a
+}
+
+// ListAPICalls returns the calls of the APIProxies that were received from (inclusive) to (exclusive)
+func (a *Analytics) ListAPICalls(proxyNames []string, from time.Time, to time.Time) ([]*APICallData, error) {
+	log.Info().Msgf("Getting API calls of %v between %v and %v", strings.Join(proxyNames, ", "), from.Format(time.RFC3339), to.Format(time.RFC3339))
+	urlPath := "/apiportal/api/1.0/Analytics.svc/APICalls"
+
+	var proxyFilters []string
+	for _, name := range proxyNames {
+		proxyFilters = append(proxyFilters, FilterEquals("apiproxy", name))
+	}
+	const timestampFormat = "2006-01-02T15:04:05"
+	filter := FilterAnd(
+		strings.Join(proxyFilters, " or "),
+		fmt.Sprintf("client_received_start_timestamp ge datetime'%v'", from.UTC().Format(timestampFormat)),
+		fmt.Sprintf("client_received_start_timestamp lt datetime'%v'", to.UTC().Format(timestampFormat)),
+	)
+	query := &Query{
+		Filter: filter,
+		Select: "apiproxy,api_resource,request_verb,response_status_code,total_response_time,is_error",
+		Top:    analyticsPageSize,
+	}
+	return getAllResults[*APICallData](urlPath, query, "List API calls", a.exe)
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyticsListAPICalls(t *testing.T) {
+	var filter string
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("$filter")
+		assert.Equal(t, "/apiportal/api/1.0/Analytics.svc/APICalls", r.URL.Path, "Incorrect URL path")
+		fmt.Fprint(w, `{"d":{"results":[{"apiproxy":"Orders","api_resource":"orders","request_verb":"GET","response_status_code":200,"total_response_time":120,"is_error":false}]}}`)
+	})
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	calls, err := NewAnalytics(exe).ListAPICalls([]string{"Orders", "Customers"}, from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("ListAPICalls failed with error - %v", err)
+	}
+	assert.Equal(t, "(apiproxy eq 'Orders' or apiproxy eq 'Customers') and (client_received_start_timestamp ge datetime'2024-05-01T00:00:00') and (client_received_start_timestamp lt datetime'2024-05-02T00:00:00')", filter, "Incorrect filter")
+	assert.Equal(t, 1, len(calls), "Expected number of calls = 1")
+	assert.Equal(t, int64(120), calls[0].ResponseTime, "Incorrect response time")
+}
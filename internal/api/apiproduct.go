@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type APIProduct struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// APIProductData contains the details of an API product and the names of the APIProxies it bundles
+type APIProductData struct {
+	Name          string   `json:"name"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Version       string   `json:"version,omitempty"`
+	Scope         string   `json:"scope,omitempty"`
+	IsPublished   bool     `json:"isPublished"`
+	QuotaCount    *int     `json:"quotaCount,omitempty"`
+	QuotaInterval *int     `json:"quotaInterval,omitempty"`
+	QuotaTimeUnit string   `json:"quotaTimeUnit,omitempty"`
+	APIProxies    []string `json:"apiProxies,omitempty"`
+}
+
+type apiProductResult struct {
+	APIProductData
+	APIProxies navigationResults[*APIEntity] `json:"apiProxies"`
+}
+
+type apiProductBody struct {
+	APIProductData
+	APIProxies []*navigationLink `json:"apiProxies"`
+}
+
+func NewAPIProduct(exe *httpclnt.HTTPExecuter) *APIProduct {
+	a := new(APIProduct)
+	a.exe = exe
+	return a
+}
+
+func (a *APIProduct) List() ([]*APIProductData, error) {
+	log.Info().Msgf("Getting list of APIProducts")
+	urlPath := "/apiportal/api/1.0/Management.svc/APIProducts"
+
+	results, err := getAllResults[*apiProductResult](urlPath, &Query{Expand: "apiProxies"}, "List APIProducts", a.exe)
+	if err != nil {
+		return nil, err
+	}
+	var products []*APIProductData
+	for _, result := range results {
+		products = append(products, result.toData())
+	}
+	return products, nil
+}
+
+// Get returns the details of the API product, or nil if it does not exist
+func (a *APIProduct) Get(name string) (*APIProductData, error) {
+	log.Info().Msgf("Getting details of APIProduct %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProducts('%v')?$expand=apiProxies", name)
+
+	result, err := getSingleResult[apiProductResult](urlPath, "Get APIProduct", a.exe)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.toData(), nil
+}
+
+func (a *APIProduct) Create(product *APIProductData) error {
+	log.Info().Msgf("Creating APIProduct %v", product.Name)
+	urlPath := "/apiportal/api/1.0/Management.svc/APIProducts"
+
+	requestBody, err := a.constructBody(product)
+	if err != nil {
+		return err
+	}
+	return modifyingCall("POST", urlPath, requestBody, 201, "Create APIProduct", a.exe)
+}
+
+func (a *APIProduct) Update(product *APIProductData) error {
+	log.Info().Msgf("Updating APIProduct %v", product.Name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProducts('%v')", product.Name)
+
+	requestBody, err := a.constructBody(product)
+	if err != nil {
+		return err
+	}
+	return modifyingCall("PUT", urlPath, requestBody, 204, "Update APIProduct", a.exe)
+}
+
+func (a *APIProduct) Delete(name string) error {
+	log.Info().Msgf("Deleting APIProduct %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProducts('%v')", name)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete APIProduct", a.exe)
+}
+
+func (a *APIProduct) constructBody(product *APIProductData) ([]byte, error) {
+	// The bundled APIProxies are linked to the product with their URIs
+	body := &apiProductBody{APIProductData: *product, APIProxies: navigationLinks("APIProxies", product.APIProxies)}
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return requestBody, nil
+}
+
+func (r *apiProductResult) toData() *APIProductData {
+	data := r.APIProductData
+	data.APIProxies = nil
+	for _, proxy := range r.APIProxies.Results {
+		data.APIProxies = append(data.APIProxies, proxy.Name)
+	}
+	sort.Strings(data.APIProxies)
+	return &data
+}
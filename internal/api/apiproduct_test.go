@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIProductGet(t *testing.T) {
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "apiProxies", r.URL.Query().Get("$expand"), "Expected APIProxies expanded")
+		if r.URL.Path == "/apiportal/api/1.0/Management.svc/APIProducts('Orders')" {
+			fmt.Fprint(w, `{"d":{"__metadata":{},"name":"Orders","title":"Orders","isPublished":true,"quotaCount":100,"apiProxies":{"results":[{"name":"OrdersV2"},{"name":"OrdersV1"}]}}}`)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	product := NewAPIProduct(exe)
+
+	details, err := product.Get("Orders")
+	if err != nil {
+		t.Fatalf("Get failed with error - %v", err)
+	}
+	assert.Equal(t, "Orders", details.Name, "Incorrect product name")
+	assert.Equal(t, 100, *details.QuotaCount, "Incorrect quota count")
+	assert.Equal(t, []string{"OrdersV1", "OrdersV2"}, details.APIProxies, "Expected sorted names of APIProxies")
+
+	details, err = product.Get("Unknown")
+	if err != nil {
+		t.Fatalf("Get failed with error - %v", err)
+	}
+	assert.Nil(t, details, "Expected nil for product that does not exist")
+}
+
+func TestAPIProductCreate(t *testing.T) {
+	var body map[string]any
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// CSRF token fetch
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(content, &body)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	err := NewAPIProduct(exe).Create(&APIProductData{Name: "Orders", Title: "Orders", APIProxies: []string{"OrdersV1"}})
+	if err != nil {
+		t.Fatalf("Create failed with error - %v", err)
+	}
+	assert.Equal(t, "Orders", body["name"], "Incorrect product name in request body")
+	assert.Equal(t, []any{map[string]any{"__metadata": map[string]any{"uri": "APIProxies('OrdersV1')"}}}, body["apiProxies"], "Expected APIProxies linked by URI")
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type APIProvider struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// APIProviderData contains the connection details of the backend system of APIProxies
+type APIProviderData struct {
+	Name               string `json:"name"`
+	Description        string `json:"description,omitempty"`
+	Host               string `json:"host,omitempty"`
+	Port               *int   `json:"port,omitempty"`
+	UseSSL             bool   `json:"useSSL"`
+	IsOnPremise        bool   `json:"isOnPremise"`
+	DestinationName    string `json:"destinationName,omitempty"`
+	AuthenticationType string `json:"authenticationType,omitempty"`
+	SapClient          string `json:"sapClient,omitempty"`
+}
+
+func NewAPIProvider(exe *httpclnt.HTTPExecuter) *APIProvider {
+	a := new(APIProvider)
+	a.exe = exe
+	return a
+}
+
+func (a *APIProvider) List() ([]*APIProviderData, error) {
+	log.Info().Msgf("Getting list of APIProviders")
+	urlPath := "/apiportal/api/1.0/Management.svc/APIProviders"
+
+	return getAllResults[*APIProviderData](urlPath, nil, "List APIProviders", a.exe)
+}
+
+// Get returns the details of the API provider, or nil if it does not exist
+func (a *APIProvider) Get(name string) (*APIProviderData, error) {
+	log.Info().Msgf("Getting details of APIProvider %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProviders('%v')", name)
+
+	return getSingleResult[APIProviderData](urlPath, "Get APIProvider", a.exe)
+}
+
+func (a *APIProvider) Create(provider *APIProviderData) error {
+	log.Info().Msgf("Creating APIProvider %v", provider.Name)
+	urlPath := "/apiportal/api/1.0/Management.svc/APIProviders"
+
+	requestBody, err := json.Marshal(provider)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("POST", urlPath, requestBody, 201, "Create APIProvider", a.exe)
+}
+
+func (a *APIProvider) Update(provider *APIProviderData) error {
+	log.Info().Msgf("Updating APIProvider %v", provider.Name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProviders('%v')", provider.Name)
+
+	requestBody, err := json.Marshal(provider)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("PUT", urlPath, requestBody, 204, "Update APIProvider", a.exe)
+}
+
+func (a *APIProvider) Delete(name string) error {
+	log.Info().Msgf("Deleting APIProvider %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProviders('%v')", name)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete APIProvider", a.exe)
+}
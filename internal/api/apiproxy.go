@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -31,14 +32,10 @@ type APIEntity struct {
 	Name string `json:"name"`
 }
 
-type apiProxyResponseData struct {
-	Root struct {
-		Results []struct {
-			Name    string `json:"name"`
-			Version string `json:"version"`
-			Status  string `json:"state"`
-		} `json:"results"`
-	} `json:"d"`
+type apiProxyResult struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Status  string `json:"state"`
 }
 
 type APIProxyMetadata struct {
@@ -140,29 +137,47 @@ func (a *APIProxy) Get(id string) (bool, error) {
 	return true, nil
 }
 
+// GetMetadata returns the metadata of the APIProxy including the deployment state, or nil if it does not exist
+func (a *APIProxy) GetMetadata(id string) (*APIProxyMetadata, error) {
+	log.Debug().Msgf("Getting metadata of APIProxy %v", id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/APIProxies('%v')", id)
+
+	result, err := getSingleResult[apiProxyResult](urlPath, "Get APIProxy", a.exe)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return &APIProxyMetadata{
+		Name:    result.Name,
+		Version: result.Version,
+		Status:  result.Status,
+	}, nil
+}
+
+func (a *APIProxy) Deploy(id string) error {
+	log.Info().Msgf("Deploying APIProxy %v", id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/DeployAPIProxy?name='%v'", url.QueryEscape(id))
+	return modifyingCall("POST", urlPath, nil, 200, "Deploy APIProxy", a.exe)
+}
+
+func (a *APIProxy) Undeploy(id string) error {
+	log.Info().Msgf("Undeploying APIProxy %v", id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/UndeployAPIProxy?name='%v'", url.QueryEscape(id))
+	return modifyingCall("POST", urlPath, nil, 200, "Undeploy APIProxy", a.exe)
+}
+
 func (a *APIProxy) List() ([]*APIProxyMetadata, error) {
 	log.Info().Msgf("Getting list of APIProxies")
 	urlPath := "/apiportal/api/1.0/Management.svc/APIProxies"
 
 	callType := "List APIProxies"
-	resp, err := readOnlyCall(urlPath, callType, a.exe)
-	if err != nil {
-		return nil, err
-	}
-	// Process response to extract proxy details
-	var jsonData *apiProxyResponseData
-	respBody, err := a.exe.ReadRespBody(resp)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(respBody, &jsonData)
+	results, err := getAllResults[apiProxyResult](urlPath, nil, callType, a.exe)
 	if err != nil {
 		log.Warn().Msgf("⚠️ Please check that hostname and credentials for APIM are correct - do not use CPI values!")
-		log.Error().Msgf("Error unmarshalling response as JSON. Response body = %s", respBody)
-		return nil, errors.Wrap(err, 0)
+		return nil, err
 	}
+	// Process results to extract proxy details
 	var details []*APIProxyMetadata
-	for _, result := range jsonData.Root.Results {
+	for _, result := range results {
 		details = append(details, &APIProxyMetadata{
 			Name:    result.Name,
 			Version: result.Version,
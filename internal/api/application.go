@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type Application struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// ApplicationData contains the details of a developer application and the names of the APIProducts it subscribes to.
+// The application key and secret are generated by each tenant and are not included.
+type ApplicationData struct {
+	Id          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	DeveloperId string   `json:"developerId"`
+	CallbackUrl string   `json:"callbackurl,omitempty"`
+	APIProducts []string `json:"apiProducts,omitempty"`
+}
+
+type applicationResult struct {
+	ApplicationData
+	APIProducts navigationResults[*APIEntity] `json:"apiProducts"`
+}
+
+type applicationBody struct {
+	ApplicationData
+	APIProducts []*navigationLink `json:"apiProducts"`
+}
+
+func NewApplication(exe *httpclnt.HTTPExecuter) *Application {
+	a := new(Application)
+	a.exe = exe
+	return a
+}
+
+func (a *Application) List() ([]*ApplicationData, error) {
+	log.Info().Msgf("Getting list of Applications")
+	urlPath := "/apiportal/api/1.0/Management.svc/Applications"
+
+	results, err := getAllResults[*applicationResult](urlPath, &Query{Expand: "apiProducts"}, "List Applications", a.exe)
+	if err != nil {
+		return nil, err
+	}
+	var applications []*ApplicationData
+	for _, result := range results {
+		applications = append(applications, result.toData())
+	}
+	return applications, nil
+}
+
+// Get returns the details of the application, or nil if it does not exist
+func (a *Application) Get(id string) (*ApplicationData, error) {
+	log.Info().Msgf("Getting details of Application %v", id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Applications('%v')?$expand=apiProducts", id)
+
+	result, err := getSingleResult[applicationResult](urlPath, "Get Application", a.exe)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.toData(), nil
+}
+
+func (a *Application) Create(application *ApplicationData) error {
+	log.Info().Msgf("Creating Application %v", application.Id)
+	urlPath := "/apiportal/api/1.0/Management.svc/Applications"
+
+	requestBody, err := a.constructBody(application)
+	if err != nil {
+		return err
+	}
+	return modifyingCall("POST", urlPath, requestBody, 201, "Create Application", a.exe)
+}
+
+func (a *Application) Update(application *ApplicationData) error {
+	log.Info().Msgf("Updating Application %v", application.Id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Applications('%v')", application.Id)
+
+	requestBody, err := a.constructBody(application)
+	if err != nil {
+		return err
+	}
+	return modifyingCall("PUT", urlPath, requestBody, 204, "Update Application", a.exe)
+}
+
+func (a *Application) Delete(id string) error {
+	log.Info().Msgf("Deleting Application %v", id)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Applications('%v')", id)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete Application", a.exe)
+}
+
+func (a *Application) constructBody(application *ApplicationData) ([]byte, error) {
+	// The subscribed APIProducts are linked to the application with their URIs
+	body := &applicationBody{ApplicationData: *application, APIProducts: navigationLinks("APIProducts", application.APIProducts)}
+	requestBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return requestBody, nil
+}
+
+func (r *applicationResult) toData() *ApplicationData {
+	data := r.ApplicationData
+	data.APIProducts = nil
+	for _, product := range r.APIProducts.Results {
+		data.APIProducts = append(data.APIProducts, product.Name)
+	}
+	sort.Strings(data.APIProducts)
+	return &data
+}
@@ -46,7 +46,7 @@ func InitHeadersAndCookies(exe *httpclnt.HTTPExecuter) (headers map[string]strin
 	headers = map[string]string{}
 	cookies = []*http.Cookie{}
 
-	if exe.AuthType == "BASIC" {
+	if exe.AuthType == "BASIC" || exe.AuthType == "CERTIFICATE" {
 		csrf := NewCsrf(exe)
 		var token string
 		token, cookies, err = csrf.GetToken()
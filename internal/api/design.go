@@ -7,19 +7,19 @@ import (
 	"github.com/engswee/flashpipe/internal/httpclnt"
 	"github.com/go-errors/errors"
 	"github.com/rs/zerolog/log"
-	"os"
-	"path/filepath"
 )
 
 type DesigntimeArtifact interface {
-	Create(id string, name string, packageId string, artifactDir string) error
-	Update(id string, name string, packageId string, artifactDir string) error
+	Create(id string, name string, packageId string, content *file.Content) error
+	Update(id string, name string, packageId string, content *file.Content) error
 	Deploy(id string) error
 	Delete(id string) error
 	Get(id string, version string) (string, string, bool, error)
-	Download(targetFile string, id string) error
-	CopyContent(srcDir string, tgtDir string) error
-	CompareContent(srcDir string, tgtDir string, scriptMap []string, target string) (bool, error)
+	Download(id string) (*file.Content, error)
+	CopyContent(src *file.Content, tgt *file.Content)
+	CompareContent(src *file.Content, tgt *file.Content, rules []*file.BPMNRule, target string) (bool, error)
+	ContentHash(content *file.Content) string
+	DiffFiles(src *file.Content, tgt *file.Content, srcName string, tgtName string) ([]*file.FileDiff, error)
 }
 
 type designtimeArtifactData struct {
@@ -73,37 +73,31 @@ func constructUpdateBody(method string, id string, name string, packageId string
 	return requestBody, nil
 }
 
-func download(targetFile string, id string, artifactType string, exe *httpclnt.HTTPExecuter) error {
+func download(id string, artifactType string, exe *httpclnt.HTTPExecuter) (*file.Content, error) {
 	log.Info().Msgf("Getting content of artifact %v from tenant for comparison", id)
-	content, err := getContent(id, "active", artifactType, exe)
+	data, err := getContent(id, "active", artifactType, exe)
 	if err != nil {
-		return err
-	}
-
-	// Create directory for target file if it doesn't exist yet
-	err = os.MkdirAll(filepath.Dir(targetFile), os.ModePerm)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = os.WriteFile(targetFile, content, os.ModePerm)
+	content, err := file.ReadZipContent(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	log.Info().Msgf("Content of artifact %v downloaded to %v", id, targetFile)
-	return nil
+	log.Info().Msgf("Content of artifact %v downloaded with %d file(s)", id, len(content.Paths()))
+	return content, nil
 }
 
-func create(id string, name string, packageId string, artifactDir string, artifactType string, exe *httpclnt.HTTPExecuter) error {
+func create(id string, name string, packageId string, content *file.Content, artifactType string, exe *httpclnt.HTTPExecuter) error {
 	log.Info().Msgf("Creating %v designtime artifact %v", artifactType, id)
 	urlPath := fmt.Sprintf("/api/v1/%vDesigntimeArtifacts", artifactType)
-	return upsert(id, name, packageId, artifactDir, "POST", urlPath, 201, artifactType, "Create", exe)
+	return upsert(id, name, packageId, content, "POST", urlPath, 201, artifactType, "Create", exe)
 }
 
-func update(id string, name string, packageId string, artifactDir string, artifactType string, exe *httpclnt.HTTPExecuter) error {
+func update(id string, name string, packageId string, content *file.Content, artifactType string, exe *httpclnt.HTTPExecuter) error {
 	log.Info().Msgf("Updating %v designtime artifact %v", artifactType, id)
 	urlPath := fmt.Sprintf("/api/v1/%vDesigntimeArtifacts(Id='%v',Version='active')", artifactType, id)
-	return upsert(id, name, packageId, artifactDir, "PUT", urlPath, 200, artifactType, "Update", exe)
+	return upsert(id, name, packageId, content, "PUT", urlPath, 200, artifactType, "Update", exe)
 }
 
 func deploy(id string, artifactType string, exe *httpclnt.HTTPExecuter) error {
@@ -118,9 +112,9 @@ func deleteCall(id string, artifactType string, exe *httpclnt.HTTPExecuter) erro
 	return modifyingCall("DELETE", urlPath, nil, 200, fmt.Sprintf("Delete %v designtime artifact", artifactType), exe)
 }
 
-func upsert(id string, name string, packageId string, artifactDir string, method string, urlPath string, successCode int, artifactType string, callType string, exe *httpclnt.HTTPExecuter) error {
-	// Zip directory and encode to base64
-	encoded, err := file.ZipDirToBase64(artifactDir)
+func upsert(id string, name string, packageId string, content *file.Content, method string, urlPath string, successCode int, artifactType string, callType string, exe *httpclnt.HTTPExecuter) error {
+	// Zip content and encode to base64
+	encoded, err := content.ZipBase64()
 	if err != nil {
 		return err
 	}
@@ -172,42 +166,44 @@ func getContent(id string, version string, artifactType string, exe *httpclnt.HT
 	return exe.ReadRespBody(resp)
 }
 
-func diffContent(firstDir string, secondDir string) bool {
+func diffContent(first *file.Content, second *file.Content) bool {
 	log.Info().Msg("Checking for changes in META-INF directory")
-	metaDiffer := file.DiffDirectories(firstDir+"/META-INF", secondDir+"/META-INF")
+	metaDiffer := file.DiffContent(first, second, "META-INF")
 	log.Info().Msg("Checking for changes in src/main/resources directory")
-	resourcesDiffer := file.DiffDirectories(firstDir+"/src/main/resources", secondDir+"/src/main/resources")
+	resourcesDiffer := file.DiffContent(first, second, "src/main/resources")
 	log.Info().Msg("Checking for changes in metainfo.prop")
-	metainfoDiffer := DiffOptionalFile(firstDir, secondDir, "metainfo.prop")
+	metainfoDiffer := DiffOptionalFile(first, second, "metainfo.prop")
 
 	return metaDiffer || resourcesDiffer || metainfoDiffer
 }
 
-func copyContent(srcDir string, tgtDir string) error {
+func copyContent(src *file.Content, tgt *file.Content) {
 	// Copy META-INF and /src/main/resources separately so that other directories like QA, STG, PRD not copied
-	err := file.ReplaceDir(srcDir+"/META-INF", tgtDir+"/META-INF")
-	if err != nil {
-		return err
-	}
-	err = file.ReplaceDir(srcDir+"/src/main/resources", tgtDir+"/src/main/resources")
-	if err != nil {
-		return err
-	}
+	tgt.Replace(src, "META-INF")
+	tgt.Replace(src, "src/main/resources")
 	// Copy also metainfo.prop that contains the description if it is available
-	if file.Exists(srcDir + "/metainfo.prop") {
-		err = file.CopyFile(srcDir+"/metainfo.prop", tgtDir+"/metainfo.prop")
-		if err != nil {
-			return err
-		}
+	if src.Exists("metainfo.prop") {
+		tgt.Replace(src, "metainfo.prop")
 	}
-	return nil
-}
-func DiffOptionalFile(srcDir string, tgtDir string, fileRelativePath string) bool {
-	downloadedFile := fmt.Sprintf("%v/%v", srcDir, fileRelativePath)
-	gitFile := fmt.Sprintf("%v/%v", tgtDir, fileRelativePath)
-	if file.Exists(downloadedFile) && file.Exists(gitFile) {
-		return file.DiffFile(downloadedFile, gitFile)
-	} else if !file.Exists(downloadedFile) && !file.Exists(gitFile) {
+}
+
+// comparedPaths are the files and directories that are compared in diffContent
+var comparedPaths = []string{"META-INF", "src/main/resources", "metainfo.prop"}
+
+// contentHash returns the hash of the same files that are compared in diffContent
+func contentHash(content *file.Content) string {
+	return file.ContentHash(content, comparedPaths...)
+}
+
+// diffFiles returns the differences of the same files that are compared in diffContent
+func diffFiles(src *file.Content, tgt *file.Content, srcName string, tgtName string) ([]*file.FileDiff, error) {
+	return file.DiffContentFiles(src, tgt, srcName, tgtName, comparedPaths...)
+}
+
+func DiffOptionalFile(src *file.Content, tgt *file.Content, fileRelativePath string) bool {
+	if src.Exists(fileRelativePath) && tgt.Exists(fileRelativePath) {
+		return file.DiffContentFile(src, tgt, fileRelativePath)
+	} else if !src.Exists(fileRelativePath) && !tgt.Exists(fileRelativePath) {
 		log.Warn().Msgf("Skipping diff of %v as it does not exist in both source and target", fileRelativePath)
 		return false
 	}
@@ -82,11 +82,6 @@ func (suite *DesigntimeSuite) TearDownSuite() {
 	for _, value := range suite.artifacts {
 		tearDownRuntime(suite.T(), value, suite.exe)
 	}
-
-	err := os.RemoveAll("../../output/download")
-	if err != nil {
-		suite.T().Logf("WARNING - Directory removal failed with error - %v", err)
-	}
 	println("========== Tearing down suite - end ==========")
 }
 
@@ -99,7 +94,7 @@ func (suite *DesigntimeSuite) Test_CreateUpdateDeployDelete() {
 
 func createUpdateDeployDelete(id string, name string, packageId string, dt DesigntimeArtifact, artifactType string, t *testing.T) {
 	// Create
-	err := dt.Create(id, name, packageId, fmt.Sprintf("../../test/testdata/artifacts/create/%v", id))
+	err := dt.Create(id, name, packageId, readContent(t, fmt.Sprintf("../../test/testdata/artifacts/create/%v", id)))
 	if err != nil {
 		t.Fatalf("Create failed with error - %v", err)
 	}
@@ -111,7 +106,7 @@ func createUpdateDeployDelete(id string, name string, packageId string, dt Desig
 	assert.Equal(t, fmt.Sprintf("%v Created", artifactType), artifactDescription, "Artifact has incorrect description")
 	if assert.True(t, artifactExists, "Expected exists = true") {
 		// Update
-		err = dt.Update(id, name, packageId, fmt.Sprintf("../../test/testdata/artifacts/update/%v", id))
+		err = dt.Update(id, name, packageId, readContent(t, fmt.Sprintf("../../test/testdata/artifacts/update/%v", id)))
 		if err != nil {
 			t.Fatalf("Update failed with error - %v", err)
 		}
@@ -128,12 +123,11 @@ func createUpdateDeployDelete(id string, name string, packageId string, dt Desig
 				t.Fatalf("Deploy failed with error - %v", err)
 			}
 			// Download
-			targetFile := fmt.Sprintf("../../output/download/%v.zip", id)
-			err = dt.Download(targetFile, id)
+			content, err := dt.Download(id)
 			if err != nil {
 				t.Fatalf("Download failed with error - %v", err)
 			}
-			assert.Truef(t, file.Exists(targetFile), "Target file %v not found", targetFile)
+			assert.True(t, content.Exists("META-INF/MANIFEST.MF"), "MANIFEST.MF missing in downloaded content")
 			// Delete
 			err = dt.Delete(id)
 			if err != nil {
@@ -157,35 +151,35 @@ func TestDesigntime_Compare(t *testing.T) {
 		dt := NewDesigntimeArtifact(key, exe)
 		compare(value, dt, t)
 	}
-
-	err := os.RemoveAll("../../output/download")
-	if err != nil {
-		t.Fatalf("Directory removal failed with error - %v", err)
-	}
 }
 func compare(id string, dt DesigntimeArtifact, t *testing.T) {
 	// Diff artifact content
-	srcDir := fmt.Sprintf("../../test/testdata/artifacts/update/%v", id)
-	tgtDir := fmt.Sprintf("../../test/testdata/artifacts/create/%v", id)
-	dirDiffer, err := dt.CompareContent(srcDir, tgtDir, nil, "git")
+	src := readContent(t, fmt.Sprintf("../../test/testdata/artifacts/update/%v", id))
+	tgt := readContent(t, fmt.Sprintf("../../test/testdata/artifacts/create/%v", id))
+	dirDiffer, err := dt.CompareContent(src, tgt, nil, "git")
 	if err != nil {
 		t.Fatalf("CompareContent failed with error - %v", err)
 	}
 	assert.True(t, dirDiffer, "Directory contents do not differ")
 
-	// Copy to output folder
-	destinationDir := fmt.Sprintf("../../output/download/%v", id)
-	err = dt.CopyContent(srcDir, destinationDir)
-	if err != nil {
-		t.Fatalf("CopyContent failed with error - %v", err)
-	}
-	assert.True(t, file.Exists(destinationDir+"/META-INF/MANIFEST.MF"), "MANIFEST.MF missing in destination")
+	// Copy to new content
+	destination := file.NewContent()
+	dt.CopyContent(src, destination)
+	assert.True(t, destination.Exists("META-INF/MANIFEST.MF"), "MANIFEST.MF missing in destination")
 	switch dt.(type) {
 	case *Integration, *MessageMapping, *ScriptCollection:
-		assert.True(t, file.Exists(destinationDir+"/src/main/resources"), "/src/main/resources missing in destination")
+		assert.True(t, destination.Exists("src/main/resources"), "/src/main/resources missing in destination")
 	case *ValueMapping:
-		assert.True(t, file.Exists(destinationDir+"/value_mapping.xml"), "value_mapping.xml missing in destination")
+		assert.True(t, destination.Exists("value_mapping.xml"), "value_mapping.xml missing in destination")
+	}
+}
+
+func readContent(t *testing.T, dir string) *file.Content {
+	content, err := file.ReadDirContent(dir)
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
 	}
+	return content
 }
 
 func setupArtifact(t *testing.T, artifactId string, packageId string, artifactDir string, artifactType string, exe *httpclnt.HTTPExecuter) {
@@ -196,7 +190,7 @@ func setupArtifact(t *testing.T, artifactId string, packageId string, artifactDi
 		t.Logf("WARNING - Exists failed with error - %v", err)
 	}
 	if !artifactExists {
-		err = dt.Create(artifactId, artifactId, packageId, artifactDir)
+		err = dt.Create(artifactId, artifactId, packageId, readContent(t, artifactDir))
 		if err != nil {
 			t.Logf("WARNING - Create designtime artifact failed with error - %v", err)
 		}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type Developer struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// DeveloperData contains the details of a developer that owns applications
+type DeveloperData struct {
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	UserName  string `json:"userName,omitempty"`
+}
+
+func NewDeveloper(exe *httpclnt.HTTPExecuter) *Developer {
+	d := new(Developer)
+	d.exe = exe
+	return d
+}
+
+func (d *Developer) List() ([]*DeveloperData, error) {
+	log.Info().Msgf("Getting list of Developers")
+	urlPath := "/apiportal/api/1.0/Management.svc/Developers"
+
+	return getAllResults[*DeveloperData](urlPath, nil, "List Developers", d.exe)
+}
+
+// Get returns the details of the developer, or nil if it does not exist
+func (d *Developer) Get(email string) (*DeveloperData, error) {
+	log.Info().Msgf("Getting details of Developer %v", email)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Developers('%v')", email)
+
+	return getSingleResult[DeveloperData](urlPath, "Get Developer", d.exe)
+}
+
+func (d *Developer) Create(developer *DeveloperData) error {
+	log.Info().Msgf("Creating Developer %v", developer.Email)
+	urlPath := "/apiportal/api/1.0/Management.svc/Developers"
+
+	requestBody, err := json.Marshal(developer)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("POST", urlPath, requestBody, 201, "Create Developer", d.exe)
+}
+
+func (d *Developer) Update(developer *DeveloperData) error {
+	log.Info().Msgf("Updating Developer %v", developer.Email)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Developers('%v')", developer.Email)
+
+	requestBody, err := json.Marshal(developer)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("PUT", urlPath, requestBody, 204, "Update Developer", d.exe)
+}
+
+func (d *Developer) Delete(email string) error {
+	log.Info().Msgf("Deleting Developer %v", email)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/Developers('%v')", email)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete Developer", d.exe)
+}
@@ -88,7 +88,9 @@ func TestIntegration_DeployMockOauth(t *testing.T) {
 }
 
 func TestIntegration_diffParam(t *testing.T) {
-	dirDiffer := DiffOptionalFile("../../test/testdata/artifacts/collection/IFlow1", "../../test/testdata/artifacts/update/Integration_Test_IFlow", "src/main/resources/parameters.prop")
+	src := readContent(t, "../../test/testdata/artifacts/collection/IFlow1")
+	tgt := readContent(t, "../../test/testdata/artifacts/update/Integration_Test_IFlow")
+	dirDiffer := DiffOptionalFile(src, tgt, "src/main/resources/parameters.prop")
 
 	assert.True(t, dirDiffer, "Directory contents do not differ")
 }
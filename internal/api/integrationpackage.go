@@ -28,25 +28,24 @@ type PackageSingleData struct {
 		Countries      string `json:"Countries,omitempty"`
 		Industries     string `json:"Industries,omitempty"`
 		LineOfBusiness string `json:"LineOfBusiness,omitempty"`
+		// Fields provided by newer versions of the API
+		SupportedPlatform string `json:"SupportedPlatform,omitempty"`
+		CreatedBy         string `json:"CreatedBy,omitempty"`
+		CreationDate      string `json:"CreationDate,omitempty"`
+		ModifiedBy        string `json:"ModifiedBy,omitempty"`
+		ModifiedDate      string `json:"ModifiedDate,omitempty"`
 	} `json:"d"`
 }
 
-type artifactData struct {
-	Root struct {
-		Results []struct {
-			Id      string `json:"Id"`
-			Name    string `json:"Name"`
-			Version string `json:"Version"`
-		} `json:"results"`
-	} `json:"d"`
+type artifactResult struct {
+	Id         string `json:"Id"`
+	Name       string `json:"Name"`
+	Version    string `json:"Version"`
+	ModifiedAt string `json:"ModifiedAt"`
 }
 
-type packageMultipleData struct {
-	Root struct {
-		Results []struct {
-			Id string `json:"Id"`
-		} `json:"results"`
-	} `json:"d"`
+type packageResult struct {
+	Id string `json:"Id"`
 }
 
 type ArtifactDetails struct {
@@ -55,6 +54,7 @@ type ArtifactDetails struct {
 	IsDraft      bool
 	Version      string
 	ArtifactType string
+	ModifiedAt   string
 }
 
 // NewIntegrationPackage returns an initialised IntegrationPackage instance.
@@ -64,29 +64,20 @@ func NewIntegrationPackage(exe *httpclnt.HTTPExecuter) *IntegrationPackage {
 	return ip
 }
 
-func (ip *IntegrationPackage) GetPackagesList() ([]string, error) {
+// GetPackagesList returns the IDs of the packages of the tenant. The query is optional and can be used
+// to filter the packages on the server side.
+func (ip *IntegrationPackage) GetPackagesList(query *Query) ([]string, error) {
 	// Get the list of packages of the current tenant
 	log.Info().Msg("Getting list of IntegrationPackages")
 	urlPath := "/api/v1/IntegrationPackages"
 
 	callType := "Get IntegrationPackages list"
-	resp, err := readOnlyCall(urlPath, callType, ip.exe)
+	results, err := getAllResults[packageResult](urlPath, query, callType, ip.exe)
 	if err != nil {
 		return nil, err
 	}
-	// Process response to extract packages
-	var jsonData *packageMultipleData
-	respBody, err := ip.exe.ReadRespBody(resp)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(respBody, &jsonData)
-	if err != nil {
-		log.Error().Msgf("Error unmarshalling response as JSON. Response body = %s", respBody)
-		return nil, errors.Wrap(err, 0)
-	}
 	var packageIds []string
-	for _, result := range jsonData.Root.Results {
+	for _, result := range results {
 		packageIds = append(packageIds, result.Id)
 	}
 	return packageIds, nil
@@ -126,23 +117,13 @@ func (ip *IntegrationPackage) GetArtifactsData(id string, artifactType string) (
 	urlPath := fmt.Sprintf("/api/v1/IntegrationPackages('%v')/%vDesigntimeArtifacts", id, artifactType)
 
 	callType := fmt.Sprintf("Get %v designtime artifacts of IntegrationPackages", artifactType)
-	resp, err := readOnlyCall(urlPath, callType, ip.exe)
+	results, err := getAllResults[artifactResult](urlPath, nil, callType, ip.exe)
 	if err != nil {
 		return nil, err
 	}
-	// Process response to extract artifact details
-	var jsonData *artifactData
-	respBody, err := ip.exe.ReadRespBody(resp)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(respBody, &jsonData)
-	if err != nil {
-		log.Error().Msgf("Error unmarshalling response as JSON. Response body = %s", respBody)
-		return nil, errors.Wrap(err, 0)
-	}
+	// Process results to extract artifact details
 	var details []*ArtifactDetails
-	for _, result := range jsonData.Root.Results {
+	for _, result := range results {
 		var draft bool
 		if result.Version == "Active" {
 			draft = true
@@ -155,6 +136,7 @@ func (ip *IntegrationPackage) GetArtifactsData(id string, artifactType string) (
 			IsDraft:      draft,
 			Version:      result.Version,
 			ArtifactType: artifactType,
+			ModifiedAt:   result.ModifiedAt,
 		})
 	}
 	return details, nil
@@ -219,8 +201,13 @@ func (ip *IntegrationPackage) Delete(packageId string) error {
 }
 
 func (ip *IntegrationPackage) constructBody(packageData *PackageSingleData) ([]byte, error) {
-	// Clear Mode field as it is not allowed in create/update
+	// Clear Mode and administrative fields as they are not allowed in create/update
 	packageData.Root.Mode = ""
+	packageData.Root.SupportedPlatform = ""
+	packageData.Root.CreatedBy = ""
+	packageData.Root.CreationDate = ""
+	packageData.Root.ModifiedBy = ""
+	packageData.Root.ModifiedDate = ""
 
 	requestBody, err := json.Marshal(packageData)
 	if err != nil {
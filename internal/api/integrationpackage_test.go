@@ -97,7 +97,7 @@ func (suite *PackageSuite) TestIntegrationPackage_CreateUpdateDelete() {
 	}
 
 	// Get list
-	packagesList, err := ip.GetPackagesList()
+	packagesList, err := ip.GetPackagesList(nil)
 	if err != nil {
 		suite.T().Fatalf("GetPackagesList failed with error - %v", err)
 	}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// redactedValue replaces the values of encrypted key value maps in the debug log
+const redactedValue = "********"
+
+type KeyValueMap struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// KeyValueMapData contains the entries of a key value map. Values of encrypted key value maps
+// cannot be read from the tenant and are always empty when retrieved.
+type KeyValueMapData struct {
+	Name      string              `json:"name"`
+	Encrypted bool                `json:"encrypted"`
+	Scope     string              `json:"scope,omitempty"`
+	Entries   []*KeyValueMapEntry `json:"keyMapEntryValues,omitempty"`
+}
+
+type KeyValueMapEntry struct {
+	Name    string `json:"name"`
+	MapName string `json:"map_name,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+type keyValueMapResult struct {
+	KeyValueMapData
+	Entries navigationResults[*KeyValueMapEntry] `json:"keyMapEntryValues"`
+}
+
+func NewKeyValueMap(exe *httpclnt.HTTPExecuter) *KeyValueMap {
+	k := new(KeyValueMap)
+	k.exe = exe
+	return k
+}
+
+func (k *KeyValueMap) List() ([]*KeyValueMapData, error) {
+	log.Info().Msgf("Getting list of KeyValueMaps")
+	urlPath := "/apiportal/api/1.0/Management.svc/KeyMapEntries"
+
+	results, err := getAllResults[*keyValueMapResult](urlPath, &Query{Expand: "keyMapEntryValues"}, "List KeyValueMaps", k.exe)
+	if err != nil {
+		return nil, err
+	}
+	var kvms []*KeyValueMapData
+	for _, result := range results {
+		kvms = append(kvms, result.toData())
+	}
+	return kvms, nil
+}
+
+// Get returns the key value map, or nil if it does not exist
+func (k *KeyValueMap) Get(name string) (*KeyValueMapData, error) {
+	log.Info().Msgf("Getting details of KeyValueMap %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/KeyMapEntries('%v')?$expand=keyMapEntryValues", name)
+
+	result, err := getSingleResult[keyValueMapResult](urlPath, "Get KeyValueMap", k.exe)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.toData(), nil
+}
+
+func (k *KeyValueMap) Create(kvm *KeyValueMapData) error {
+	log.Info().Msgf("Creating KeyValueMap %v", kvm.Name)
+	urlPath := "/apiportal/api/1.0/Management.svc/KeyMapEntries"
+
+	for _, entry := range kvm.Entries {
+		entry.MapName = kvm.Name
+	}
+	return k.call("POST", urlPath, kvm, kvm.redacted(), 201, "Create KeyValueMap")
+}
+
+// Update creates, updates and deletes the entries of the key value map in the tenant so that they match
+// the entries of kvm. Entries of encrypted key value maps are always updated as their values cannot be compared.
+func (k *KeyValueMap) Update(kvm *KeyValueMapData) error {
+	log.Info().Msgf("Updating KeyValueMap %v", kvm.Name)
+
+	tenantKvm, err := k.Get(kvm.Name)
+	if err != nil {
+		return err
+	}
+	if tenantKvm == nil {
+		return fmt.Errorf("KeyValueMap %v does not exist", kvm.Name)
+	}
+	tenantEntries := map[string]*KeyValueMapEntry{}
+	for _, entry := range tenantKvm.Entries {
+		tenantEntries[entry.Name] = entry
+	}
+
+	for _, entry := range kvm.Entries {
+		entry.MapName = kvm.Name
+		redactedEntry := entry
+		if kvm.Encrypted {
+			redactedEntry = entry.redacted()
+		}
+		tenantEntry, exists := tenantEntries[entry.Name]
+		delete(tenantEntries, entry.Name)
+		if !exists {
+			log.Debug().Msgf("Creating entry %v of KeyValueMap %v", entry.Name, kvm.Name)
+			err = k.call("POST", "/apiportal/api/1.0/Management.svc/KeyMapEntryValues", entry, redactedEntry, 201, "Create KeyValueMap entry")
+		} else if kvm.Encrypted || tenantEntry.Value != entry.Value {
+			log.Debug().Msgf("Updating entry %v of KeyValueMap %v", entry.Name, kvm.Name)
+			err = k.call("PUT", entryPath(kvm.Name, entry.Name), entry, redactedEntry, 204, "Update KeyValueMap entry")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for name := range tenantEntries {
+		log.Debug().Msgf("Deleting entry %v of KeyValueMap %v", name, kvm.Name)
+		err = modifyingCall("DELETE", entryPath(kvm.Name, name), nil, 204, "Delete KeyValueMap entry", k.exe)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KeyValueMap) Delete(name string) error {
+	log.Info().Msgf("Deleting KeyValueMap %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/KeyMapEntries('%v')", name)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete KeyValueMap", k.exe)
+}
+
+// call executes the modifying call with request body data. The request body is logged from redactedData, so that
+// values of encrypted key value maps are not written to the debug log.
+func (k *KeyValueMap) call(method string, urlPath string, data any, redactedData any, successCode int, callType string) error {
+	requestBody, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	redactedBody, err := json.Marshal(redactedData)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCallWithRedactedBody(method, urlPath, requestBody, redactedBody, successCode, callType, k.exe)
+}
+
+// redacted returns a copy of the key value map in which the values are redacted if the key value map is encrypted
+func (kvm *KeyValueMapData) redacted() *KeyValueMapData {
+	if !kvm.Encrypted {
+		return kvm
+	}
+	redacted := *kvm
+	redacted.Entries = nil
+	for _, entry := range kvm.Entries {
+		redacted.Entries = append(redacted.Entries, entry.redacted())
+	}
+	return &redacted
+}
+
+// redacted returns a copy of the entry in which the value is redacted
+func (e *KeyValueMapEntry) redacted() *KeyValueMapEntry {
+	redacted := *e
+	if redacted.Value != "" {
+		redacted.Value = redactedValue
+	}
+	return &redacted
+}
+
+func entryPath(mapName string, entryName string) string {
+	return fmt.Sprintf("/apiportal/api/1.0/Management.svc/KeyMapEntryValues(map_name='%v',name='%v')", mapName, entryName)
+}
+
+func (r *keyValueMapResult) toData() *KeyValueMapData {
+	data := r.KeyValueMapData
+	data.Entries = nil
+	for _, entry := range r.Entries.Results {
+		e := &KeyValueMapEntry{Name: entry.Name}
+		// Values of encrypted key value maps are never exposed, regardless of the content returned by the tenant
+		if !data.Encrypted {
+			e.Value = entry.Value
+		}
+		data.Entries = append(data.Entries, e)
+	}
+	sort.Slice(data.Entries, func(i, j int) bool {
+		return data.Entries[i].Name < data.Entries[j].Name
+	})
+	return &data
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyValueMapGetEncrypted(t *testing.T) {
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"d":{"name":"Credentials","encrypted":true,"scope":"ENV","keyMapEntryValues":{"results":[{"name":"password","map_name":"Credentials","value":"secret"},{"name":"clientId","map_name":"Credentials","value":"id"}]}}}`)
+	})
+
+	kvm, err := NewKeyValueMap(exe).Get("Credentials")
+	if err != nil {
+		t.Fatalf("Get failed with error - %v", err)
+	}
+	assert.Equal(t, []*KeyValueMapEntry{{Name: "clientId"}, {Name: "password"}}, kvm.Entries, "Expected sorted entries without values")
+}
+
+func TestKeyValueMapSecretsNotLogged(t *testing.T) {
+	var logs bytes.Buffer
+	logger, level := log.Logger, zerolog.GlobalLevel()
+	log.Logger = zerolog.New(&logs)
+	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	t.Cleanup(func() {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	})
+
+	var bodies []string
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "KeyMapEntries"):
+			fmt.Fprint(w, `{"d":{"name":"Credentials","encrypted":true,"keyMapEntryValues":{"results":[{"name":"password","map_name":"Credentials"}]}}}`)
+		case r.Method == http.MethodGet:
+			// CSRF token fetch
+		case r.Method == http.MethodPost:
+			content, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(content))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			content, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(content))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	kvm := NewKeyValueMap(exe)
+	err := kvm.Create(&KeyValueMapData{Name: "Credentials", Encrypted: true, Entries: []*KeyValueMapEntry{{Name: "password", Value: "topsecret1"}}})
+	if err != nil {
+		t.Fatalf("Create failed with error - %v", err)
+	}
+	err = kvm.Update(&KeyValueMapData{Name: "Credentials", Encrypted: true, Entries: []*KeyValueMapEntry{{Name: "password", Value: "topsecret2"}, {Name: "clientId", Value: "topsecret3"}}})
+	if err != nil {
+		t.Fatalf("Update failed with error - %v", err)
+	}
+
+	if assert.Len(t, bodies, 3, "Expected create of map, update and create of entries") {
+		assert.Contains(t, bodies[0], "topsecret1", "Expected secret in request body")
+		assert.Contains(t, bodies[1], "topsecret2", "Expected secret in request body")
+		assert.Contains(t, bodies[2], "topsecret3", "Expected secret in request body")
+	}
+	assert.Contains(t, logs.String(), "Request body", "Expected request body logged")
+	assert.NotContains(t, logs.String(), "topsecret", "Expected secrets redacted in log")
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"github.com/engswee/flashpipe/internal/file"
 	"github.com/engswee/flashpipe/internal/httpclnt"
 )
 
@@ -17,11 +18,11 @@ func NewMessageMapping(exe *httpclnt.HTTPExecuter) DesigntimeArtifact {
 	return mm
 }
 
-func (mm *MessageMapping) Create(id string, name string, packageId string, artifactDir string) error {
-	return create(id, name, packageId, artifactDir, mm.typ, mm.exe)
+func (mm *MessageMapping) Create(id string, name string, packageId string, content *file.Content) error {
+	return create(id, name, packageId, content, mm.typ, mm.exe)
 }
-func (mm *MessageMapping) Update(id string, name string, packageId string, artifactDir string) (err error) {
-	return update(id, name, packageId, artifactDir, mm.typ, mm.exe)
+func (mm *MessageMapping) Update(id string, name string, packageId string, content *file.Content) (err error) {
+	return update(id, name, packageId, content, mm.typ, mm.exe)
 }
 func (mm *MessageMapping) Deploy(id string) (err error) {
 	return deploy(id, mm.typ, mm.exe)
@@ -32,13 +33,19 @@ func (mm *MessageMapping) Delete(id string) (err error) {
 func (mm *MessageMapping) Get(id string, version string) (string, string, bool, error) {
 	return get(id, version, mm.typ, mm.exe)
 }
-func (mm *MessageMapping) Download(targetFile string, id string) error {
-	return download(targetFile, id, mm.typ, mm.exe)
+func (mm *MessageMapping) Download(id string) (*file.Content, error) {
+	return download(id, mm.typ, mm.exe)
 }
-func (mm *MessageMapping) CopyContent(srcDir string, tgtDir string) error {
-	return copyContent(srcDir, tgtDir)
+func (mm *MessageMapping) CopyContent(src *file.Content, tgt *file.Content) {
+	copyContent(src, tgt)
 }
-func (mm *MessageMapping) CompareContent(srcDir string, tgtDir string, _ []string, _ string) (bool, error) {
-	// Diff directories
-	return diffContent(srcDir, tgtDir), nil
+func (mm *MessageMapping) CompareContent(src *file.Content, tgt *file.Content, _ []*file.BPMNRule, _ string) (bool, error) {
+	// Diff contents
+	return diffContent(src, tgt), nil
+}
+func (mm *MessageMapping) ContentHash(content *file.Content) string {
+	return contentHash(content)
+}
+func (mm *MessageMapping) DiffFiles(src *file.Content, tgt *file.Content, srcName string, tgtName string) ([]*file.FileDiff, error) {
+	return diffFiles(src, tgt, srcName, tgtName)
 }
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Query contains the OData system query options for list calls
+type Query struct {
+	Filter string
+	Select string
+	Expand string
+	// Page size for client-side paging. When not set, only server-side paging (__next) is followed
+	Top  int
+	Skip int
+}
+
+type listResponseData[T any] struct {
+	Root struct {
+		Results []T    `json:"results"`
+		Next    string `json:"__next"`
+	} `json:"d"`
+}
+
+// URLPath returns the URL path of the collection with the query options
+func (q *Query) URLPath(collectionPath string) string {
+	if q == nil {
+		return collectionPath
+	}
+	var options []string
+	add := func(option string, value string) {
+		if value != "" {
+			// Spaces are common in $filter, encode them as %20 instead of +
+			options = append(options, fmt.Sprintf("%v=%v", option, strings.ReplaceAll(url.QueryEscape(value), "+", "%20")))
+		}
+	}
+	add("$filter", q.Filter)
+	add("$select", q.Select)
+	add("$expand", q.Expand)
+	if q.Top > 0 {
+		add("$top", fmt.Sprint(q.Top))
+	}
+	if q.Skip > 0 {
+		add("$skip", fmt.Sprint(q.Skip))
+	}
+	if len(options) == 0 {
+		return collectionPath
+	}
+	separator := "?"
+	if strings.Contains(collectionPath, "?") {
+		separator = "&"
+	}
+	return collectionPath + separator + strings.Join(options, "&")
+}
+
+// getAllResults executes the list call and follows the server-side paging links (__next) or
+// pages with $skip/$top until all results of the collection are retrieved
+func getAllResults[T any](collectionPath string, query *Query, callType string, exe *httpclnt.HTTPExecuter) ([]T, error) {
+	var pageQuery Query
+	if query != nil {
+		pageQuery = *query
+	}
+	urlPath := pageQuery.URLPath(collectionPath)
+
+	var results []T
+	for page := 1; ; page++ {
+		log.Debug().Msgf("Getting page %d of %v", page, callType)
+		resp, err := readOnlyCall(urlPath, callType, exe)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := exe.ReadRespBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		var jsonData *listResponseData[T]
+		err = json.Unmarshal(respBody, &jsonData)
+		if err != nil {
+			log.Error().Msgf("Error unmarshalling response as JSON. Response body = %s", respBody)
+			return nil, errors.Wrap(err, 0)
+		}
+		results = append(results, jsonData.Root.Results...)
+
+		if jsonData.Root.Next != "" {
+			urlPath, err = resolveNextLink(urlPath, jsonData.Root.Next)
+			if err != nil {
+				return nil, err
+			}
+		} else if pageQuery.Top > 0 && len(jsonData.Root.Results) == pageQuery.Top {
+			pageQuery.Skip += pageQuery.Top
+			urlPath = pageQuery.URLPath(collectionPath)
+		} else {
+			return results, nil
+		}
+	}
+}
+
+// resolveNextLink returns the URL path of the __next link, which can be absolute or relative to the current request
+func resolveNextLink(currentPath string, next string) (string, error) {
+	current, err := url.Parse(currentPath)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	nextURL, err := url.Parse(next)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	resolved := current.ResolveReference(nextURL)
+	if resolved.RawQuery == "" {
+		return resolved.EscapedPath(), nil
+	}
+	return resolved.EscapedPath() + "?" + resolved.RawQuery, nil
+}
+
+// FilterEquals returns the $filter expression for a field with the value
+func FilterEquals(field string, value string) string {
+	return fmt.Sprintf("%v eq %v", field, quoteValue(value))
+}
+
+// FilterPattern returns the $filter expression for a field matching a pattern where * matches any characters
+// at the start and/or end of the value, e.g. Demo*, *Demo, *Demo*
+func FilterPattern(field string, pattern string) string {
+	prefix := strings.HasPrefix(pattern, "*")
+	suffix := strings.HasSuffix(pattern, "*") && len(pattern) > 1
+	value := strings.TrimSuffix(strings.TrimPrefix(pattern, "*"), "*")
+	switch {
+	case prefix && suffix:
+		return fmt.Sprintf("substringof(%v,%v)", quoteValue(value), field)
+	case prefix:
+		return fmt.Sprintf("endswith(%v,%v)", field, quoteValue(value))
+	case suffix:
+		return fmt.Sprintf("startswith(%v,%v)", field, quoteValue(value))
+	default:
+		return FilterEquals(field, value)
+	}
+}
+
+// FilterAnd combines the non-empty $filter expressions
+func FilterAnd(expressions ...string) string {
+	var nonEmpty []string
+	for _, expression := range expressions {
+		if expression != "" {
+			nonEmpty = append(nonEmpty, expression)
+		}
+	}
+	if len(nonEmpty) <= 1 {
+		return strings.Join(nonEmpty, "")
+	}
+	return "(" + strings.Join(nonEmpty, ") and (") + ")"
+}
+
+func quoteValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+type singleResponseData[T any] struct {
+	Root T `json:"d"`
+}
+
+// getSingleResult executes the read call of a single entity. If the entity does not exist, nil is returned
+func getSingleResult[T any](urlPath string, callType string, exe *httpclnt.HTTPExecuter) (*T, error) {
+	resp, err := readOnlyCall(urlPath, callType, exe)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("%v call failed with response code = 404", callType) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	respBody, err := exe.ReadRespBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	var jsonData *singleResponseData[T]
+	err = json.Unmarshal(respBody, &jsonData)
+	if err != nil {
+		log.Error().Msgf("Error unmarshalling response as JSON. Response body = %s", respBody)
+		return nil, errors.Wrap(err, 0)
+	}
+	return &jsonData.Root, nil
+}
+
+// navigationLinks returns the links to entities of the collection for deep insert/update of navigation properties
+func navigationLinks(collection string, keys []string) []*navigationLink {
+	links := []*navigationLink{}
+	for _, key := range keys {
+		link := new(navigationLink)
+		link.Metadata.URI = fmt.Sprintf("%v('%v')", collection, key)
+		links = append(links, link)
+	}
+	return links
+}
+
+type navigationLink struct {
+	Metadata struct {
+		URI string `json:"uri"`
+	} `json:"__metadata"`
+}
+
+// navigationResults contains the expanded entities of a navigation property
+type navigationResults[T any] struct {
+	Results []T `json:"results"`
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestExecuter(t *testing.T, handler http.HandlerFunc) *httpclnt.HTTPExecuter {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+	return httpclnt.New("", "", "", "", "user", "password", serverURL.Hostname(), "http", port, false)
+}
+
+func TestQueryURLPath(t *testing.T) {
+	query := &Query{Filter: "Vendor eq 'SAP'", Select: "Id,Name", Top: 100, Skip: 200}
+
+	assert.Equal(t, "/api/v1/IntegrationPackages?$filter=Vendor%20eq%20%27SAP%27&$select=Id%2CName&$top=100&$skip=200", query.URLPath("/api/v1/IntegrationPackages"), "Incorrect URL path")
+	assert.Equal(t, "/api/v1/IntegrationPackages", (*Query)(nil).URLPath("/api/v1/IntegrationPackages"), "Expected URL path without query")
+}
+
+func TestFilters(t *testing.T) {
+	assert.Equal(t, "Vendor eq 'O''Reilly'", FilterEquals("Vendor", "O'Reilly"), "Expected quotes escaped")
+	assert.Equal(t, "startswith(Name,'Demo')", FilterPattern("Name", "Demo*"), "Incorrect prefix pattern")
+	assert.Equal(t, "endswith(Name,'Demo')", FilterPattern("Name", "*Demo"), "Incorrect suffix pattern")
+	assert.Equal(t, "substringof('Demo',Name)", FilterPattern("Name", "*Demo*"), "Incorrect substring pattern")
+	assert.Equal(t, "Name eq 'Demo'", FilterPattern("Name", "Demo"), "Incorrect exact pattern")
+	assert.Equal(t, "(Vendor eq 'SAP') and (startswith(Name,'Demo'))", FilterAnd("Vendor eq 'SAP'", "", "startswith(Name,'Demo')"), "Incorrect combined filter")
+	assert.Equal(t, "Vendor eq 'SAP'", FilterAnd("", "Vendor eq 'SAP'"), "Expected single filter without brackets")
+}
+
+func TestGetAllResultsFollowsNextLink(t *testing.T) {
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$skiptoken") == "" {
+			fmt.Fprint(w, `{"d":{"results":[{"Id":"Package1"},{"Id":"Package2"}],"__next":"IntegrationPackages?$skiptoken=2"}}`)
+		} else {
+			fmt.Fprint(w, `{"d":{"results":[{"Id":"Package3"}]}}`)
+		}
+	})
+
+	results, err := getAllResults[packageResult]("/api/v1/IntegrationPackages", nil, "Get IntegrationPackages list", exe)
+	if err != nil {
+		t.Fatalf("getAllResults failed with error - %v", err)
+	}
+	assert.Equal(t, []packageResult{{Id: "Package1"}, {Id: "Package2"}, {Id: "Package3"}}, results, "Expected results of all pages")
+}
+
+func TestGetAllResultsClientPaging(t *testing.T) {
+	var skips []string
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		skips = append(skips, r.URL.Query().Get("$skip"))
+		assert.Equal(t, "Vendor eq 'SAP'", r.URL.Query().Get("$filter"), "Expected filter in every page")
+		if r.URL.Query().Get("$skip") == "" {
+			fmt.Fprint(w, `{"d":{"results":[{"Id":"Package1"},{"Id":"Package2"}]}}`)
+		} else {
+			fmt.Fprint(w, `{"d":{"results":[{"Id":"Package3"}]}}`)
+		}
+	})
+
+	results, err := getAllResults[packageResult]("/api/v1/IntegrationPackages", &Query{Filter: "Vendor eq 'SAP'", Top: 2}, "Get IntegrationPackages list", exe)
+	if err != nil {
+		t.Fatalf("getAllResults failed with error - %v", err)
+	}
+	assert.Equal(t, 3, len(results), "Expected number of results = 3")
+	assert.Equal(t, []string{"", "2"}, skips, "Expected second page with $skip=2")
+}
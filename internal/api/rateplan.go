@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type RatePlan struct {
+	exe *httpclnt.HTTPExecuter
+}
+
+// RatePlanData contains the details of a rate plan that is attached to API products for billing
+type RatePlanData struct {
+	Name          string   `json:"name"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+	BillingPeriod string   `json:"billingPeriod,omitempty"`
+	PricingType   string   `json:"pricingType,omitempty"`
+	BasicCharge   *float64 `json:"basicCharge,omitempty"`
+}
+
+func NewRatePlan(exe *httpclnt.HTTPExecuter) *RatePlan {
+	r := new(RatePlan)
+	r.exe = exe
+	return r
+}
+
+func (r *RatePlan) List() ([]*RatePlanData, error) {
+	log.Info().Msgf("Getting list of RatePlans")
+	urlPath := "/apiportal/api/1.0/Management.svc/RatePlans"
+
+	return getAllResults[*RatePlanData](urlPath, nil, "List RatePlans", r.exe)
+}
+
+// Get returns the details of the rate plan, or nil if it does not exist
+func (r *RatePlan) Get(name string) (*RatePlanData, error) {
+	log.Info().Msgf("Getting details of RatePlan %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/RatePlans('%v')", name)
+
+	return getSingleResult[RatePlanData](urlPath, "Get RatePlan", r.exe)
+}
+
+func (r *RatePlan) Create(ratePlan *RatePlanData) error {
+	log.Info().Msgf("Creating RatePlan %v", ratePlan.Name)
+	urlPath := "/apiportal/api/1.0/Management.svc/RatePlans"
+
+	requestBody, err := json.Marshal(ratePlan)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("POST", urlPath, requestBody, 201, "Create RatePlan", r.exe)
+}
+
+func (r *RatePlan) Update(ratePlan *RatePlanData) error {
+	log.Info().Msgf("Updating RatePlan %v", ratePlan.Name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/RatePlans('%v')", ratePlan.Name)
+
+	requestBody, err := json.Marshal(ratePlan)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return modifyingCall("PUT", urlPath, requestBody, 204, "Update RatePlan", r.exe)
+}
+
+func (r *RatePlan) Delete(name string) error {
+	log.Info().Msgf("Deleting RatePlan %v", name)
+	urlPath := fmt.Sprintf("/apiportal/api/1.0/Management.svc/RatePlans('%v')", name)
+	return modifyingCall("DELETE", urlPath, nil, 204, "Delete RatePlan", r.exe)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRatePlanGet(t *testing.T) {
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/apiportal/api/1.0/Management.svc/RatePlans('Gold')" {
+			fmt.Fprint(w, `{"d":{"__metadata":{},"name":"Gold","title":"Gold","currency":"EUR","billingPeriod":"MONTHLY","basicCharge":99.5}}`)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	ratePlan := NewRatePlan(exe)
+
+	details, err := ratePlan.Get("Gold")
+	if err != nil {
+		t.Fatalf("Get failed with error - %v", err)
+	}
+	assert.Equal(t, "Gold", details.Name, "Incorrect rate plan name")
+	assert.Equal(t, 99.5, *details.BasicCharge, "Incorrect basic charge")
+
+	details, err = ratePlan.Get("Unknown")
+	if err != nil {
+		t.Fatalf("Get failed with error - %v", err)
+	}
+	assert.Nil(t, details, "Expected nil for rate plan that does not exist")
+}
+
+func TestRatePlanCreate(t *testing.T) {
+	var body map[string]any
+	exe := newTestExecuter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// CSRF token fetch
+			return
+		}
+		content, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(content, &body)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	err := NewRatePlan(exe).Create(&RatePlanData{Name: "Gold", Title: "Gold", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("Create failed with error - %v", err)
+	}
+	assert.Equal(t, "Gold", body["name"], "Incorrect rate plan name in request body")
+	assert.Equal(t, "EUR", body["currency"], "Incorrect currency in request body")
+	assert.NotContains(t, body, "basicCharge", "Expected unset basic charge omitted")
+}
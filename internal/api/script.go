@@ -4,7 +4,6 @@ import (
 	"github.com/engswee/flashpipe/internal/file"
 	"github.com/engswee/flashpipe/internal/httpclnt"
 	"github.com/rs/zerolog/log"
-	"os"
 )
 
 type ScriptCollection struct {
@@ -20,11 +19,11 @@ func NewScriptCollection(exe *httpclnt.HTTPExecuter) DesigntimeArtifact {
 	return sc
 }
 
-func (sc *ScriptCollection) Create(id string, name string, packageId string, artifactDir string) error {
-	return create(id, name, packageId, artifactDir, sc.typ, sc.exe)
+func (sc *ScriptCollection) Create(id string, name string, packageId string, content *file.Content) error {
+	return create(id, name, packageId, content, sc.typ, sc.exe)
 }
-func (sc *ScriptCollection) Update(id string, name string, packageId string, artifactDir string) (err error) {
-	return update(id, name, packageId, artifactDir, sc.typ, sc.exe)
+func (sc *ScriptCollection) Update(id string, name string, packageId string, content *file.Content) (err error) {
+	return update(id, name, packageId, content, sc.typ, sc.exe)
 }
 func (sc *ScriptCollection) Deploy(id string) (err error) {
 	return deploy(id, sc.typ, sc.exe)
@@ -35,52 +34,39 @@ func (sc *ScriptCollection) Delete(id string) (err error) {
 func (sc *ScriptCollection) Get(id string, version string) (string, string, bool, error) {
 	return get(id, version, sc.typ, sc.exe)
 }
-func (sc *ScriptCollection) Download(targetFile string, id string) error {
-	return download(targetFile, id, sc.typ, sc.exe)
+func (sc *ScriptCollection) Download(id string) (*file.Content, error) {
+	return download(id, sc.typ, sc.exe)
 }
-func (sc *ScriptCollection) CopyContent(srcDir string, tgtDir string) error {
+func (sc *ScriptCollection) CopyContent(src *file.Content, tgt *file.Content) {
 	// Copy META-INF and /src/main/resources separately so that other directories like QA, STG, PRD not copied
-	err := file.ReplaceDir(srcDir+"/META-INF", tgtDir+"/META-INF")
-	if err != nil {
-		return err
-	}
-
-	// It is technically possible to have an empty script collection
-	if file.Exists(srcDir + "/src/main/resources") {
-		// As long as it exists in source, will copy/replace it in target
-		err = file.ReplaceDir(srcDir+"/src/main/resources", tgtDir+"/src/main/resources")
-		if err != nil {
-			return err
-		}
-	} else if file.Exists(tgtDir + "/src/main/resources") {
-		// If resources does not exist in source but exists in target, then remove target
-		err = os.RemoveAll(tgtDir + "/src/main/resources")
-		if err != nil {
-			return err
-		}
-	}
+	tgt.Replace(src, "META-INF")
+	// It is technically possible to have an empty script collection, in which case /src/main/resources is removed
+	// from target
+	tgt.Replace(src, "src/main/resources")
 	// Copy also metainfo.prop that contains the description if it is available
-	if file.Exists(srcDir + "/metainfo.prop") {
-		err = file.CopyFile(srcDir+"/metainfo.prop", tgtDir+"/metainfo.prop")
-		if err != nil {
-			return err
-		}
+	if src.Exists("metainfo.prop") {
+		tgt.Replace(src, "metainfo.prop")
 	}
-	return nil
 }
-func (sc *ScriptCollection) CompareContent(srcDir string, tgtDir string, _ []string, _ string) (bool, error) {
-	// Diff directories
+func (sc *ScriptCollection) CompareContent(src *file.Content, tgt *file.Content, _ []*file.BPMNRule, _ string) (bool, error) {
+	// Diff contents
 	log.Info().Msg("Checking for changes in META-INF directory")
-	metaDiffer := file.DiffDirectories(srcDir+"/META-INF", tgtDir+"/META-INF")
+	metaDiffer := file.DiffContent(src, tgt, "META-INF")
 	// It is technically possible to have an empty script collection
-	if file.Exists(srcDir+"/src/main/resources") && file.Exists(tgtDir+"/src/main/resources") {
-		return metaDiffer || diffContent(srcDir, tgtDir), nil
-	} else if !file.Exists(srcDir+"/src/main/resources") && !file.Exists(tgtDir+"/src/main/resources") {
+	if src.Exists("src/main/resources") && tgt.Exists("src/main/resources") {
+		return metaDiffer || diffContent(src, tgt), nil
+	} else if !src.Exists("src/main/resources") && !tgt.Exists("src/main/resources") {
 		log.Warn().Msg("Skipping diff as /src/main/resources does not exist in both source and target")
 		log.Info().Msg("Checking for changes in metainfo.prop")
-		metainfoDiffer := DiffOptionalFile(srcDir, tgtDir, "metainfo.prop")
+		metainfoDiffer := DiffOptionalFile(src, tgt, "metainfo.prop")
 		return metaDiffer || metainfoDiffer, nil
 	}
 	log.Info().Msg("Directory /src/main/resources does not exist in either source or target")
 	return true, nil
 }
+func (sc *ScriptCollection) ContentHash(content *file.Content) string {
+	return contentHash(content)
+}
+func (sc *ScriptCollection) DiffFiles(src *file.Content, tgt *file.Content, srcName string, tgtName string) ([]*file.FileDiff, error) {
+	return diffFiles(src, tgt, srcName, tgtName)
+}
@@ -2,12 +2,17 @@ package api
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"github.com/engswee/flashpipe/internal/config"
 	"github.com/engswee/flashpipe/internal/httpclnt"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 )
 
 type ServiceDetails struct {
@@ -18,29 +23,86 @@ type ServiceDetails struct {
 	OauthPath         string
 	OauthClientId     string
 	OauthClientSecret string
+	ClientCertificate *tls.Certificate
+	RootCAs           *x509.CertPool
 }
 
-func GetServiceDetails(cmd *cobra.Command) *ServiceDetails {
-	oauthHost := config.GetString(cmd, "oauth-host")
-	if oauthHost == "" {
-		return &ServiceDetails{
-			Host:     config.GetString(cmd, "tmn-host"),
-			Userid:   config.GetString(cmd, "tmn-userid"),
-			Password: config.GetString(cmd, "tmn-password"),
+// GetServiceDetails returns the connection details of the tenant from the connection flags of the command
+func GetServiceDetails(cmd *cobra.Command) (*ServiceDetails, error) {
+	return newServiceDetails(func(key string) string {
+		return config.GetString(cmd, key)
+	})
+}
+
+// GetServiceDetailsFromProfile returns the connection details of a tenant from the settings of a profile in the config file
+func GetServiceDetailsFromProfile(settings map[string]any) (*ServiceDetails, error) {
+	return newServiceDetails(func(key string) string {
+		value, ok := settings[key]
+		if !ok {
+			if key == "oauth-path" {
+				return "/oauth/token"
+			}
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+}
+
+func newServiceDetails(get func(key string) string) (*ServiceDetails, error) {
+	var serviceDetails *ServiceDetails
+	if serviceKey := get("service-key"); serviceKey != "" {
+		var err error
+		serviceDetails, err = ParseServiceKey(serviceKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --service-key: %w", err)
+		}
+	} else if oauthHost := get("oauth-host"); oauthHost == "" {
+		serviceDetails = &ServiceDetails{
+			Host:     get("tmn-host"),
+			Userid:   get("tmn-userid"),
+			Password: get("tmn-password"),
 		}
 	} else {
-		return &ServiceDetails{
-			Host:              config.GetString(cmd, "tmn-host"),
+		serviceDetails = &ServiceDetails{
+			Host:              get("tmn-host"),
 			OauthHost:         oauthHost,
-			OauthClientId:     config.GetString(cmd, "oauth-clientid"),
-			OauthClientSecret: config.GetString(cmd, "oauth-clientsecret"),
-			OauthPath:         config.GetString(cmd, "oauth-path"),
+			OauthClientId:     get("oauth-clientid"),
+			OauthClientSecret: get("oauth-clientsecret"),
+			OauthPath:         get("oauth-path"),
 		}
 	}
+
+	// Client certificate files take precedence over the certificate of the service key
+	clientCertificate, err := httpclnt.LoadClientCertificate(get("tls-cert"), get("tls-key"), get("tls-pkcs12"), get("tls-pkcs12-password"))
+	if err != nil {
+		return nil, err
+	}
+	if clientCertificate != nil {
+		serviceDetails.ClientCertificate = clientCertificate
+	}
+	serviceDetails.RootCAs, err = httpclnt.LoadCABundle(get("tls-ca-bundle"))
+	if err != nil {
+		return nil, err
+	}
+	return serviceDetails, nil
 }
 
 func InitHTTPExecuter(serviceDetails *ServiceDetails) *httpclnt.HTTPExecuter {
-	return httpclnt.New(serviceDetails.OauthHost, serviceDetails.OauthPath, serviceDetails.OauthClientId, serviceDetails.OauthClientSecret, serviceDetails.Userid, serviceDetails.Password, serviceDetails.Host, "https", 443, true)
+	var tlsConfig *tls.Config
+	if serviceDetails.ClientCertificate != nil || serviceDetails.RootCAs != nil {
+		tlsConfig = &tls.Config{RootCAs: serviceDetails.RootCAs}
+		if serviceDetails.ClientCertificate != nil {
+			tlsConfig.Certificates = []tls.Certificate{*serviceDetails.ClientCertificate}
+		}
+	}
+	// The host can contain a port, e.g. for a local test server
+	host, port := serviceDetails.Host, 443
+	if h, p, err := net.SplitHostPort(serviceDetails.Host); err == nil {
+		if portNumber, err := strconv.Atoi(p); err == nil {
+			host, port = h, portNumber
+		}
+	}
+	return httpclnt.NewWithTLS(serviceDetails.OauthHost, serviceDetails.OauthPath, serviceDetails.OauthClientId, serviceDetails.OauthClientSecret, serviceDetails.Userid, serviceDetails.Password, host, "https", port, true, tlsConfig)
 }
 
 func modifyingCall(method string, urlPath string, content []byte, successCode int, callType string, exe *httpclnt.HTTPExecuter) error {
@@ -48,6 +110,15 @@ func modifyingCall(method string, urlPath string, content []byte, successCode in
 }
 
 func modifyingCallWithContentType(method string, urlPath string, content []byte, contentType string, successCode int, callType string, exe *httpclnt.HTTPExecuter) error {
+	return execModifyingCall(method, urlPath, content, content, contentType, successCode, callType, exe)
+}
+
+// modifyingCallWithRedactedBody logs redactedContent instead of the request body, for request bodies that contain secrets
+func modifyingCallWithRedactedBody(method string, urlPath string, content []byte, redactedContent []byte, successCode int, callType string, exe *httpclnt.HTTPExecuter) error {
+	return execModifyingCall(method, urlPath, content, redactedContent, "application/json", successCode, callType, exe)
+}
+
+func execModifyingCall(method string, urlPath string, content []byte, loggedContent []byte, contentType string, successCode int, callType string, exe *httpclnt.HTTPExecuter) error {
 	headers, cookies, err := InitHeadersAndCookies(exe)
 	if err != nil {
 		return err
@@ -57,7 +128,7 @@ func modifyingCallWithContentType(method string, urlPath string, content []byte,
 	var body io.Reader
 	if len(content) > 0 {
 		headers["Content-Type"] = contentType
-		log.Debug().Msgf("Request body = %s", content)
+		log.Debug().Msgf("Request body = %s", loggedContent)
 		body = bytes.NewReader(content)
 	} else {
 		body = http.NoBody
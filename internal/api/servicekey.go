@@ -0,0 +1,91 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ParseServiceKey returns the connection details from a BTP service key. The value is either the path of the service
+// key file or the JSON content of the service key. The following formats are supported:
+//   - Process Integration Runtime (plan api) with the details in the oauth object
+//   - API Management (API portal access) with the details at the top level
+//
+// Both the client secret and the certificate-based (X.509) variants of the service keys are supported.
+func ParseServiceKey(value string) (*ServiceDetails, error) {
+	content := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		var err error
+		content, err = os.ReadFile(value)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+	}
+	var key map[string]any
+	err := json.Unmarshal(content, &key)
+	if err != nil {
+		return nil, fmt.Errorf("Service key is not valid JSON: %w", err)
+	}
+	key = lowerCaseKeys(key)
+	if oauth, ok := key["oauth"].(map[string]any); ok {
+		key = lowerCaseKeys(oauth)
+	}
+	field := func(name string) string {
+		value, _ := key[name].(string)
+		return value
+	}
+
+	details := &ServiceDetails{OauthClientId: field("clientid"), OauthClientSecret: field("clientsecret")}
+	details.Host, _, err = serviceKeyURL(field("url"), "url")
+	if err != nil {
+		return nil, err
+	}
+	details.OauthHost, details.OauthPath, err = serviceKeyURL(field("tokenurl"), "tokenurl")
+	if err != nil {
+		return nil, err
+	}
+	if details.OauthClientId == "" {
+		return nil, fmt.Errorf("Service key does not have clientid")
+	}
+	if certificate := field("certificate"); certificate != "" {
+		clientCertificate, err := tls.X509KeyPair([]byte(certificate), []byte(field("key")))
+		if err != nil {
+			return nil, fmt.Errorf("Service key has invalid certificate or key: %w", err)
+		}
+		details.ClientCertificate = &clientCertificate
+	} else if details.OauthClientSecret == "" {
+		return nil, fmt.Errorf("Service key does not have clientsecret or certificate")
+	}
+	return details, nil
+}
+
+// serviceKeyURL returns the host and path of a URL in the service key
+func serviceKeyURL(value string, name string) (string, string, error) {
+	if value == "" {
+		return "", "", fmt.Errorf("Service key does not have %v", name)
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", "", fmt.Errorf("Service key has invalid %v: %w", name, err)
+	}
+	if parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return "", "", fmt.Errorf("Service key has invalid %v %v - expected https://<host>", name, value)
+	}
+	if parsed.Port() != "" && parsed.Port() != "443" {
+		return "", "", fmt.Errorf("Service key has %v %v with unsupported port %v", name, value, parsed.Port())
+	}
+	return parsed.Hostname(), parsed.Path, nil
+}
+
+func lowerCaseKeys(m map[string]any) map[string]any {
+	lowered := map[string]any{}
+	for key, value := range m {
+		lowered[strings.ToLower(key)] = value
+	}
+	return lowered
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServiceKey_ProcessIntegrationRuntime(t *testing.T) {
+	details, err := ParseServiceKey(`{"oauth":{"clientid":"sb-client","clientsecret":"secret","url":"https://tenant.it-cpi018.cfapps.eu10-003.hana.ondemand.com","tokenurl":"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token","createdate":"2024-05-01T08:00:00.000Z"}}`)
+	if err != nil {
+		t.Fatalf("ParseServiceKey failed with error - %v", err)
+	}
+	assert.Equal(t, &ServiceDetails{
+		Host:              "tenant.it-cpi018.cfapps.eu10-003.hana.ondemand.com",
+		OauthHost:         "subaccount.authentication.eu10.hana.ondemand.com",
+		OauthPath:         "/oauth/token",
+		OauthClientId:     "sb-client",
+		OauthClientSecret: "secret",
+	}, details, "Incorrect service details")
+}
+
+func TestParseServiceKey_APIManagementFile(t *testing.T) {
+	keyFile := t.TempDir() + "/apim-key.json"
+	err := os.WriteFile(keyFile, []byte(`{"url":"https://devportal.prod01.apimanagement.eu10.hana.ondemand.com","tokenUrl":"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token","clientId":"sb-apim","clientSecret":"secret"}`), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+
+	details, err := ParseServiceKey(keyFile)
+	if err != nil {
+		t.Fatalf("ParseServiceKey failed with error - %v", err)
+	}
+	assert.Equal(t, "devportal.prod01.apimanagement.eu10.hana.ondemand.com", details.Host, "Incorrect host")
+	assert.Equal(t, "sb-apim", details.OauthClientId, "Expected field names to not be case-sensitive")
+}
+
+func TestParseServiceKey_Certificate(t *testing.T) {
+	certificate, key := generateCertificate(t)
+	content, _ := json.Marshal(map[string]any{"oauth": map[string]string{
+		"clientid":    "sb-client",
+		"certificate": certificate,
+		"key":         key,
+		"url":         "https://tenant.it-cpi018.cfapps.eu10-003.hana.ondemand.com",
+		"tokenurl":    "https://subaccount.authentication.cert.eu10.hana.ondemand.com/oauth/token",
+	}})
+
+	details, err := ParseServiceKey(string(content))
+	if err != nil {
+		t.Fatalf("ParseServiceKey failed with error - %v", err)
+	}
+	assert.NotNil(t, details.ClientCertificate, "Expected client certificate")
+	assert.Equal(t, "subaccount.authentication.cert.eu10.hana.ondemand.com", details.OauthHost, "Incorrect OAuth host")
+	assert.Empty(t, details.OauthClientSecret, "Expected no client secret")
+}
+
+func TestParseServiceKey_Invalid(t *testing.T) {
+	_, err := ParseServiceKey(`{"oauth":{"clientid":"sb-client","url":"https://tenant.hana.ondemand.com","tokenurl":"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token"}}`)
+	assert.ErrorContains(t, err, "Service key does not have clientsecret or certificate")
+	_, err = ParseServiceKey(`{"clientid":"sb-client","clientsecret":"secret","url":"http://tenant.hana.ondemand.com","tokenurl":"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token"}`)
+	assert.ErrorContains(t, err, "expected https://<host>")
+	_, err = ParseServiceKey(`{"clientid":"sb-client","clientsecret":"secret","url":"https://tenant.hana.ondemand.com"}`)
+	assert.ErrorContains(t, err, "Service key does not have tokenurl")
+	_, err = ParseServiceKey(`{"clientid":"sb-client","certificate":"invalid","key":"invalid","url":"https://tenant.hana.ondemand.com","tokenurl":"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token"}`)
+	assert.ErrorContains(t, err, "Service key has invalid certificate or key")
+}
+
+// generateCertificate returns a self-signed certificate and its private key in PEM format
+func generateCertificate(t *testing.T) (string, string) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed with error - %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "flashpipe"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed with error - %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey failed with error - %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
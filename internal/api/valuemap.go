@@ -19,16 +19,16 @@ func NewValueMapping(exe *httpclnt.HTTPExecuter) DesigntimeArtifact {
 	return i
 }
 
-func (vm *ValueMapping) Create(id string, name string, packageId string, artifactDir string) error {
-	return create(id, name, packageId, artifactDir, vm.typ, vm.exe)
+func (vm *ValueMapping) Create(id string, name string, packageId string, content *file.Content) error {
+	return create(id, name, packageId, content, vm.typ, vm.exe)
 }
-func (vm *ValueMapping) Update(id string, name string, packageId string, artifactDir string) error {
+func (vm *ValueMapping) Update(id string, name string, packageId string, content *file.Content) error {
 	log.Info().Msgf("Update of Value Mapping %v by executing delete followed by create", id)
 	err := deleteCall(id, vm.typ, vm.exe)
 	if err != nil {
 		return err
 	}
-	return create(id, name, packageId, artifactDir, vm.typ, vm.exe)
+	return create(id, name, packageId, content, vm.typ, vm.exe)
 }
 func (vm *ValueMapping) Deploy(id string) error {
 	return deploy(id, vm.typ, vm.exe)
@@ -39,35 +39,32 @@ func (vm *ValueMapping) Delete(id string) error {
 func (vm *ValueMapping) Get(id string, version string) (string, string, bool, error) {
 	return get(id, version, vm.typ, vm.exe)
 }
-func (vm *ValueMapping) Download(targetFile string, id string) error {
-	return download(targetFile, id, vm.typ, vm.exe)
+func (vm *ValueMapping) Download(id string) (*file.Content, error) {
+	return download(id, vm.typ, vm.exe)
 }
-func (vm *ValueMapping) CopyContent(srcDir string, tgtDir string) error {
+func (vm *ValueMapping) CopyContent(src *file.Content, tgt *file.Content) {
 	// Copy META-INF and value_mapping.xml separately so that other directories like QA, STG, PRD not copied
-	err := file.ReplaceDir(srcDir+"/META-INF", tgtDir+"/META-INF")
-	if err != nil {
-		return err
-	}
-	err = file.CopyFile(srcDir+"/value_mapping.xml", tgtDir+"/value_mapping.xml")
-	if err != nil {
-		return err
-	}
+	tgt.Replace(src, "META-INF")
+	tgt.Replace(src, "value_mapping.xml")
 	// Copy also metainfo.prop that contains the description if it is available
-	if file.Exists(srcDir + "/metainfo.prop") {
-		err = file.CopyFile(srcDir+"/metainfo.prop", tgtDir+"/metainfo.prop")
-		if err != nil {
-			return err
-		}
+	if src.Exists("metainfo.prop") {
+		tgt.Replace(src, "metainfo.prop")
 	}
-	return nil
 }
-func (vm *ValueMapping) CompareContent(srcDir string, tgtDir string, _ []string, _ string) (bool, error) {
-	// Diff directories
+func (vm *ValueMapping) CompareContent(src *file.Content, tgt *file.Content, _ []*file.BPMNRule, _ string) (bool, error) {
+	// Diff contents
 	log.Info().Msg("Checking for changes in META-INF directory")
-	metaDiffer := file.DiffDirectories(srcDir+"/META-INF", tgtDir+"/META-INF")
+	metaDiffer := file.DiffContent(src, tgt, "META-INF")
 	log.Info().Msg("Checking for changes in value_mapping.xml")
-	xmlDiffer := file.DiffFile(srcDir+"/value_mapping.xml", tgtDir+"/value_mapping.xml")
+	xmlDiffer := file.DiffContentFile(src, tgt, "value_mapping.xml")
 	// TODO - The API for value mapping does not return metainfo.prop, so we can't compare it
 
 	return metaDiffer || xmlDiffer, nil
 }
+func (vm *ValueMapping) ContentHash(content *file.Content) string {
+	// metainfo.prop is excluded as it is not returned by the API for value mapping
+	return file.ContentHash(content, "META-INF", "value_mapping.xml")
+}
+func (vm *ValueMapping) DiffFiles(src *file.Content, tgt *file.Content, srcName string, tgtName string) ([]*file.FileDiff, error) {
+	return file.DiffContentFiles(src, tgt, srcName, tgtName, "META-INF", "value_mapping.xml")
+}
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/engswee/flashpipe/internal/analytics"
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/file"
 	"github.com/engswee/flashpipe/internal/repo"
 	"github.com/engswee/flashpipe/internal/str"
 	"github.com/engswee/flashpipe/internal/sync"
@@ -47,6 +49,12 @@ tenant and a Git repository.`,
 			default:
 				return fmt.Errorf("invalid value for --target = %v", target)
 			}
+			// Validate content types
+			for _, contentType := range config.GetStringSlice(cmd, "content-types") {
+				if _, ok := apimSyncFunctionTypes[strings.TrimSpace(contentType)]; !ok {
+					return fmt.Errorf("invalid value for --content-types = %v", contentType)
+				}
+			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -59,10 +67,42 @@ tenant and a Git repository.`,
 		},
 	}
 	// Define cobra flags, the default value has the lowest (least significant) precedence
+	apimCmd.Flags().StringSlice("content-types", []string{"proxies"}, "Comma-separated list of content types to sync. Allowed values: proxies, products, applications, developers, providers, kvms, rateplans")
+	apimCmd.Flags().Bool("deploy", false, "Deploy API proxies that are created or updated in the tenant and wait until they are deployed")
+	apimCmd.Flags().Int("delay-length", 10, "Delay (in seconds) between each check of API proxy deployment status")
+	apimCmd.Flags().Int("max-check-limit", 10, "Max number of times to check for API proxy deployment status")
+	apimCmd.Flags().String("file-overlay", "", "JSON file with rules for converting environment-specific values in API proxies (Git values to tenant values), reversed when syncing to Git")
+	apimCmd.Flags().StringSlice("apim-ignore-fields", file.DefaultAPIMIgnoredFields, "Comma-separated list of volatile fields (XML elements/attributes, JSON keys) of API proxies that are removed before comparing and writing to Git")
+	apimCmd.Flags().String("file-kvm-secrets", "", "Properties file with values of encrypted Key Value Map entries in the format <map>.<entry>=<value>")
 
 	return apimCmd
 }
 
+// apimSyncFunctionTypes maps the content types of sync apim to the function type of the syncer
+var apimSyncFunctionTypes = map[string]string{
+	"developers":   "APIMDeveloper",
+	"providers":    "APIMProvider",
+	"kvms":         "APIMKeyValueMap",
+	"rateplans":    "APIMRatePlan",
+	"proxies":      "APIM",
+	"products":     "APIMProduct",
+	"applications": "APIMApplication",
+}
+
+// apimSyncOrder is the order the content types are processed in, so that referenced content
+// (e.g. APIProviders and KeyValueMaps of APIProxies) exists in the tenant before the content that references it
+var apimSyncOrder = []string{"developers", "providers", "kvms", "rateplans", "proxies", "products", "applications"}
+
+// apimSyncRequest returns the sync request for the content type. --ids-include and --ids-exclude select API proxies
+// by name, so they are not applied to the other content types
+func apimSyncRequest(contentType string, request sync.Request) sync.Request {
+	if contentType != "proxies" {
+		request.IncludedIds = nil
+		request.ExcludedIds = nil
+	}
+	return request
+}
+
 func runSyncAPIM(cmd *cobra.Command) error {
 	log.Info().Msg("Executing sync apim command")
 
@@ -85,16 +125,49 @@ func runSyncAPIM(cmd *cobra.Command) error {
 	commitEmail := config.GetString(cmd, "git-commit-email")
 	skipCommit := config.GetBool(cmd, "git-skip-commit")
 	target := config.GetString(cmd, "target")
+	contentTypes := str.TrimSlice(config.GetStringSlice(cmd, "content-types"))
+	ignoredFields := str.TrimSlice(config.GetStringSlice(cmd, "apim-ignore-fields"))
+	deploy := config.GetBool(cmd, "deploy")
+	delayLength := config.GetInt(cmd, "delay-length")
+	maxCheckLimit := config.GetInt(cmd, "max-check-limit")
+	secretsFile, err := config.GetStringWithEnvExpand(cmd, "file-kvm-secrets")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-kvm-secrets: %w", err)
+	}
+	overlayFile, err := config.GetStringWithEnvExpand(cmd, "file-overlay")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-overlay: %w", err)
+	}
+	var overlay *file.APIProxyOverlay
+	if overlayFile != "" {
+		log.Info().Msgf("Using %v for converting environment-specific values in API proxies", overlayFile)
+		overlay, err = file.LoadAPIProxyOverlay(overlayFile)
+		if err != nil {
+			return err
+		}
+		// Rules in file are defined from Git to tenant, so reverse them when syncing to Git
+		if target == "git" {
+			overlay = overlay.Reverse()
+		}
+	}
 
-	serviceDetails := api.GetServiceDetails(cmd)
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
 	// Initialise HTTP executer
 	exe := api.InitHTTPExecuter(serviceDetails)
 
-	syncer := sync.NewSyncer(target, "APIM", exe)
 	apimWorkDir := fmt.Sprintf("%v/apim", workDir)
-	err = syncer.Exec(sync.Request{WorkDir: apimWorkDir, ArtifactsDir: artifactsDir, IncludedIds: includedIds, ExcludedIds: excludedIds})
-	if err != nil {
-		return err
+	for _, contentType := range apimSyncOrder {
+		if !slices.Contains(contentTypes, contentType) {
+			continue
+		}
+		syncer := sync.NewSyncer(target, apimSyncFunctionTypes[contentType], exe)
+		err = syncer.Exec(apimSyncRequest(contentType, sync.Request{WorkDir: apimWorkDir, ArtifactsDir: artifactsDir, IncludedIds: includedIds, ExcludedIds: excludedIds, SecretsFile: secretsFile, Deploy: deploy, DelayLength: delayLength, MaxCheckLimit: maxCheckLimit, Overlay: overlay, IgnoredFields: ignoredFields}))
+		if err != nil {
+			return err
+		}
 	}
 	if target == "git" && !skipCommit {
 		err = repo.CommitToRepo(gitRepoDir, commitMsg, commitUser, commitEmail)
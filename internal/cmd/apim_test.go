@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/sync"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIMSyncRequestIdsOnlyForProxies(t *testing.T) {
+	request := sync.Request{ArtifactsDir: "apim", IncludedIds: []string{"OrderProxy"}, ExcludedIds: []string{"LegacyProxy"}}
+
+	proxyRequest := apimSyncRequest("proxies", request)
+	assert.Equal(t, []string{"OrderProxy"}, proxyRequest.IncludedIds, "Expected included IDs for API proxies")
+	assert.Equal(t, []string{"LegacyProxy"}, proxyRequest.ExcludedIds, "Expected excluded IDs for API proxies")
+
+	for _, contentType := range []string{"products", "applications", "developers", "providers", "kvms", "rateplans"} {
+		entityRequest := apimSyncRequest(contentType, request)
+		assert.Nil(t, entityRequest.IncludedIds, "Expected no included IDs for %v", contentType)
+		assert.Nil(t, entityRequest.ExcludedIds, "Expected no excluded IDs for %v", contentType)
+		assert.Equal(t, "apim", entityRequest.ArtifactsDir, "Expected other fields of request kept for %v", contentType)
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/engswee/flashpipe/internal/traffic"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewAPIMAnalyticsCommand() *cobra.Command {
+
+	analyticsCmd := &cobra.Command{
+		Use:   "analytics",
+		Short: "Summarise traffic of API proxies",
+		Long: `Summarise the call counts, error rates and latencies of API proxies
+from the analytics of SAP Integration Suite API Management.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the output format
+			outputFormat := config.GetString(cmd, "output-format")
+			switch outputFormat {
+			case "table", "json", "csv":
+			default:
+				return fmt.Errorf("invalid value for --output-format = %v", outputFormat)
+			}
+			// Validate the time range
+			now := time.Now()
+			for _, flag := range []string{"from", "to"} {
+				if value := config.GetString(cmd, flag); value != "" {
+					if _, err := traffic.ParseTime(value, now); err != nil {
+						return fmt.Errorf("invalid value for --%v = %v", flag, value)
+					}
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runAPIMAnalytics(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	analyticsCmd.Flags().StringSlice("proxy", nil, "Comma separated list of API proxy names")
+	analyticsCmd.Flags().String("from", "24h", "Start of time range as timestamp (RFC3339), date or duration before --to")
+	analyticsCmd.Flags().String("to", "", "End of time range as timestamp (RFC3339), date or duration before now. Defaults to now")
+	analyticsCmd.Flags().String("output-format", "table", "Format of traffic report. Allowed values: table, json, csv")
+	analyticsCmd.Flags().String("file-output", "", "File to write the traffic report to. Defaults to standard output")
+	analyticsCmd.Flags().Int("min-calls", 0, "Fail when an API proxy has less calls than this. Not checked when 0")
+	analyticsCmd.Flags().Float64("max-error-rate", 0, "Fail when the error rate (in percent) of an API proxy is higher than this. Not checked when 0")
+	analyticsCmd.Flags().Int("max-latency-p95", 0, "Fail when the 95th percentile latency (in milliseconds) of an API proxy is higher than this. Not checked when 0")
+
+	_ = analyticsCmd.MarkFlagRequired("proxy")
+
+	return analyticsCmd
+}
+
+func runAPIMAnalytics(cmd *cobra.Command) error {
+	log.Info().Msg("Executing apim analytics command")
+
+	proxyNames := str.TrimSlice(config.GetStringSlice(cmd, "proxy"))
+	outputFormat := config.GetString(cmd, "output-format")
+	outputFile, err := config.GetStringWithEnvExpand(cmd, "file-output")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-output: %w", err)
+	}
+	thresholds := traffic.Thresholds{
+		MinCalls:      config.GetInt(cmd, "min-calls"),
+		MaxErrorRate:  config.GetFloat64(cmd, "max-error-rate"),
+		MaxLatencyP95: int64(config.GetInt(cmd, "max-latency-p95")),
+	}
+
+	// Flags are already validated in PreRunE
+	to := time.Now()
+	if value := config.GetString(cmd, "to"); value != "" {
+		to, _ = traffic.ParseTime(value, to)
+	}
+	from, _ := traffic.ParseTime(config.GetString(cmd, "from"), to)
+	if !from.Before(to) {
+		return fmt.Errorf("--from [%v] must be before --to [%v]", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	exe := api.InitHTTPExecuter(serviceDetails)
+	calls, err := api.NewAnalytics(exe).ListAPICalls(proxyNames, from, to)
+	if err != nil {
+		return err
+	}
+	report := traffic.Summarise(calls, proxyNames, from, to)
+	violations := report.Check(thresholds)
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer f.Close()
+		w = f
+	}
+	err = report.Write(w, outputFormat)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		log.Info().Msgf("Traffic report written to %v", outputFile)
+	}
+
+	if len(violations) > 0 {
+		for _, violation := range violations {
+			log.Error().Msg(violation)
+		}
+		return fmt.Errorf("Traffic of API proxies exceeded thresholds: %v", strings.Join(violations, "; "))
+	}
+	log.Info().Msg("🏆 Traffic of API proxies summarised successfully")
+	return nil
+}
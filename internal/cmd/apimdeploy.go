@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/engswee/flashpipe/internal/sync"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewDeployAPIMCommand() *cobra.Command {
+
+	deployAPIMCmd := &cobra.Command{
+		Use:   "apim",
+		Short: "Deploy API proxies",
+		Long: `Deploy API proxies in the API portal of
+SAP Integration Suite API Management.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runDeployAPIM(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	addAPIProxyStateFlags(deployAPIMCmd, "deployment")
+	return deployAPIMCmd
+}
+
+func NewUndeployCommand() *cobra.Command {
+
+	undeployCmd := &cobra.Command{
+		Use:   "undeploy",
+		Short: "Undeploy API proxies of API Management",
+		Long: `Undeploy API proxies from the API portal of
+SAP Integration Suite API Management.`,
+	}
+	return undeployCmd
+}
+
+func NewUndeployAPIMCommand() *cobra.Command {
+
+	undeployAPIMCmd := &cobra.Command{
+		Use:   "apim",
+		Short: "Undeploy API proxies",
+		Long: `Undeploy API proxies in the API portal of
+SAP Integration Suite API Management.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runUndeployAPIM(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	addAPIProxyStateFlags(undeployAPIMCmd, "undeployment")
+	return undeployAPIMCmd
+}
+
+func addAPIProxyStateFlags(cmd *cobra.Command, action string) {
+	cmd.Flags().StringSlice("proxy-names", nil, "Comma separated list of API proxy names")
+	cmd.Flags().Int("delay-length", 10, "Delay (in seconds) between each check of API proxy "+action+" status")
+	cmd.Flags().Int("max-check-limit", 10, "Max number of times to check for API proxy "+action+" status")
+
+	_ = cmd.MarkFlagRequired("proxy-names")
+}
+
+func runDeployAPIM(cmd *cobra.Command) error {
+	log.Info().Msg("Executing deploy apim command")
+
+	proxyNames := str.TrimSlice(config.GetStringSlice(cmd, "proxy-names"))
+	delayLength := config.GetInt(cmd, "delay-length")
+	maxCheckLimit := config.GetInt(cmd, "max-check-limit")
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	exe := api.InitHTTPExecuter(serviceDetails)
+	err = sync.DeployAPIProxies(api.NewAPIProxy(exe), proxyNames, delayLength, maxCheckLimit)
+	if err != nil {
+		return err
+	}
+	log.Info().Msg("🏆 API proxy deployment completed successfully")
+	return nil
+}
+
+func runUndeployAPIM(cmd *cobra.Command) error {
+	log.Info().Msg("Executing undeploy apim command")
+
+	proxyNames := str.TrimSlice(config.GetStringSlice(cmd, "proxy-names"))
+	delayLength := config.GetInt(cmd, "delay-length")
+	maxCheckLimit := config.GetInt(cmd, "max-check-limit")
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	exe := api.InitHTTPExecuter(serviceDetails)
+	err = sync.UndeployAPIProxies(api.NewAPIProxy(exe), proxyNames, delayLength, maxCheckLimit)
+	if err != nil {
+		return err
+	}
+	log.Info().Msg("🏆 API proxy undeployment completed successfully")
+	return nil
+}
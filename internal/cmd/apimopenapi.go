@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/openapi"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewAPIMGroupCommand() *cobra.Command {
+
+	apimCmd := &cobra.Command{
+		Use:   "apim",
+		Short: "Manage API Management content",
+		Long: `Manage content in the API portal of
+SAP Integration Suite API Management.`,
+	}
+	return apimCmd
+}
+
+func NewImportOpenAPICommand() *cobra.Command {
+
+	importCmd := &cobra.Command{
+		Use:   "import-openapi",
+		Short: "Create or update API proxy from OpenAPI specification",
+		Long: `Create or update an API proxy in the API portal of
+SAP Integration Suite API Management from an OpenAPI 3 specification.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			basePath := config.GetString(cmd, "base-path")
+			if !strings.HasPrefix(basePath, "/") {
+				return fmt.Errorf("invalid value for --base-path = %v", basePath)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runImportOpenAPI(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	importCmd.Flags().String("spec", "", "OpenAPI 3 specification file in YAML or JSON format")
+	importCmd.Flags().String("provider", "", "Name of API provider of the backend service")
+	importCmd.Flags().String("base-path", "", "Base path of API proxy, e.g. /v1/orders")
+	importCmd.Flags().String("proxy-name", "", "Name of API proxy. Defaults to title of specification when not provided")
+	importCmd.Flags().String("dir-artifacts", "", "Directory containing API proxies in Git. The generated content is merged into the existing API proxy in this directory")
+	importCmd.Flags().String("dir-work", "/tmp", "Working directory for in-transit files")
+
+	_ = importCmd.MarkFlagRequired("spec")
+	_ = importCmd.MarkFlagRequired("provider")
+	_ = importCmd.MarkFlagRequired("base-path")
+
+	return importCmd
+}
+
+func runImportOpenAPI(cmd *cobra.Command) error {
+	log.Info().Msg("Executing apim import-openapi command")
+
+	specFile, err := config.GetStringWithEnvExpand(cmd, "spec")
+	if err != nil {
+		return fmt.Errorf("security alert for --spec: %w", err)
+	}
+	artifactsDir, err := config.GetStringWithEnvExpand(cmd, "dir-artifacts")
+	if err != nil {
+		return fmt.Errorf("security alert for --dir-artifacts: %w", err)
+	}
+	workDir, err := config.GetStringWithEnvExpand(cmd, "dir-work")
+	if err != nil {
+		return fmt.Errorf("security alert for --dir-work: %w", err)
+	}
+	provider := config.GetString(cmd, "provider")
+	basePath := config.GetString(cmd, "base-path")
+	proxyName := config.GetString(cmd, "proxy-name")
+
+	spec, err := openapi.Load(specFile)
+	if err != nil {
+		return err
+	}
+	if proxyName == "" {
+		proxyName = spec.ProxyName()
+		if proxyName == "" {
+			return fmt.Errorf("--proxy-name is required as the OpenAPI specification does not have a title")
+		}
+	}
+
+	// Without a Git directory, the content is generated from scratch in the working directory
+	var artifactDir string
+	if artifactsDir != "" {
+		artifactDir = filepath.Join(artifactsDir, proxyName)
+	} else {
+		artifactDir = filepath.Join(workDir, "openapi", proxyName)
+		err = os.RemoveAll(artifactDir)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	err = openapi.GenerateAPIProxy(spec, openapi.ProxyOptions{Name: proxyName, Provider: provider, BasePath: basePath}, artifactDir)
+	if err != nil {
+		return err
+	}
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	exe := api.InitHTTPExecuter(serviceDetails)
+	err = api.NewAPIProxy(exe).Upload(artifactDir, workDir)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("🏆 API proxy %v imported successfully", proxyName)
+	return nil
+}
@@ -29,7 +29,10 @@ SAP Integration Suite tenant.`,
 			default:
 				return fmt.Errorf("invalid value for --artifact-type = %v", artifactType)
 			}
-			return nil
+			if err := validateParamsCheck(cmd); err != nil {
+				return err
+			}
+			return validateVersionBump(cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			startTime := time.Now()
@@ -51,6 +54,16 @@ SAP Integration Suite tenant.`,
 	artifactCmd.Flags().String("file-manifest", "", "Use a different MANIFEST.MF file instead of the default in META-INF/")
 	artifactCmd.Flags().String("dir-work", "/tmp", "Working directory for in-transit files")
 	artifactCmd.Flags().StringSlice("script-collection-map", nil, "Comma-separated source-target ID pairs for converting script collection references during create/update")
+	artifactCmd.Flags().String("file-bpmn-rules", "", "JSON file with rules for converting values in IFlow BPMN2 XML (Git values to tenant values) during create/update")
+	artifactCmd.Flags().Bool("lint", false, "Lint artifact before create/update and stop when there are lint errors")
+	artifactCmd.Flags().String("file-lint-config", "", "JSON file with settings of built-in lint rules and custom lint rules")
+	artifactCmd.Flags().String("params-check", "WARN", "Handling of findings when checking parameters.prop against parameters.propdef and IFlow before create/update. Allowed values: WARN, ERROR, SKIP")
+	artifactCmd.Flags().Bool("redeploy-on-param-change", false, "Redeploy instead of undeploying runtime artifact when configured parameters are changed")
+	artifactCmd.Flags().Bool("redeploy", false, "Redeploy runtime artifact and wait for deployment after changes in design or configured parameters")
+	artifactCmd.Flags().Int("delay-length", 30, "Delay (in seconds) between each check of artifact deployment status when redeploying")
+	artifactCmd.Flags().Int("max-check-limit", 10, "Max number of times to check for artifact deployment status when redeploying")
+	artifactCmd.Flags().String("version-bump", "", "Increment version based on version in tenant when changes are found. Allowed values: patch, minor, major, timestamp")
+	artifactCmd.Flags().Bool("version-bump-write-back", false, "Write bumped Bundle-Version back to MANIFEST.MF in artifact directory")
 	artifactCmd.Flags().String("artifact-type", "Integration", "Artifact type. Allowed values: Integration, MessageMapping, ScriptCollection, ValueMapping")
 	// TODO - another flag for replacing value mapping in QAS?
 
@@ -85,6 +98,7 @@ func runUpdateArtifact(cmd *cobra.Command) error {
 		return fmt.Errorf("security alert for --dir-work: %w", err)
 	}
 	scriptMap := str.TrimSlice(config.GetStringSlice(cmd, "script-collection-map"))
+	bpmnRulesFile := config.GetString(cmd, "file-bpmn-rules")
 
 	defaultParamFile := fmt.Sprintf("%v/src/main/resources/parameters.prop", artifactDir)
 	if parametersFile == "" {
@@ -110,7 +124,7 @@ func runUpdateArtifact(cmd *cobra.Command) error {
 
 	// Default artifact name from Manifest file or artifact ID
 	if artifactName == "" {
-		headers, err := sync.GetManifestHeaders(manifestFile)
+		headers, err := file.GetManifestHeaders(manifestFile)
 		if err != nil {
 			return err
 		}
@@ -126,8 +140,21 @@ func runUpdateArtifact(cmd *cobra.Command) error {
 		}
 	}
 
+	rules, err := getBPMNRules(scriptMap, bpmnRulesFile, "tenant")
+	if err != nil {
+		return err
+	}
+
+	err = lintBeforeUpload(cmd, artifactDir, nil, nil)
+	if err != nil {
+		return err
+	}
+
 	// Initialise HTTP executer
-	serviceDetails := api.GetServiceDetails(cmd)
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
 	exe := api.InitHTTPExecuter(serviceDetails)
 
 	// Create integration package first if required
@@ -138,7 +165,17 @@ func runUpdateArtifact(cmd *cobra.Command) error {
 
 	synchroniser := sync.New(exe)
 
-	err = synchroniser.SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, parametersFile, scriptMap)
+	opts := &sync.UploadOptions{
+		Rules:                 rules,
+		ParamsCheck:           config.GetString(cmd, "params-check"),
+		RedeployOnParamChange: config.GetBool(cmd, "redeploy-on-param-change"),
+		Redeploy:              config.GetBool(cmd, "redeploy"),
+		DelayLength:           config.GetInt(cmd, "delay-length"),
+		MaxCheckLimit:         config.GetInt(cmd, "max-check-limit"),
+		VersionBump:           config.GetString(cmd, "version-bump"),
+		VersionBumpWriteBack:  config.GetBool(cmd, "version-bump-write-back"),
+	}
+	err = synchroniser.SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, parametersFile, opts)
 	if err != nil {
 		return err
 	}
@@ -167,3 +204,41 @@ func createPackage(packageId string, packageName string, exe *httpclnt.HTTPExecu
 	}
 	return nil
 }
+
+func getBPMNRules(scriptMap []string, rulesFile string, target string) ([]*file.BPMNRule, error) {
+	// Script collection map is always provided in the direction of the sync
+	rules := file.ScriptMapToBPMNRules(scriptMap)
+	if rulesFile != "" {
+		log.Info().Msgf("Using %v for converting values in IFlow BPMN2 XML", rulesFile)
+		fileRules, err := file.LoadBPMNRules(rulesFile)
+		if err != nil {
+			return nil, err
+		}
+		// Rules in file are defined from Git to tenant, so reverse them when syncing to Git
+		if target == "git" {
+			fileRules = file.ReverseBPMNRules(fileRules)
+		}
+		rules = append(rules, fileRules...)
+	}
+	return rules, nil
+}
+
+func validateParamsCheck(cmd *cobra.Command) error {
+	paramsCheck := config.GetString(cmd, "params-check")
+	switch paramsCheck {
+	case "WARN", "ERROR", "SKIP":
+	default:
+		return fmt.Errorf("invalid value for --params-check = %v", paramsCheck)
+	}
+	return nil
+}
+
+func validateVersionBump(cmd *cobra.Command) error {
+	versionBump := config.GetString(cmd, "version-bump")
+	switch versionBump {
+	case "", "patch", "minor", "major", "timestamp":
+	default:
+		return fmt.Errorf("invalid value for --version-bump = %v", versionBump)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/compare"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewCompareCommand() *cobra.Command {
+
+	compareCmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare integration packages between two tenants",
+		Long: `Compare the artifacts of integration packages between the tenants of two
+profiles in the config file (e.g. QA and PRD) in designtime version, content,
+deployed runtime version and externalized parameters, and generate a side-by-side
+report with file-level diffs.`,
+		Annotations: map[string]string{"profile-connections": "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the output format
+			outputFormat := config.GetString(cmd, "output-format")
+			switch outputFormat {
+			case "markdown", "html":
+			default:
+				return fmt.Errorf("invalid value for --output-format = %v", outputFormat)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runCompare(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	compareCmd.Flags().String("source-profile", "", "Name of tenant profile in config file of the source tenant")
+	compareCmd.Flags().String("target-profile", "", "Name of tenant profile in config file of the target tenant")
+	compareCmd.Flags().StringSlice("package-ids", nil, "Comma-separated list of Integration Package IDs to compare")
+	compareCmd.Flags().String("output-format", "markdown", "Format of comparison report. Allowed values: markdown, html")
+	compareCmd.Flags().String("file-output", "", "File to write the comparison report to. Defaults to standard output")
+
+	_ = compareCmd.MarkFlagRequired("source-profile")
+	_ = compareCmd.MarkFlagRequired("target-profile")
+	_ = compareCmd.MarkFlagRequired("package-ids")
+
+	return compareCmd
+}
+
+func runCompare(cmd *cobra.Command) error {
+	log.Info().Msg("Executing compare command")
+
+	sourceProfile := config.GetString(cmd, "source-profile")
+	targetProfile := config.GetString(cmd, "target-profile")
+	packageIds := str.TrimSlice(config.GetStringSlice(cmd, "package-ids"))
+	outputFormat := config.GetString(cmd, "output-format")
+	outputFile, err := config.GetStringWithEnvExpand(cmd, "file-output")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-output: %w", err)
+	}
+
+	sourceDetails, err := profileServiceDetails(cmd, sourceProfile)
+	if err != nil {
+		return err
+	}
+	targetDetails, err := profileServiceDetails(cmd, targetProfile)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msgf("📢 Begin comparing packages between %v and %v", sourceProfile, targetProfile)
+	comparer := compare.New(api.InitHTTPExecuter(sourceDetails), api.InitHTTPExecuter(targetDetails))
+	report, err := comparer.ComparePackages(packageIds, sourceProfile, targetProfile)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer f.Close()
+		w = f
+	}
+	err = report.Write(w, outputFormat)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		log.Info().Msgf("Comparison report written to %v", outputFile)
+	}
+
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	for _, status := range []string{compare.StatusSame, compare.StatusDifferent, compare.StatusMissingInSource, compare.StatusMissingInTarget} {
+		log.Info().Msgf("%v: %d artifact(s)", status, report.Count(status))
+	}
+	log.Info().Msgf("🏆 Comparison of %v and %v completed successfully", sourceProfile, targetProfile)
+	return nil
+}
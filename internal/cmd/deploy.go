@@ -8,6 +8,7 @@ import (
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
 	"github.com/engswee/flashpipe/internal/str"
+	"github.com/engswee/flashpipe/internal/sync"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -52,7 +53,10 @@ runtime of SAP Integration Suite tenant.`,
 }
 
 func runDeploy(cmd *cobra.Command) error {
-	serviceDetails := api.GetServiceDetails(cmd)
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
 
 	artifactType := config.GetString(cmd, "artifact-type")
 	log.Info().Msgf("Executing deploy %v command", artifactType)
@@ -62,7 +66,7 @@ func runDeploy(cmd *cobra.Command) error {
 	maxCheckLimit := config.GetInt(cmd, "max-check-limit")
 	compareVersions := config.GetBool(cmd, "compare-versions")
 
-	err := deployArtifacts(artifactIds, artifactType, delayLength, maxCheckLimit, compareVersions, serviceDetails)
+	err = deployArtifacts(artifactIds, artifactType, delayLength, maxCheckLimit, compareVersions, serviceDetails)
 	if err != nil {
 		return err
 	}
@@ -94,7 +98,7 @@ func deployArtifacts(artifactIds []string, artifactType string, delayLength int,
 
 	// Check deployment status of artifacts
 	for i, id := range artifactIds {
-		err := checkDeploymentStatus(rt, delayLength, maxCheckLimit, id)
+		err := sync.CheckDeploymentStatus(rt, delayLength, maxCheckLimit, id)
 		if err != nil {
 			return err
 		}
@@ -145,35 +149,3 @@ func deploySingle(artifact api.DesigntimeArtifact, runtime *api.Runtime, id stri
 	}
 	return nil
 }
-
-func checkDeploymentStatus(runtime *api.Runtime, delayLength int, maxCheckLimit int, id string) error {
-	log.Info().Msgf("Checking runtime status for artifact %v every %d seconds up to %d times", id, delayLength, maxCheckLimit)
-
-	for i := 0; i < maxCheckLimit; i++ {
-		version, status, err := runtime.Get(id)
-		if err != nil {
-			return err
-		}
-		log.Info().Msgf("Check %d - Current artifact runtime status = %s", i+1, status)
-		if version == "NOT_DEPLOYED" {
-			time.Sleep(time.Duration(delayLength) * time.Second)
-			continue
-		}
-		if status == "STARTED" {
-			return nil
-		} else if status != "STARTING" {
-			// If there is an error, delay before getting the error details as it sometimes return 204 when the error details are not available yet
-			time.Sleep(time.Duration(delayLength) * time.Second)
-			errorMessage, err := runtime.GetErrorInfo(id)
-			if err != nil {
-				return err
-			}
-			return fmt.Errorf("Artifact deployment unsuccessful, ended with status %s. Error message = %s", status, errorMessage)
-		}
-		if i == (maxCheckLimit - 1) {
-			return fmt.Errorf("Artifact status remained in %s after %d checks", status, maxCheckLimit)
-		}
-		time.Sleep(time.Duration(delayLength) * time.Second)
-	}
-	return nil
-}
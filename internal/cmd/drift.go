@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/drift"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/engswee/flashpipe/internal/sync"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// DriftExitCode is the exit code of the drift command when drift between the tenant and Git is found
+const DriftExitCode = 2
+
+func NewDriftCommand() *cobra.Command {
+
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Detect drift of integration packages between tenant and Git",
+		Long: `Compare the editable integration packages and their designtime artifacts
+in SAP Integration Suite tenant with a Git repository (in the structure of
+the snapshot command) without changing Git. Exits with code 2 when drift
+is found.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the output format
+			outputFormat := config.GetString(cmd, "output-format")
+			switch outputFormat {
+			case "table", "json":
+			default:
+				return fmt.Errorf("invalid value for --output-format = %v", outputFormat)
+			}
+			// If artifacts directory is provided, validate that is it a subdirectory of Git repo
+			gitRepoDir, err := config.GetStringWithEnvExpand(cmd, "dir-git-repo")
+			if err != nil {
+				return fmt.Errorf("security alert for --dir-git-repo: %w", err)
+			}
+			if gitRepoDir != "" {
+				artifactsDir, err := config.GetStringWithEnvExpand(cmd, "dir-artifacts")
+				if err != nil {
+					return fmt.Errorf("security alert for --dir-artifacts: %w", err)
+				}
+				gitRepoDirClean := filepath.Clean(gitRepoDir) + string(os.PathSeparator)
+				if artifactsDir != "" && !strings.HasPrefix(artifactsDir, gitRepoDirClean) {
+					return fmt.Errorf("--dir-artifacts [%v] should be a subdirectory of --dir-git-repo [%v]", artifactsDir, gitRepoDirClean)
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runDrift(cmd); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	driftCmd.Flags().String("dir-git-repo", "", "Directory of Git repository")
+	driftCmd.Flags().String("dir-artifacts", "", "Directory containing contents of artifacts (grouped into packages)")
+	driftCmd.Flags().StringSlice("ids-include", nil, "List of included package IDs")
+	driftCmd.Flags().StringSlice("ids-exclude", nil, "List of excluded package IDs")
+	driftCmd.Flags().StringSlice("package-ignore-fields", sync.DefaultPackageIgnoredFields, "Fields of package details that are ignored when checking for changes")
+	driftCmd.Flags().StringSlice("script-collection-map", nil, "Comma-separated source-target ID pairs for converting script collection references as when syncing to Git")
+	driftCmd.Flags().String("file-bpmn-rules", "", "JSON file with rules for converting values in IFlow BPMN2 XML (Git values to tenant values), reversed as when syncing to Git")
+	driftCmd.Flags().String("output-format", "table", "Format of drift report. Allowed values: table, json")
+	driftCmd.Flags().String("file-output", "", "File to write the drift report to. Defaults to standard output")
+
+	_ = driftCmd.MarkFlagRequired("dir-git-repo")
+	driftCmd.MarkFlagsMutuallyExclusive("ids-include", "ids-exclude")
+
+	return driftCmd
+}
+
+func runDrift(cmd *cobra.Command) error {
+	log.Info().Msg("Executing drift command")
+
+	gitRepoDir, err := config.GetStringWithEnvExpand(cmd, "dir-git-repo")
+	if err != nil {
+		return fmt.Errorf("security alert for --dir-git-repo: %w", err)
+	}
+	artifactsBaseDir, err := config.GetStringWithEnvExpandWithDefault(cmd, "dir-artifacts", gitRepoDir)
+	if err != nil {
+		return fmt.Errorf("security alert for --dir-artifacts: %w", err)
+	}
+	includedIds := str.TrimSlice(config.GetStringSlice(cmd, "ids-include"))
+	excludedIds := str.TrimSlice(config.GetStringSlice(cmd, "ids-exclude"))
+	packageIgnoredFields := str.TrimSlice(config.GetStringSlice(cmd, "package-ignore-fields"))
+	scriptCollectionMap := str.TrimSlice(config.GetStringSlice(cmd, "script-collection-map"))
+	bpmnRulesFile := config.GetString(cmd, "file-bpmn-rules")
+	outputFormat := config.GetString(cmd, "output-format")
+	outputFile, err := config.GetStringWithEnvExpand(cmd, "file-output")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-output: %w", err)
+	}
+
+	// The tenant is compared with Git in the same way as syncing to Git
+	rules, err := getBPMNRules(scriptCollectionMap, bpmnRulesFile, "git")
+	if err != nil {
+		return err
+	}
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	report, err := getDriftReport(serviceDetails, artifactsBaseDir, includedIds, excludedIds, packageIgnoredFields, rules)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer f.Close()
+		w = f
+	}
+	err = report.Write(w, outputFormat)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		log.Info().Msgf("Drift report written to %v", outputFile)
+	}
+
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	for _, status := range []string{drift.StatusChanged, drift.StatusMissingInGit, drift.StatusMissingInTenant, drift.StatusDraft} {
+		packages, artifacts := report.Count(status)
+		log.Info().Msgf("%v: %d package(s), %d artifact(s)", status, packages, artifacts)
+	}
+	if report.Drift {
+		return &ExitError{Code: DriftExitCode, Err: fmt.Errorf("Drift detected between tenant and Git")}
+	}
+	log.Info().Msg("🏆 No drift detected between tenant and Git")
+	return nil
+}
+
+func getDriftReport(serviceDetails *api.ServiceDetails, artifactsBaseDir string, includedIds []string, excludedIds []string, packageIgnoredFields []string, rules []*file.BPMNRule) (*drift.Report, error) {
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msg("📢 Begin checking drift between the tenant and Git")
+
+	// Initialise HTTP executer
+	exe := api.InitHTTPExecuter(serviceDetails)
+	synchroniser := sync.New(exe)
+	report := &drift.Report{Packages: []*drift.PackageResult{}}
+
+	// Packages in the tenant
+	ids, err := api.NewIntegrationPackage(exe).GetPackagesList(nil)
+	if err != nil {
+		return nil, err
+	}
+	tenantIds := map[string]bool{}
+	for _, id := range ids {
+		tenantIds[id] = true
+		if str.FilterIDs(id, includedIds, excludedIds) {
+			continue
+		}
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("Processing package %v", id)
+		packageDataFromTenant, readOnly, _, err := synchroniser.VerifyDownloadablePackage(id)
+		if err != nil {
+			return nil, err
+		}
+		if readOnly {
+			continue
+		}
+		result, err := synchroniser.PackageDrift(id, packageDataFromTenant, fmt.Sprintf("%v/%v", artifactsBaseDir, id), packageIgnoredFields, rules)
+		if err != nil {
+			return nil, err
+		}
+		report.Add(result)
+	}
+
+	// Packages in Git that do not exist in the tenant
+	entries, err := os.ReadDir(filepath.Clean(artifactsBaseDir))
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		id := entry.Name()
+		if !entry.IsDir() || tenantIds[id] || str.FilterIDs(id, includedIds, excludedIds) {
+			continue
+		}
+		packageDir := fmt.Sprintf("%v/%v", artifactsBaseDir, id)
+		result, err := synchroniser.PackageDrift(id, nil, packageDir, packageIgnoredFields, rules)
+		if err != nil {
+			return nil, err
+		}
+		// Directories that are not package directories are skipped
+		if result != nil {
+			report.Add(result)
+		}
+	}
+	return report, nil
+}
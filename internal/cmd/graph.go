@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/graph"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/engswee/flashpipe/internal/sync"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewGraphCommand() *cobra.Command {
+
+	graphCmd := &cobra.Command{
+		Use:   "graph [dir]",
+		Short: "Generate dependency graph of integration content",
+		Long: `Generate the dependency graph between IFlows, script collections,
+mappings, ProcessDirect endpoints and JMS queues from the artifact
+directories in a local directory (e.g. a Git checkout) or downloaded
+fresh from integration packages in the tenant.`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{"offline": "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the output format
+			outputFormat := config.GetString(cmd, "output-format")
+			switch outputFormat {
+			case "dot", "mermaid", "json":
+			default:
+				return fmt.Errorf("invalid value for --output-format = %v", outputFormat)
+			}
+			// Content is either from a local directory or downloaded from the tenant
+			packageIds := config.GetStringSlice(cmd, "package-ids")
+			if len(args) == 0 && len(packageIds) == 0 {
+				return fmt.Errorf("either directory argument or --package-ids must be provided")
+			}
+			if len(args) > 0 && len(packageIds) > 0 {
+				return fmt.Errorf("directory argument and --package-ids cannot be used together")
+			}
+			if len(packageIds) > 0 {
+				return validateConnectionFlags(cmd)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runGraph(cmd, args); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	graphCmd.Flags().StringSlice("package-ids", nil, "Comma-separated list of Integration Package IDs to download from tenant instead of using a local directory")
+	graphCmd.Flags().String("dir-work", "/tmp", "Working directory for downloaded artifacts")
+	graphCmd.Flags().String("impacted-by", "", "Only include the artifacts that are directly or indirectly impacted by changes to this artifact ID (or ProcessDirect:<address>, JMSQueue:<queue>)")
+	graphCmd.Flags().String("output-format", "dot", "Format of dependency graph. Allowed values: dot, mermaid, json")
+	graphCmd.Flags().String("file-output", "", "File to write the dependency graph to. Defaults to standard output")
+
+	return graphCmd
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	log.Info().Msg("Executing graph command")
+
+	packageIds := str.TrimSlice(config.GetStringSlice(cmd, "package-ids"))
+	workDir, err := config.GetStringWithEnvExpand(cmd, "dir-work")
+	if err != nil {
+		return fmt.Errorf("security alert for --dir-work: %w", err)
+	}
+	impactedBy := config.GetString(cmd, "impacted-by")
+	outputFormat := config.GetString(cmd, "output-format")
+	outputFile, err := config.GetStringWithEnvExpand(cmd, "file-output")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-output: %w", err)
+	}
+
+	var dir string
+	if len(args) > 0 {
+		dir = args[0]
+	} else {
+		dir, err = downloadPackages(cmd, packageIds, workDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("Parsing artifacts in %v", dir)
+	g, err := graph.Build(dir)
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Dependency graph contains %d node(s) and %d edge(s)", len(g.Nodes), len(g.Edges))
+
+	if impactedBy != "" {
+		g, err = g.ImpactedBy(impactedBy)
+		if err != nil {
+			return err
+		}
+		for _, node := range g.Nodes {
+			if node.Id != impactedBy {
+				log.Info().Msgf("%v %v is impacted by %v", node.Type, node.Id, impactedBy)
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer f.Close()
+		w = f
+	}
+	err = g.Write(w, outputFormat)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		log.Info().Msgf("🏆 Dependency graph written to %v", outputFile)
+	}
+	return nil
+}
+
+func downloadPackages(cmd *cobra.Command, packageIds []string, workDir string) (string, error) {
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return "", err
+	}
+	// Initialise HTTP executer
+	exe := api.InitHTTPExecuter(serviceDetails)
+	synchroniser := sync.New(exe)
+
+	downloadDir := filepath.Join(workDir, "graph")
+	err = os.RemoveAll(downloadDir)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	for _, packageId := range packageIds {
+		err = synchroniser.ArtifactsToGit(packageId, workDir, filepath.Join(downloadDir, packageId), nil, nil, "ADD", "ID", nil)
+		if err != nil {
+			return "", err
+		}
+	}
+	return downloadDir, nil
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/lint"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewLintCommand() *cobra.Command {
+
+	lintCmd := &cobra.Command{
+		Use:   "lint <dir>",
+		Short: "Lint designtime artifacts against design guidelines",
+		Long: `Lint the IFlow BPMN2 files, MANIFEST.MF and parameters.propdef of
+designtime artifacts in a local directory against built-in and
+user-defined rules. The directory can contain a single artifact or
+multiple artifacts in subdirectories.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{"offline": "true"},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// Validate the output format
+			outputFormat := config.GetString(cmd, "output-format")
+			switch outputFormat {
+			case "json", "sarif":
+			default:
+				return fmt.Errorf("invalid value for --output-format = %v", outputFormat)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runLint(cmd, args[0]); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	lintCmd.Flags().String("file-lint-config", "", "JSON file with settings of built-in rules and custom rules")
+	lintCmd.Flags().String("output-format", "json", "Format of lint report. Allowed values: json, sarif")
+	lintCmd.Flags().String("file-output", "", "File to write the lint report to. Defaults to standard output")
+
+	return lintCmd
+}
+
+func runLint(cmd *cobra.Command, dir string) error {
+	log.Info().Msg("Executing lint command")
+
+	configFile := config.GetString(cmd, "file-lint-config")
+	outputFormat := config.GetString(cmd, "output-format")
+	outputFile, err := config.GetStringWithEnvExpand(cmd, "file-output")
+	if err != nil {
+		return fmt.Errorf("security alert for --file-output: %w", err)
+	}
+
+	report, err := lintDir(configFile, dir, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer f.Close()
+		w = f
+	}
+	err = report.Write(w, outputFormat, cmd.Root().Version)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		log.Info().Msgf("Lint report written to %v", outputFile)
+	}
+
+	return checkLintErrors(report)
+}
+
+// lintBeforeUpload runs the linter as a gate before artifacts are uploaded when --lint is set
+func lintBeforeUpload(cmd *cobra.Command, dir string, includedIds []string, excludedIds []string) error {
+	if !config.GetBool(cmd, "lint") {
+		return nil
+	}
+	report, err := lintDir(config.GetString(cmd, "file-lint-config"), dir, includedIds, excludedIds)
+	if err != nil {
+		return err
+	}
+	return checkLintErrors(report)
+}
+
+func lintDir(configFile string, dir string, includedIds []string, excludedIds []string) (*lint.Report, error) {
+	lintConfig, err := lint.LoadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	linter, err := lint.New(lintConfig)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Linting artifacts in %v", dir)
+	report, err := linter.LintDir(dir, includedIds, excludedIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, finding := range report.Findings {
+		event := log.Info()
+		switch finding.Severity {
+		case lint.SeverityError:
+			event = log.Error()
+		case lint.SeverityWarning:
+			event = log.Warn()
+		}
+		event.Msgf("[%v] %v: %v (%v)", finding.RuleId, finding.ArtifactId, finding.Message, finding.File)
+	}
+	return report, nil
+}
+
+func checkLintErrors(report *lint.Report) error {
+	errorCount := report.Count(lint.SeverityError)
+	log.Info().Msgf("Linted %d artifact(s) with %d error(s), %d warning(s) and %d info finding(s)", report.Artifacts, errorCount, report.Count(lint.SeverityWarning), report.Count(lint.SeverityInfo))
+	if errorCount > 0 {
+		return fmt.Errorf("Lint failed with %d error(s)", errorCount)
+	}
+	log.Info().Msg("🏆 Lint completed successfully")
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/engswee/flashpipe/internal/analytics"
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/str"
 	"github.com/engswee/flashpipe/internal/sync"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -18,6 +19,9 @@ func NewPackageCommand() *cobra.Command {
 		Short:   "Create/update integration package",
 		Long: `Create or update integration package on the
 SAP Integration Suite tenant.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateVersionBump(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			startTime := time.Now()
 			if err = runUpdatePackage(cmd); err != nil {
@@ -30,6 +34,9 @@ SAP Integration Suite tenant.`,
 
 	// Define cobra flags, the default value has the lowest (least significant) precedence
 	packageCmd.Flags().String("package-file", "", "Path to location of package file")
+	packageCmd.Flags().String("version-bump", "", "Increment version based on version in tenant when changes are found. Allowed values: patch, minor, major, timestamp")
+	packageCmd.Flags().Bool("version-bump-write-back", false, "Write bumped version back to package file")
+	packageCmd.Flags().StringSlice("package-ignore-fields", sync.DefaultPackageIgnoredFields, "Fields of package details that are ignored when checking for changes")
 
 	_ = packageCmd.MarkFlagRequired("package-file")
 	return packageCmd
@@ -41,9 +48,17 @@ func runUpdatePackage(cmd *cobra.Command) error {
 	packageFile := config.GetString(cmd, "package-file")
 
 	// Initialise HTTP executer
-	serviceDetails := api.GetServiceDetails(cmd)
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
 	exe := api.InitHTTPExecuter(serviceDetails)
 	packageSynchroniser := sync.NewSyncer("tenant", "CPIPackage", exe)
 
-	return packageSynchroniser.Exec(sync.Request{PackageFile: packageFile})
+	return packageSynchroniser.Exec(sync.Request{
+		PackageFile:          packageFile,
+		VersionBump:          config.GetString(cmd, "version-bump"),
+		VersionBumpWriteBack: config.GetBool(cmd, "version-bump-write-back"),
+		IgnoredFields:        str.TrimSlice(config.GetStringSlice(cmd, "package-ignore-fields")),
+	})
 }
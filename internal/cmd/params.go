@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/analytics"
+	"github.com/engswee/flashpipe/internal/config"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/params"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func NewCheckParamsCommand() *cobra.Command {
+
+	checkParamsCmd := &cobra.Command{
+		Use:   "check-params <dir>",
+		Short: "Check externalized parameters of IFlows",
+		Long: `Cross-check parameters.prop against parameters.propdef and the
+placeholders used in the IFlow BPMN2 files. The directory can contain
+a single IFlow or multiple artifacts in subdirectories.`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{"offline": "true"},
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			startTime := time.Now()
+			if err = runCheckParams(cmd, args[0]); err != nil {
+				cmd.SilenceUsage = true
+			}
+			analytics.Log(cmd, err, startTime)
+			return
+		},
+	}
+
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	checkParamsCmd.Flags().String("file-param", "", "Use a different parameters.prop file instead of the default in src/main/resources/ (only for directory of single IFlow)")
+
+	return checkParamsCmd
+}
+
+func runCheckParams(cmd *cobra.Command, dir string) error {
+	log.Info().Msg("Executing check-params command")
+
+	parametersFile := config.GetString(cmd, "file-param")
+
+	var artifactDirs []string
+	if file.Exists(filepath.Join(dir, "META-INF", "MANIFEST.MF")) {
+		artifactDirs = append(artifactDirs, dir)
+	} else {
+		if parametersFile != "" {
+			return fmt.Errorf("--file-param can only be used when the directory contains a single IFlow")
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && file.Exists(filepath.Join(dir, entry.Name(), "META-INF", "MANIFEST.MF")) {
+				artifactDirs = append(artifactDirs, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	errorCount := 0
+	for _, artifactDir := range artifactDirs {
+		headers, err := file.GetManifestHeaders(filepath.Join(artifactDir, "META-INF", "MANIFEST.MF"))
+		if err != nil {
+			return err
+		}
+		if headers.Get("SAP-BundleType") != "IntegrationFlow" {
+			continue
+		}
+		artifactParametersFile := parametersFile
+		if artifactParametersFile == "" {
+			artifactParametersFile = filepath.Join(artifactDir, "src", "main", "resources", "parameters.prop")
+		}
+		log.Info().Msgf("Checking parameters of IFlow %v", file.GetArtifactId(headers))
+		report, err := params.Check(artifactDir, artifactParametersFile)
+		if err != nil {
+			return err
+		}
+		report.Log()
+		errorCount += report.Count(params.SeverityError)
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("Parameter check failed with %d error(s)", errorCount)
+	}
+	log.Info().Msg("🏆 Parameter check completed successfully")
+	return nil
+}
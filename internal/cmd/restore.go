@@ -6,6 +6,7 @@ import (
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
 	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/repo"
 	"github.com/engswee/flashpipe/internal/str"
 	"github.com/engswee/flashpipe/internal/sync"
 	"github.com/go-errors/errors"
@@ -13,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 )
@@ -22,7 +24,8 @@ func NewRestoreCommand() *cobra.Command {
 	restoreCmd := &cobra.Command{
 		Use:   "restore",
 		Short: "Restore integration packages from Git to tenant",
-		Long:  `Restore all editable integration packages from a Git repository to SAP Integration Suite tenant.`,
+		Long: `Restore all editable integration packages from a Git repository to SAP Integration Suite tenant.
+Use --git-ref to restore the packages as of an older commit, tag or date.`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 
 			// If artifacts directory is provided, validate that is it a subdirectory of Git repo
@@ -41,7 +44,7 @@ func NewRestoreCommand() *cobra.Command {
 					return fmt.Errorf("--dir-artifacts [%v] should be a subdirectory of --dir-git-repo [%v]", artifactsDir, gitRepoDirClean)
 				}
 			}
-			return nil
+			return validateVersionBump(cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			startTime := time.Now()
@@ -53,6 +56,15 @@ func NewRestoreCommand() *cobra.Command {
 		},
 	}
 
+	// Define cobra flags, the default value has the lowest (least significant) precedence
+	restoreCmd.Flags().Bool("redeploy", false, "Redeploy runtime artifacts and wait for deployment after changes in design or configured parameters")
+	restoreCmd.Flags().Int("delay-length", 30, "Delay (in seconds) between each check of artifact deployment status when redeploying")
+	restoreCmd.Flags().Int("max-check-limit", 10, "Max number of times to check for artifact deployment status when redeploying")
+	restoreCmd.Flags().StringSlice("package-ignore-fields", sync.DefaultPackageIgnoredFields, "Fields of package details that are ignored when checking for changes")
+	restoreCmd.Flags().String("version-bump", "", "Increment version based on version in tenant when changes are found. Allowed values: patch, minor, major, timestamp")
+	restoreCmd.Flags().String("git-ref", "", "Restore from this commit, tag, branch or date (YYYY-MM-DD or RFC 3339) instead of the working tree")
+	restoreCmd.Flags().StringSlice("artifact-ids", nil, "List of artifact IDs to restore. All artifacts of the packages are restored if not set")
+
 	return restoreCmd
 }
 
@@ -73,9 +85,43 @@ func runRestore(cmd *cobra.Command) error {
 	}
 	includedIds := str.TrimSlice(config.GetStringSlice(cmd, "ids-include"))
 	excludedIds := str.TrimSlice(config.GetStringSlice(cmd, "ids-exclude"))
+	artifactIds := str.TrimSlice(config.GetStringSlice(cmd, "artifact-ids"))
+	gitRef := config.GetString(cmd, "git-ref")
+
+	if gitRef != "" {
+		// Extract the artifacts directory as of the Git reference, so that the working tree is not changed
+		relativeDir, err := filepath.Rel(filepath.Clean(gitRepoDir), filepath.Clean(artifactsBaseDir))
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		refDir := filepath.Join(workDir, "restore_git_ref")
+		err = os.RemoveAll(refDir)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		defer os.RemoveAll(refDir)
+		commit, err := repo.ExtractDir(gitRepoDir, gitRef, relativeDir, refDir)
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("Restoring snapshot of commit %v", commit.Hash)
+		artifactsBaseDir = refDir
+	}
 
-	serviceDetails := api.GetServiceDetails(cmd)
-	err = restoreSnapshot(serviceDetails, artifactsBaseDir, workDir, includedIds, excludedIds)
+	opts := &sync.UploadOptions{
+		Redeploy:      config.GetBool(cmd, "redeploy"),
+		DelayLength:   config.GetInt(cmd, "delay-length"),
+		MaxCheckLimit: config.GetInt(cmd, "max-check-limit"),
+		VersionBump:   config.GetString(cmd, "version-bump"),
+	}
+
+	packageIgnoredFields := str.TrimSlice(config.GetStringSlice(cmd, "package-ignore-fields"))
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	err = restoreSnapshot(serviceDetails, artifactsBaseDir, workDir, includedIds, excludedIds, artifactIds, packageIgnoredFields, opts)
 	if err != nil {
 		return err
 	}
@@ -83,15 +129,14 @@ func runRestore(cmd *cobra.Command) error {
 	return nil
 }
 
-func restoreSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string, workDir string, includedIds []string, excludedIds []string) error {
+func restoreSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string, workDir string, includedIds []string, excludedIds []string, artifactIds []string, packageIgnoredFields []string, opts *sync.UploadOptions) error {
 	log.Info().Msg("---------------------------------------------------------------------------------")
 	log.Info().Msg("📢 Begin restoring snapshot to the tenant")
 
-	// Get directory list
 	baseSourceDir := filepath.Clean(artifactsBaseDir)
-	entries, err := os.ReadDir(baseSourceDir)
+	packageIds, err := getRestorePackageIds(baseSourceDir, includedIds, excludedIds, artifactIds)
 	if err != nil {
-		return errors.Wrap(err, 0)
+		return err
 	}
 
 	// Initialise HTTP executer
@@ -99,7 +144,42 @@ func restoreSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string
 	packageSynchroniser := sync.NewSyncer("tenant", "CPIPackage", exe)
 	artifactsSynchroniser := sync.New(exe)
 
-	// Go through each directory and check if there is an integration package details in it, if yes, then proceed to restore integration package and artifacts
+	for _, packageId := range packageIds {
+		packageDir := fmt.Sprintf("%v/%v", baseSourceDir, packageId)
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("Restoring integration package %v", packageId)
+
+		// 1 - Sync CPI Integration Package
+		err = packageSynchroniser.Exec(sync.Request{ArtifactsDir: packageDir, VersionBump: opts.VersionBump, IgnoredFields: packageIgnoredFields})
+		if err != nil {
+			return err
+		}
+
+		// 2 - Sync CPI Artifacts
+		err = artifactsSynchroniser.ArtifactsToTenant(packageId, workDir, packageDir, artifactIds, nil, opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msg("🏆 Completed restoring snapshot to the tenant")
+	return nil
+}
+
+// getRestorePackageIds returns the IDs of the packages to restore. If artifactIds is set, only the packages containing
+// these artifacts are restored, and an error is returned if any of the artifacts is not found
+func getRestorePackageIds(baseSourceDir string, includedIds []string, excludedIds []string, artifactIds []string) ([]string, error) {
+	// Get directory list
+	entries, err := os.ReadDir(baseSourceDir)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	// Go through each directory and check if there is an integration package details in it, if yes, then select it for
+	// restoring the integration package and artifacts
+	var packageIds []string
+	foundArtifactIds := map[string]bool{}
 	for _, entry := range entries {
 		packageId := entry.Name()
 		packageDir := fmt.Sprintf("%v/%v", baseSourceDir, packageId)
@@ -112,25 +192,35 @@ func restoreSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string
 				if str.FilterIDs(packageId, includedIds, excludedIds) {
 					continue
 				}
-
-				// 1 - Sync CPI Integration Package
-				err = packageSynchroniser.Exec(sync.Request{ArtifactsDir: packageDir})
-				if err != nil {
-					return err
-				}
-
-				// 2 - Sync CPI Artifacts
-				err = artifactsSynchroniser.ArtifactsToTenant(packageId, workDir, packageDir, nil, nil)
-				if err != nil {
-					return err
+				// Only packages containing the selected artifacts are restored
+				if len(artifactIds) > 0 {
+					gitArtifactIds, err := sync.GitArtifactIds(packageDir)
+					if err != nil {
+						return nil, err
+					}
+					selected := false
+					for _, artifactId := range gitArtifactIds {
+						if slices.Contains(artifactIds, artifactId) {
+							foundArtifactIds[artifactId] = true
+							selected = true
+						}
+					}
+					if !selected {
+						log.Info().Msgf("Skipping package %v as it does not contain any artifact in --artifact-ids", packageId)
+						continue
+					}
 				}
+				packageIds = append(packageIds, packageId)
 			} else {
 				log.Warn().Msgf("Skipping directory as integration package file %v is not found", packageFile)
 			}
 		}
 	}
-
-	log.Info().Msg("---------------------------------------------------------------------------------")
-	log.Info().Msg("🏆 Completed restoring snapshot to the tenant")
-	return nil
+	// Check all selected artifacts exist before changing the tenant
+	for _, artifactId := range artifactIds {
+		if !foundArtifactIds[artifactId] {
+			return nil, fmt.Errorf("Artifact %v in --artifact-ids does not exist in the selected packages", artifactId)
+		}
+	}
+	return packageIds, nil
 }
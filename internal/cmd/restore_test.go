@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRestorePackage(t *testing.T, baseDir string, packageId string, artifactIds ...string) {
+	packageDir := filepath.Join(baseDir, packageId)
+	_ = os.MkdirAll(packageDir, os.ModePerm)
+	_ = os.WriteFile(filepath.Join(packageDir, packageId+".json"), []byte(`{"d":{"Id":"`+packageId+`"}}`), 0644)
+	for _, artifactId := range artifactIds {
+		metaInfDir := filepath.Join(packageDir, artifactId, "META-INF")
+		_ = os.MkdirAll(metaInfDir, os.ModePerm)
+		err := os.WriteFile(filepath.Join(metaInfDir, "MANIFEST.MF"), []byte("Manifest-Version: 1.0\nBundle-SymbolicName: "+artifactId+"\n\n"), 0644)
+		if err != nil {
+			t.Fatalf("WriteFile failed with error - %v", err)
+		}
+	}
+}
+
+func TestGetRestorePackageIds(t *testing.T) {
+	baseDir := t.TempDir()
+	writeRestorePackage(t, baseDir, "PackageA", "IFlowA1", "IFlowA2")
+	writeRestorePackage(t, baseDir, "PackageB", "IFlowB1")
+
+	packageIds, err := getRestorePackageIds(baseDir, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PackageA", "PackageB"}, packageIds, "Expected all packages without --artifact-ids")
+
+	packageIds, err = getRestorePackageIds(baseDir, nil, nil, []string{"IFlowB1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PackageB"}, packageIds, "Expected only package containing selected artifact")
+
+	_, err = getRestorePackageIds(baseDir, nil, nil, []string{"IFlowB1", "IFlowC1"})
+	assert.EqualError(t, err, "Artifact IFlowC1 in --artifact-ids does not exist in the selected packages")
+
+	_, err = getRestorePackageIds(baseDir, nil, []string{"PackageB"}, []string{"IFlowB1"})
+	assert.EqualError(t, err, "Artifact IFlowB1 in --artifact-ids does not exist in the selected packages", "Expected artifact of excluded package not found")
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
 	"github.com/engswee/flashpipe/internal/logger"
 	"github.com/rs/zerolog/log"
@@ -37,6 +39,7 @@ automating time-consuming manual tasks like:
 	}
 
 	rootCmd.PersistentFlags().String("config", "", "config file (default is $HOME/flashpipe.yaml)")
+	rootCmd.PersistentFlags().String("profile", "", "Name of tenant profile in config file to use")
 
 	// Define cobra flags, the default value has the lowest (least significant) precedence
 	rootCmd.PersistentFlags().String("tmn-host", "", "Host for tenant management node of Cloud Integration or API Portal node of APIM excluding https://")
@@ -46,12 +49,18 @@ automating time-consuming manual tasks like:
 	rootCmd.PersistentFlags().String("oauth-clientid", "", "Client ID for using OAuth")
 	rootCmd.PersistentFlags().String("oauth-clientsecret", "", "Client Secret for using OAuth")
 	rootCmd.PersistentFlags().String("oauth-path", "/oauth/token", "Path for OAuth token server")
+	rootCmd.PersistentFlags().String("service-key", "", "BTP service key file or JSON content, used instead of other connection flags")
+	rootCmd.PersistentFlags().String("tls-cert", "", "Client certificate file (PEM) for X.509 certificate authentication")
+	rootCmd.PersistentFlags().String("tls-key", "", "Private key file (PEM) of client certificate")
+	rootCmd.PersistentFlags().String("tls-pkcs12", "", "Client certificate and private key in PKCS#12 file, used instead of --tls-cert and --tls-key")
+	rootCmd.PersistentFlags().String("tls-pkcs12-password", "", "Password of PKCS#12 file")
+	rootCmd.PersistentFlags().String("tls-ca-bundle", "", "CA certificates file (PEM) trusted in addition to the system CA certificates")
 
 	rootCmd.PersistentFlags().Bool("debug", false, "Show debug logs")
 
-	_ = rootCmd.MarkPersistentFlagRequired("tmn-host")
 	rootCmd.MarkFlagsRequiredTogether("tmn-userid", "tmn-password")
-	rootCmd.MarkFlagsRequiredTogether("oauth-host", "oauth-clientid", "oauth-clientsecret")
+	rootCmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+	rootCmd.MarkFlagsRequiredTogether("oauth-host", "oauth-clientid")
 
 	return rootCmd
 }
@@ -61,7 +70,16 @@ automating time-consuming manual tasks like:
 func Execute() {
 
 	rootCmd := NewCmdRoot()
-	rootCmd.AddCommand(NewDeployCommand())
+	deployCmd := NewDeployCommand()
+	deployCmd.AddCommand(NewDeployAPIMCommand())
+	rootCmd.AddCommand(deployCmd)
+	undeployCmd := NewUndeployCommand()
+	undeployCmd.AddCommand(NewUndeployAPIMCommand())
+	rootCmd.AddCommand(undeployCmd)
+	apimCmd := NewAPIMGroupCommand()
+	apimCmd.AddCommand(NewImportOpenAPICommand())
+	apimCmd.AddCommand(NewAPIMAnalyticsCommand())
+	rootCmd.AddCommand(apimCmd)
 	syncCmd := NewSyncCommand()
 	syncCmd.AddCommand(NewAPIMCommand())
 	rootCmd.AddCommand(syncCmd)
@@ -72,16 +90,40 @@ func Execute() {
 	snapshotCmd := NewSnapshotCommand()
 	snapshotCmd.AddCommand(NewRestoreCommand())
 	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(NewLintCommand())
+	rootCmd.AddCommand(NewGraphCommand())
+	rootCmd.AddCommand(NewCheckParamsCommand())
+	rootCmd.AddCommand(NewDriftCommand())
+	rootCmd.AddCommand(NewCompareCommand())
 
 	err := rootCmd.Execute()
 
 	if err != nil {
 		// Display stack trace based on type of error
 		msg := logger.GetErrorDetails(err)
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			log.Error().Msg(msg)
+			os.Exit(exitErr.Code)
+		}
 		log.Fatal().Msg(msg)
 	}
 }
 
+// ExitError is returned by commands that exit with a specific exit code instead of 1
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
 func initializeConfig(cmd *cobra.Command) error {
 	cfgFile := config.GetString(cmd, "config")
 	if cfgFile != "" {
@@ -114,6 +156,21 @@ func initializeConfig(cmd *cobra.Command) error {
 	// Bind to environment variables
 	viper.AutomaticEnv()
 
+	// Settings of the selected profile override the top-level settings of the config file
+	profile := config.GetString(cmd, "profile")
+	if profile == "" {
+		profile = viper.GetString("profile")
+	}
+	if profile != "" {
+		settings, err := profileSettings(cmd, profile)
+		if err != nil {
+			return err
+		}
+		if err = viper.MergeConfigMap(settings); err != nil {
+			return err
+		}
+	}
+
 	// Bind the current command's flags to viper
 	bindFlags(cmd)
 
@@ -122,8 +179,12 @@ func initializeConfig(cmd *cobra.Command) error {
 		viper.Set("debug", config.GetBool(cmd, "debug"))
 	}
 
-	if config.GetString(cmd, "oauth-host") == "" && config.GetString(cmd, "tmn-userid") == "" {
-		return fmt.Errorf("required flag \"tmn-userid\" (Basic Auth) or \"oauth-host\" (OAuth) not set")
+	// Commands that work only on local files (offline) or connect to the tenants of profiles (profile-connections)
+	// do not need the connection flags
+	if cmd.Annotations["offline"] != "true" && cmd.Annotations["profile-connections"] != "true" {
+		if err := validateConnectionFlags(cmd); err != nil {
+			return err
+		}
 	}
 
 	logger.InitConsoleLogger(viper.GetBool("debug"))
@@ -142,3 +203,66 @@ func bindFlags(cmd *cobra.Command) {
 		}
 	})
 }
+
+// profileSettings returns the settings of the named profile in the config file
+func profileSettings(cmd *cobra.Command, name string) (map[string]any, error) {
+	profiles := viper.GetStringMap(config.ProfilesKey)
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("Profile %v is used but config file does not have %v", name, config.ProfilesKey)
+	}
+	return config.ResolveProfile(profiles, name, flagNames(cmd.Root()))
+}
+
+// flagNames returns the names of the flags of the command and all its subcommands
+func flagNames(cmd *cobra.Command) map[string]bool {
+	names := map[string]bool{}
+	collect := func(f *pflag.Flag) {
+		names[f.Name] = true
+	}
+	cmd.PersistentFlags().VisitAll(collect)
+	cmd.Flags().VisitAll(collect)
+	for _, child := range cmd.Commands() {
+		for name := range flagNames(child) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// profileServiceDetails returns the connection details of the tenant of the named profile in the config file
+func profileServiceDetails(cmd *cobra.Command, name string) (*api.ServiceDetails, error) {
+	settings, err := profileSettings(cmd, name)
+	if err != nil {
+		return nil, err
+	}
+	serviceDetails, err := api.GetServiceDetailsFromProfile(settings)
+	if err != nil {
+		return nil, fmt.Errorf("Profile %v: %w", name, err)
+	}
+	if err = validateServiceDetails(serviceDetails); err != nil {
+		return nil, fmt.Errorf("Profile %v: %w", name, err)
+	}
+	return serviceDetails, nil
+}
+
+func validateConnectionFlags(cmd *cobra.Command) error {
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	return validateServiceDetails(serviceDetails)
+}
+
+func validateServiceDetails(serviceDetails *api.ServiceDetails) error {
+	if serviceDetails.Host == "" {
+		return fmt.Errorf("required flag(s) \"tmn-host\" not set")
+	}
+	if serviceDetails.OauthHost == "" && serviceDetails.Userid == "" && serviceDetails.ClientCertificate == nil {
+		return fmt.Errorf("required flag \"tmn-userid\" (Basic Auth), \"oauth-host\" (OAuth) or \"tls-cert\" (client certificate) not set")
+	}
+	// OAuth with a client certificate does not use a client secret
+	if serviceDetails.OauthHost != "" && serviceDetails.OauthClientSecret == "" && serviceDetails.ClientCertificate == nil {
+		return fmt.Errorf("required flag \"oauth-clientsecret\" or \"tls-cert\" (client certificate) not set for OAuth")
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateServiceDetails(t *testing.T) {
+	assert.NoError(t, validateServiceDetails(&api.ServiceDetails{Host: "dummy", Userid: "dummy", Password: "dummy"}), "Expected Basic Auth valid")
+	assert.NoError(t, validateServiceDetails(&api.ServiceDetails{Host: "dummy", OauthHost: "dummy", OauthClientId: "dummy", OauthClientSecret: "dummy"}), "Expected OAuth with client secret valid")
+	assert.NoError(t, validateServiceDetails(&api.ServiceDetails{Host: "dummy", OauthHost: "dummy", OauthClientId: "dummy", ClientCertificate: &tls.Certificate{}}), "Expected OAuth with client certificate valid")
+
+	assert.ErrorContains(t, validateServiceDetails(&api.ServiceDetails{Userid: "dummy"}), "tmn-host", "Expected missing host invalid")
+	assert.ErrorContains(t, validateServiceDetails(&api.ServiceDetails{Host: "dummy"}), "tmn-userid", "Expected missing authentication invalid")
+	assert.ErrorContains(t, validateServiceDetails(&api.ServiceDetails{Host: "dummy", OauthHost: "dummy", OauthClientId: "dummy"}), "oauth-clientsecret", "Expected OAuth without client secret or certificate invalid")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/config"
 	"github.com/engswee/flashpipe/internal/repo"
+	"github.com/engswee/flashpipe/internal/state"
 	"github.com/engswee/flashpipe/internal/str"
 	"github.com/engswee/flashpipe/internal/sync"
 	"github.com/rs/zerolog/log"
@@ -48,6 +49,13 @@ tenant to a Git repository.`,
 					return fmt.Errorf("--dir-artifacts [%v] should be a subdirectory of --dir-git-repo [%v]", artifactsDir, gitRepoDirClean)
 				}
 			}
+			// Validate date of modification filter
+			modifiedSince := config.GetString(cmd, "filter-modified-since")
+			if modifiedSince != "" {
+				if _, err = time.Parse(time.DateOnly, modifiedSince); err != nil {
+					return fmt.Errorf("invalid value for --filter-modified-since = %v", modifiedSince)
+				}
+			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
@@ -73,6 +81,12 @@ tenant to a Git repository.`,
 	snapshotCmd.Flags().String("git-commit-email", "41898282+github-actions[bot]@users.noreply.github.com", "Email used in commit")
 	snapshotCmd.Flags().Bool("git-skip-commit", false, "Skip committing changes to Git repository")
 	snapshotCmd.Flags().Bool("sync-package-details", true, "Sync details of Integration Packages")
+	snapshotCmd.Flags().Bool("full", false, "Download all artifacts instead of only artifacts with changed version or modification date since the previous snapshot")
+	snapshotCmd.Flags().String("filter-vendor", "", "Only include packages of this vendor")
+	snapshotCmd.Flags().String("filter-name", "", "Only include packages with this name. Use * at the start and/or end for partial matches")
+	snapshotCmd.Flags().String("filter-modified-since", "", "Only include packages modified on or after this date (YYYY-MM-DD)")
+	snapshotCmd.Flags().String("filter", "", "Additional OData $filter expression for packages")
+	snapshotCmd.Flags().StringSlice("package-ignore-fields", sync.DefaultPackageIgnoredFields, "Fields of package details that are ignored when checking for changes")
 
 	_ = snapshotCmd.MarkFlagRequired("dir-git-repo")
 	snapshotCmd.MarkFlagsMutuallyExclusive("ids-include", "ids-exclude")
@@ -103,9 +117,25 @@ func runSnapshot(cmd *cobra.Command) error {
 	commitEmail := config.GetString(cmd, "git-commit-email")
 	skipCommit := config.GetBool(cmd, "git-skip-commit")
 	syncPackageLevelDetails := config.GetBool(cmd, "sync-package-details")
+	packageIgnoredFields := str.TrimSlice(config.GetStringSlice(cmd, "package-ignore-fields"))
+
+	query := getPackageQuery(cmd)
+	full := config.GetBool(cmd, "full")
 
-	serviceDetails := api.GetServiceDetails(cmd)
-	err = getTenantSnapshot(serviceDetails, artifactsBaseDir, workDir, draftHandling, syncPackageLevelDetails, includedIds, excludedIds)
+	st, err := state.Load(filepath.Join(gitRepoDir, state.DefaultFile))
+	if err != nil {
+		return err
+	}
+
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
+	err = getTenantSnapshot(serviceDetails, artifactsBaseDir, workDir, draftHandling, syncPackageLevelDetails, packageIgnoredFields, query, st, full, includedIds, excludedIds)
+	if err != nil {
+		return err
+	}
+	err = st.Save()
 	if err != nil {
 		return err
 	}
@@ -119,7 +149,7 @@ func runSnapshot(cmd *cobra.Command) error {
 	return nil
 }
 
-func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string, workDir string, draftHandling string, syncPackageLevelDetails bool, includedIds []string, excludedIds []string) error {
+func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir string, workDir string, draftHandling string, syncPackageLevelDetails bool, packageIgnoredFields []string, query *api.Query, st *state.State, full bool, includedIds []string, excludedIds []string) error {
 	log.Info().Msg("---------------------------------------------------------------------------------")
 	log.Info().Msg("📢 Begin taking a snapshot of the tenant")
 
@@ -128,7 +158,7 @@ func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir stri
 
 	// Get packages from the tenant
 	ip := api.NewIntegrationPackage(exe)
-	ids, err := ip.GetPackagesList()
+	ids, err := ip.GetPackagesList(query)
 	if err != nil {
 		return err
 	}
@@ -138,6 +168,7 @@ func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir stri
 
 	log.Info().Msgf("Processing %d packages", len(ids))
 	synchroniser := sync.New(exe)
+	synchroniser.UseState(st, full)
 	for i, id := range ids {
 		log.Info().Msg("---------------------------------------------------------------------------------")
 		log.Info().Msgf("Processing package %d/%d - ID: %v", i+1, len(ids), id)
@@ -153,7 +184,7 @@ func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir stri
 				continue
 			}
 			if syncPackageLevelDetails {
-				err = synchroniser.PackageToGit(packageDataFromTenant, id, packageWorkingDir, packageArtifactsDir)
+				err = synchroniser.PackageToGit(packageDataFromTenant, id, packageWorkingDir, packageArtifactsDir, packageIgnoredFields)
 				if err != nil {
 					return err
 				}
@@ -170,3 +201,25 @@ func getTenantSnapshot(serviceDetails *api.ServiceDetails, artifactsBaseDir stri
 	log.Info().Msg("🏆 Completed taking a snapshot of the tenant")
 	return nil
 }
+
+// getPackageQuery returns the query to filter packages on the server side, or nil if there are no filters
+func getPackageQuery(cmd *cobra.Command) *api.Query {
+	var vendorFilter, nameFilter, modifiedFilter string
+	if vendor := config.GetString(cmd, "filter-vendor"); vendor != "" {
+		vendorFilter = api.FilterEquals("Vendor", vendor)
+	}
+	if name := config.GetString(cmd, "filter-name"); name != "" {
+		nameFilter = api.FilterPattern("Name", name)
+	}
+	if modifiedSince := config.GetString(cmd, "filter-modified-since"); modifiedSince != "" {
+		// ModifiedDate of packages is the epoch time in milliseconds as string
+		date, _ := time.Parse(time.DateOnly, modifiedSince)
+		modifiedFilter = fmt.Sprintf("ModifiedDate ge '%d'", date.UnixMilli())
+	}
+	filter := api.FilterAnd(vendorFilter, nameFilter, modifiedFilter, config.GetString(cmd, "filter"))
+	if filter == "" {
+		return nil
+	}
+	log.Info().Msgf("Filtering packages with $filter = %v", filter)
+	return &api.Query{Filter: filter}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/state"
 	"github.com/engswee/flashpipe/internal/str"
 
 	"github.com/engswee/flashpipe/internal/analytics"
@@ -62,7 +63,25 @@ tenant and a Git repository.`,
 			default:
 				return fmt.Errorf("invalid value for --target = %v", target)
 			}
-			return nil
+			// Validate Parameter Source
+			paramSource := config.GetString(cmd, "param-source")
+			switch paramSource {
+			case "DEFAULT":
+			case "ENV":
+				if config.GetString(cmd, "dir-param-env") == "" {
+					return fmt.Errorf("--dir-param-env is required when --param-source = ENV")
+				}
+			case "FILE":
+				if config.GetString(cmd, "file-param") == "" {
+					return fmt.Errorf("--file-param is required when --param-source = FILE")
+				}
+			default:
+				return fmt.Errorf("invalid value for --param-source = %v", paramSource)
+			}
+			if err := validateParamsCheck(cmd); err != nil {
+				return err
+			}
+			return validateVersionBump(cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			startTime := time.Now()
@@ -81,15 +100,30 @@ tenant and a Git repository.`,
 	syncCmd.PersistentFlags().String("dir-work", "/tmp", "Working directory for in-transit files")
 	syncCmd.Flags().String("dir-naming-type", "ID", "Name artifact directory by ID or Name. Allowed values: ID, NAME")
 	syncCmd.Flags().String("draft-handling", "SKIP", "Handling when artifact is in draft version. Allowed values: SKIP, ADD, ERROR")
-	syncCmd.PersistentFlags().StringSlice("ids-include", nil, "List of included artifact IDs")
-	syncCmd.PersistentFlags().StringSlice("ids-exclude", nil, "List of excluded artifact IDs")
+	syncCmd.PersistentFlags().StringSlice("ids-include", nil, "List of included artifact IDs (API proxy names for sync apim)")
+	syncCmd.PersistentFlags().StringSlice("ids-exclude", nil, "List of excluded artifact IDs (API proxy names for sync apim)")
 	syncCmd.PersistentFlags().String("target", "git", "Target of sync. Allowed values: git, tenant")
 	syncCmd.PersistentFlags().String("git-commit-msg", "Sync repo from tenant", "Message used in commit")
 	syncCmd.PersistentFlags().String("git-commit-user", "github-actions[bot]", "User used in commit")
 	syncCmd.PersistentFlags().String("git-commit-email", "41898282+github-actions[bot]@users.noreply.github.com", "Email used in commit")
 	syncCmd.Flags().StringSlice("script-collection-map", nil, "Comma-separated source-target ID pairs for converting script collection references during sync ")
+	syncCmd.Flags().String("file-bpmn-rules", "", "JSON file with rules for converting values in IFlow BPMN2 XML (Git values to tenant values), reversed when syncing to Git")
+	syncCmd.Flags().Bool("lint", false, "Lint artifacts before syncing to tenant and stop when there are lint errors")
+	syncCmd.Flags().String("file-lint-config", "", "JSON file with settings of built-in lint rules and custom lint rules")
+	syncCmd.Flags().String("params-check", "WARN", "Handling of findings when checking parameters.prop against parameters.propdef and IFlow before syncing to tenant. Allowed values: WARN, ERROR, SKIP")
+	syncCmd.Flags().String("param-source", "DEFAULT", "Source of parameters.prop file when syncing to tenant. Allowed values: DEFAULT, ENV, FILE")
+	syncCmd.Flags().String("dir-param-env", "", "Directory containing <artifact ID>.prop parameters files when --param-source = ENV")
+	syncCmd.Flags().String("file-param", "", "Parameters file used for all artifacts when --param-source = FILE. {ARTIFACT_ID} is replaced with the artifact ID")
+	syncCmd.Flags().Bool("redeploy-on-param-change", false, "Redeploy instead of undeploying runtime artifact when configured parameters are changed")
+	syncCmd.Flags().Bool("redeploy", false, "Redeploy runtime artifacts and wait for deployment after changes in design or configured parameters")
+	syncCmd.Flags().Int("delay-length", 30, "Delay (in seconds) between each check of artifact deployment status when redeploying")
+	syncCmd.Flags().Int("max-check-limit", 10, "Max number of times to check for artifact deployment status when redeploying")
+	syncCmd.Flags().String("version-bump", "", "Increment version based on version in tenant when changes are found. Allowed values: patch, minor, major, timestamp")
+	syncCmd.Flags().Bool("version-bump-write-back", false, "Write bumped Bundle-Version back to MANIFEST.MF in Git repository and commit it")
 	syncCmd.PersistentFlags().Bool("git-skip-commit", false, "Skip committing changes to Git repository")
 	syncCmd.Flags().Bool("sync-package-details", false, "Sync details of Integration Package")
+	syncCmd.Flags().StringSlice("package-ignore-fields", sync.DefaultPackageIgnoredFields, "Fields of package details that are ignored when checking for changes")
+	syncCmd.Flags().Bool("incremental", false, "Skip downloading artifacts with unchanged content hash and version based on the state file in the Git repository")
 
 	_ = syncCmd.MarkFlagRequired("package-id")
 	_ = syncCmd.MarkFlagRequired("dir-git-repo")
@@ -122,15 +156,30 @@ func runSync(cmd *cobra.Command) error {
 	commitUser := config.GetString(cmd, "git-commit-user")
 	commitEmail := config.GetString(cmd, "git-commit-email")
 	scriptCollectionMap := str.TrimSlice(config.GetStringSlice(cmd, "script-collection-map"))
+	bpmnRulesFile := config.GetString(cmd, "file-bpmn-rules")
 	skipCommit := config.GetBool(cmd, "git-skip-commit")
 	syncPackageLevelDetails := config.GetBool(cmd, "sync-package-details")
+	packageIgnoredFields := str.TrimSlice(config.GetStringSlice(cmd, "package-ignore-fields"))
 	target := config.GetString(cmd, "target")
+	incremental := config.GetBool(cmd, "incremental")
 
-	serviceDetails := api.GetServiceDetails(cmd)
+	serviceDetails, err := api.GetServiceDetails(cmd)
+	if err != nil {
+		return err
+	}
 	// Initialise HTTP executer
 	exe := api.InitHTTPExecuter(serviceDetails)
 	synchroniser := sync.New(exe)
 
+	var st *state.State
+	if incremental {
+		st, err = state.Load(filepath.Join(gitRepoDir, state.DefaultFile))
+		if err != nil {
+			return err
+		}
+		synchroniser.UseState(st, false)
+	}
+
 	// Sync from tenant to Git
 	if target == "git" {
 		packageDataFromTenant, readOnly, _, err := synchroniser.VerifyDownloadablePackage(packageId)
@@ -139,16 +188,26 @@ func runSync(cmd *cobra.Command) error {
 		}
 		if !readOnly {
 			if syncPackageLevelDetails {
-				err = synchroniser.PackageToGit(packageDataFromTenant, packageId, workDir, artifactsDir)
+				err = synchroniser.PackageToGit(packageDataFromTenant, packageId, workDir, artifactsDir, packageIgnoredFields)
 				if err != nil {
 					return err
 				}
 			}
 
-			err = synchroniser.ArtifactsToGit(packageId, workDir, artifactsDir, includedIds, excludedIds, draftHandling, dirNamingType, scriptCollectionMap)
+			rules, err := getBPMNRules(scriptCollectionMap, bpmnRulesFile, target)
+			if err != nil {
+				return err
+			}
+			err = synchroniser.ArtifactsToGit(packageId, workDir, artifactsDir, includedIds, excludedIds, draftHandling, dirNamingType, rules)
 			if err != nil {
 				return err
 			}
+			if st != nil {
+				err = st.Save()
+				if err != nil {
+					return err
+				}
+			}
 
 			if !skipCommit {
 				err = repo.CommitToRepo(gitRepoDir, commitMsg, commitUser, commitEmail)
@@ -161,6 +220,11 @@ func runSync(cmd *cobra.Command) error {
 
 	// Sync from Git to tenant
 	if target == "tenant" {
+		err = lintBeforeUpload(cmd, artifactsDir, includedIds, excludedIds)
+		if err != nil {
+			return err
+		}
+
 		// Check for existence of package in tenant
 		_, _, packageExists, err := synchroniser.VerifyDownloadablePackage(packageId)
 		if !packageExists {
@@ -180,10 +244,54 @@ func runSync(cmd *cobra.Command) error {
 			return err
 		}
 
-		err = synchroniser.ArtifactsToTenant(packageId, workDir, artifactsDir, includedIds, excludedIds)
+		rules, err := getBPMNRules(nil, bpmnRulesFile, target)
+		if err != nil {
+			return err
+		}
+		paramEnvDir, err := config.GetStringWithEnvExpand(cmd, "dir-param-env")
+		if err != nil {
+			return fmt.Errorf("security alert for --dir-param-env: %w", err)
+		}
+		paramFile, err := config.GetStringWithEnvExpand(cmd, "file-param")
+		if err != nil {
+			return fmt.Errorf("security alert for --file-param: %w", err)
+		}
+		opts := &sync.UploadOptions{
+			Rules:                 rules,
+			ParamsCheck:           config.GetString(cmd, "params-check"),
+			ParamSource:           config.GetString(cmd, "param-source"),
+			ParamEnvDir:           paramEnvDir,
+			ParamFile:             paramFile,
+			RedeployOnParamChange: config.GetBool(cmd, "redeploy-on-param-change"),
+			Redeploy:              config.GetBool(cmd, "redeploy"),
+			DelayLength:           config.GetInt(cmd, "delay-length"),
+			MaxCheckLimit:         config.GetInt(cmd, "max-check-limit"),
+			VersionBump:           config.GetString(cmd, "version-bump"),
+			VersionBumpWriteBack:  config.GetBool(cmd, "version-bump-write-back"),
+		}
+		err = synchroniser.ArtifactsToTenant(packageId, workDir, artifactsDir, includedIds, excludedIds, opts)
 		if err != nil {
 			return err
 		}
+		if st != nil {
+			err = st.Save()
+			if err != nil {
+				return err
+			}
+		}
+
+		versionBumpedFiles := synchroniser.VersionBumpedFiles()
+		if len(versionBumpedFiles) > 0 && !skipCommit {
+			// The state file is only committed together with bumped versions, otherwise it is left in the work tree
+			commitFiles := versionBumpedFiles
+			if st != nil {
+				commitFiles = append(commitFiles, filepath.Join(gitRepoDir, state.DefaultFile))
+			}
+			err = repo.CommitFiles(gitRepoDir, commitFiles, "Bump Bundle-Version of artifacts updated in tenant", commitUser, commitEmail)
+			if err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,270 @@
+package compare
+
+import (
+	"sort"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/rs/zerolog/log"
+)
+
+// Status of a package or artifact in the target tenant compared to the source tenant
+const (
+	StatusSame            = "SAME"
+	StatusDifferent       = "DIFFERENT"
+	StatusMissingInSource = "MISSING_IN_SOURCE"
+	StatusMissingInTarget = "MISSING_IN_TARGET"
+)
+
+// ArtifactState is the designtime and runtime state of an artifact in one tenant. RuntimeVersion is NOT_DEPLOYED if
+// the artifact is not deployed.
+type ArtifactState struct {
+	Version        string
+	RuntimeVersion string
+	Draft          bool
+}
+
+// ParameterDiff is an externalized parameter of an Integration artifact with different values in both tenants
+type ParameterDiff struct {
+	Key         string
+	SourceValue string
+	TargetValue string
+}
+
+// ArtifactComparison is the comparison of an artifact in both tenants. Source or Target is nil if the artifact does not
+// exist in that tenant. Files lists the file-level differences of the content.
+type ArtifactComparison struct {
+	Id             string
+	Name           string
+	Type           string
+	Status         string
+	Source         *ArtifactState
+	Target         *ArtifactState
+	ContentDiffers bool
+	Files          []*file.FileDiff
+	Parameters     []*ParameterDiff
+}
+
+// VersionDiffers returns true if the designtime version differs between both tenants
+func (a *ArtifactComparison) VersionDiffers() bool {
+	return a.Source != nil && a.Target != nil && a.Source.Version != a.Target.Version
+}
+
+// RuntimeDiffers returns true if the deployed runtime version differs between both tenants
+func (a *ArtifactComparison) RuntimeDiffers() bool {
+	return a.Source != nil && a.Target != nil && a.Source.RuntimeVersion != a.Target.RuntimeVersion
+}
+
+type PackageComparison struct {
+	Id        string
+	Status    string
+	Artifacts []*ArtifactComparison
+}
+
+type Report struct {
+	Source   string
+	Target   string
+	Packages []*PackageComparison
+}
+
+// Count returns the number of artifacts with the status
+func (r *Report) Count(status string) int {
+	count := 0
+	for _, p := range r.Packages {
+		for _, artifact := range p.Artifacts {
+			if artifact.Status == status {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+type Comparer struct {
+	source *httpclnt.HTTPExecuter
+	target *httpclnt.HTTPExecuter
+}
+
+// New returns an initialised Comparer instance that compares the source tenant with the target tenant.
+func New(source *httpclnt.HTTPExecuter, target *httpclnt.HTTPExecuter) *Comparer {
+	c := new(Comparer)
+	c.source = source
+	c.target = target
+	return c
+}
+
+// ComparePackages compares the artifacts of the packages between both tenants. sourceName and targetName identify the
+// tenants in the report and in the headers of the file diffs.
+func (c *Comparer) ComparePackages(packageIds []string, sourceName string, targetName string) (*Report, error) {
+	report := &Report{Source: sourceName, Target: targetName}
+	for _, packageId := range packageIds {
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("📢 Comparing package %v", packageId)
+		p, err := c.comparePackage(packageId, sourceName, targetName)
+		if err != nil {
+			return nil, err
+		}
+		report.Packages = append(report.Packages, p)
+	}
+	sort.Slice(report.Packages, func(i, j int) bool { return report.Packages[i].Id < report.Packages[j].Id })
+	return report, nil
+}
+
+func (c *Comparer) comparePackage(packageId string, sourceName string, targetName string) (*PackageComparison, error) {
+	p := &PackageComparison{Id: packageId, Status: StatusSame, Artifacts: []*ArtifactComparison{}}
+	sourceArtifacts, sourceExists, err := getArtifacts(c.source, packageId)
+	if err != nil {
+		return nil, err
+	}
+	targetArtifacts, targetExists, err := getArtifacts(c.target, packageId)
+	if err != nil {
+		return nil, err
+	}
+	if !sourceExists && !targetExists {
+		log.Warn().Msgf("Package %v does not exist in both tenants", packageId)
+	}
+
+	for _, artifact := range sourceArtifacts {
+		targetArtifact := api.FindArtifactById(artifact.Id, targetArtifacts)
+		comparison, err := c.compareArtifact(artifact, targetArtifact, sourceName, targetName)
+		if err != nil {
+			return nil, err
+		}
+		p.Artifacts = append(p.Artifacts, comparison)
+	}
+	for _, artifact := range targetArtifacts {
+		if api.FindArtifactById(artifact.Id, sourceArtifacts) == nil {
+			log.Warn().Msgf("Artifact %v does not exist in %v", artifact.Id, sourceName)
+			state, err := getState(c.target, artifact)
+			if err != nil {
+				return nil, err
+			}
+			p.Artifacts = append(p.Artifacts, &ArtifactComparison{Id: artifact.Id, Name: artifact.Name, Type: artifact.ArtifactType, Status: StatusMissingInSource, Target: state})
+		}
+	}
+	sort.Slice(p.Artifacts, func(i, j int) bool { return p.Artifacts[i].Id < p.Artifacts[j].Id })
+
+	switch {
+	case !sourceExists:
+		p.Status = StatusMissingInSource
+	case !targetExists:
+		p.Status = StatusMissingInTarget
+	default:
+		for _, artifact := range p.Artifacts {
+			if artifact.Status != StatusSame {
+				p.Status = StatusDifferent
+			}
+		}
+	}
+	return p, nil
+}
+
+// compareArtifact compares the artifact of the source tenant with the artifact of the target tenant, which is nil if
+// it does not exist in the target tenant
+func (c *Comparer) compareArtifact(sourceArtifact *api.ArtifactDetails, targetArtifact *api.ArtifactDetails, sourceName string, targetName string) (*ArtifactComparison, error) {
+	log.Info().Msgf("Comparing artifact %v", sourceArtifact.Id)
+	comparison := &ArtifactComparison{Id: sourceArtifact.Id, Name: sourceArtifact.Name, Type: sourceArtifact.ArtifactType, Status: StatusSame}
+	var err error
+	comparison.Source, err = getState(c.source, sourceArtifact)
+	if err != nil {
+		return nil, err
+	}
+	if targetArtifact == nil {
+		log.Warn().Msgf("Artifact %v does not exist in %v", sourceArtifact.Id, targetName)
+		comparison.Status = StatusMissingInTarget
+		return comparison, nil
+	}
+	comparison.Target, err = getState(c.target, targetArtifact)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare content
+	sourceDt := api.NewDesigntimeArtifact(sourceArtifact.ArtifactType, c.source)
+	targetDt := api.NewDesigntimeArtifact(targetArtifact.ArtifactType, c.target)
+	sourceContent, err := sourceDt.Download(sourceArtifact.Id)
+	if err != nil {
+		return nil, err
+	}
+	targetContent, err := targetDt.Download(targetArtifact.Id)
+	if err != nil {
+		return nil, err
+	}
+	comparison.ContentDiffers, err = sourceDt.CompareContent(sourceContent, targetContent, nil, "tenant")
+	if err != nil {
+		return nil, err
+	}
+	if comparison.ContentDiffers {
+		comparison.Files, err = sourceDt.DiffFiles(sourceContent, targetContent, sourceName, targetName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Compare externalized parameters
+	if sourceArtifact.ArtifactType == "Integration" {
+		sourceParams, err := api.NewConfiguration(c.source).Get(sourceArtifact.Id, "active")
+		if err != nil {
+			return nil, err
+		}
+		targetParams, err := api.NewConfiguration(c.target).Get(targetArtifact.Id, "active")
+		if err != nil {
+			return nil, err
+		}
+		comparison.Parameters = diffParameters(sourceParams.Root.Results, targetParams.Root.Results)
+	}
+
+	if comparison.VersionDiffers() || comparison.RuntimeDiffers() || comparison.ContentDiffers || len(comparison.Parameters) > 0 {
+		comparison.Status = StatusDifferent
+		log.Info().Msgf("Artifact %v differs between %v and %v", sourceArtifact.Id, sourceName, targetName)
+	} else {
+		log.Info().Msgf("🏆 Artifact %v is the same in %v and %v", sourceArtifact.Id, sourceName, targetName)
+	}
+	return comparison, nil
+}
+
+// getArtifacts returns the artifacts of the package in the tenant, and false if the package does not exist
+func getArtifacts(exe *httpclnt.HTTPExecuter, packageId string) ([]*api.ArtifactDetails, bool, error) {
+	ip := api.NewIntegrationPackage(exe)
+	_, _, exists, err := ip.Get(packageId)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	artifacts, err := ip.GetAllArtifacts(packageId)
+	if err != nil {
+		return nil, false, err
+	}
+	return artifacts, true, nil
+}
+
+func getState(exe *httpclnt.HTTPExecuter, artifact *api.ArtifactDetails) (*ArtifactState, error) {
+	runtimeVersion, _, err := api.NewRuntime(exe).Get(artifact.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &ArtifactState{Version: artifact.Version, RuntimeVersion: runtimeVersion, Draft: artifact.IsDraft}, nil
+}
+
+// diffParameters returns the parameters that have different values in both lists, sorted by key. A parameter that
+// only exists in one list has an empty value in the other.
+func diffParameters(source []*api.ParameterData, target []*api.ParameterData) []*ParameterDiff {
+	var diffs []*ParameterDiff
+	for _, param := range source {
+		targetParam := api.FindParameterByKey(param.ParameterKey, target)
+		if targetParam == nil || targetParam.ParameterValue != param.ParameterValue {
+			diff := &ParameterDiff{Key: param.ParameterKey, SourceValue: param.ParameterValue}
+			if targetParam != nil {
+				diff.TargetValue = targetParam.ParameterValue
+			}
+			diffs = append(diffs, diff)
+		}
+	}
+	for _, param := range target {
+		if api.FindParameterByKey(param.ParameterKey, source) == nil {
+			diffs = append(diffs, &ParameterDiff{Key: param.ParameterKey, TargetValue: param.ParameterValue})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Key < diffs[j].Key })
+	return diffs
+}
@@ -0,0 +1,77 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() *Report {
+	return &Report{Source: "qa", Target: "prd", Packages: []*PackageComparison{
+		{Id: "PackageA", Status: StatusDifferent, Artifacts: []*ArtifactComparison{
+			{Id: "IFlowA", Type: "Integration", Status: StatusDifferent,
+				Source:         &ArtifactState{Version: "1.0.1", RuntimeVersion: "1.0.1"},
+				Target:         &ArtifactState{Version: "1.0.0", RuntimeVersion: "NOT_DEPLOYED"},
+				ContentDiffers: true,
+				Files:          []*file.FileDiff{{Path: "META-INF/MANIFEST.MF", Status: file.FileChanged, Diff: "--- qa/META-INF/MANIFEST.MF\n+++ prd/META-INF/MANIFEST.MF\n-Bundle-Version: 1.0.1\n+Bundle-Version: 1.0.0\n"}},
+				Parameters:     []*ParameterDiff{{Key: "Host", SourceValue: "qa.example.com", TargetValue: "prd<example>|com"}},
+			},
+			{Id: "MappingB", Type: "MessageMapping", Status: StatusMissingInTarget,
+				Source: &ArtifactState{Version: "1.0.0", RuntimeVersion: "NOT_DEPLOYED", Draft: true},
+			},
+		}},
+	}}
+}
+
+func TestDiffParameters(t *testing.T) {
+	source := []*api.ParameterData{{ParameterKey: "Host", ParameterValue: "qa"}, {ParameterKey: "Port", ParameterValue: "443"}, {ParameterKey: "User", ParameterValue: "dummy"}}
+	target := []*api.ParameterData{{ParameterKey: "Port", ParameterValue: "443"}, {ParameterKey: "Host", ParameterValue: "prd"}, {ParameterKey: "Proxy", ParameterValue: "proxy"}}
+
+	diffs := diffParameters(source, target)
+	assert.Equal(t, []*ParameterDiff{
+		{Key: "Host", SourceValue: "qa", TargetValue: "prd"},
+		{Key: "Proxy", TargetValue: "proxy"},
+		{Key: "User", SourceValue: "dummy"},
+	}, diffs, "Unexpected parameter differences")
+}
+
+func TestCount(t *testing.T) {
+	report := testReport()
+	assert.Equal(t, 1, report.Count(StatusDifferent), "Incorrect number of different artifacts")
+	assert.Equal(t, 1, report.Count(StatusMissingInTarget), "Incorrect number of artifacts missing in target")
+	assert.Equal(t, 0, report.Count(StatusSame), "Incorrect number of same artifacts")
+}
+
+func TestWrite_Markdown(t *testing.T) {
+	var buffer bytes.Buffer
+	err := testReport().Write(&buffer, "markdown")
+	assert.NoError(t, err)
+
+	output := buffer.String()
+	assert.Contains(t, output, "| Package | Artifact | Type | Status | qa version | prd version | qa runtime | prd runtime |", "Incorrect header")
+	assert.Contains(t, output, "| PackageA | IFlowA | Integration | DIFFERENT | 1.0.1 | 1.0.0 | 1.0.1 | NOT_DEPLOYED |", "Incorrect artifact line")
+	assert.Contains(t, output, "| PackageA | MappingB | MessageMapping | MISSING_IN_TARGET | 1.0.0 (draft) | - | NOT_DEPLOYED | - |", "Incorrect missing artifact line")
+	assert.Contains(t, output, "| Host | qa.example.com | prd<example>\\|com |", "Expected escaped parameter value")
+	assert.Contains(t, output, "```diff\n--- qa/META-INF/MANIFEST.MF\n", "Expected diff of file")
+	assert.Equal(t, 1, strings.Count(output, "## PackageA / "), "Expected details only for artifacts with differences")
+}
+
+func TestWrite_HTML(t *testing.T) {
+	var buffer bytes.Buffer
+	err := testReport().Write(&buffer, "html")
+	assert.NoError(t, err)
+
+	output := buffer.String()
+	assert.Contains(t, output, `<tr class="DIFFERENT"><td>PackageA</td><td>IFlowA</td>`, "Incorrect artifact row")
+	assert.Contains(t, output, "<td>prd&lt;example&gt;|com</td>", "Expected escaped parameter value")
+	assert.Contains(t, output, "<pre>--- qa/META-INF/MANIFEST.MF\n", "Expected diff of file")
+}
+
+func TestWrite_InvalidFormat(t *testing.T) {
+	err := testReport().Write(&bytes.Buffer{}, "json")
+	assert.Error(t, err)
+}
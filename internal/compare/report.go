@@ -0,0 +1,123 @@
+package compare
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/go-errors/errors"
+)
+
+var funcs = map[string]any{
+	"version": func(state *ArtifactState) string {
+		if state == nil {
+			return "-"
+		}
+		if state.Draft {
+			return state.Version + " (draft)"
+		}
+		return state.Version
+	},
+	"runtime": func(state *ArtifactState) string {
+		if state == nil {
+			return "-"
+		}
+		return state.RuntimeVersion
+	},
+	// cell escapes the characters that break a Markdown table cell
+	"cell": func(value string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+	},
+}
+
+const markdownTemplate = `# Comparison of {{.Source}} and {{.Target}}
+
+| Package | Artifact | Type | Status | {{.Source}} version | {{.Target}} version | {{.Source}} runtime | {{.Target}} runtime |
+|---|---|---|---|---|---|---|---|
+{{- range $p := .Packages}}
+| {{$p.Id}} | * | Package | {{$p.Status}} | | | | |
+{{- range $p.Artifacts}}
+| {{$p.Id}} | {{.Id}} | {{.Type}} | {{.Status}} | {{version .Source}} | {{version .Target}} | {{runtime .Source}} | {{runtime .Target}} |
+{{- end}}
+{{- end}}
+{{range $p := .Packages}}{{range .Artifacts}}{{if or .Files .Parameters}}
+## {{$p.Id}} / {{.Id}}
+{{if .Parameters}}
+| Parameter | {{$.Source}} | {{$.Target}} |
+|---|---|---|
+{{- range .Parameters}}
+| {{cell .Key}} | {{cell .SourceValue}} | {{cell .TargetValue}} |
+{{- end}}
+{{end}}{{range .Files}}
+### {{.Path}} ({{.Status}})
+
+` + "```diff" + `
+{{.Diff}}` + "```" + `
+{{end}}{{end}}{{end}}{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Comparison of {{.Source}} and {{.Target}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.SAME { background: #e6ffed; }
+.DIFFERENT { background: #fff5b1; }
+.MISSING_IN_SOURCE, .MISSING_IN_TARGET { background: #ffeef0; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>Comparison of {{.Source}} and {{.Target}}</h1>
+<table>
+<tr><th>Package</th><th>Artifact</th><th>Type</th><th>Status</th><th>{{.Source}} version</th><th>{{.Target}} version</th><th>{{.Source}} runtime</th><th>{{.Target}} runtime</th></tr>
+{{- range $p := .Packages}}
+<tr class="{{$p.Status}}"><td>{{$p.Id}}</td><td>*</td><td>Package</td><td>{{$p.Status}}</td><td></td><td></td><td></td><td></td></tr>
+{{- range $p.Artifacts}}
+<tr class="{{.Status}}"><td>{{$p.Id}}</td><td>{{.Id}}</td><td>{{.Type}}</td><td>{{.Status}}</td><td>{{version .Source}}</td><td>{{version .Target}}</td><td>{{runtime .Source}}</td><td>{{runtime .Target}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+{{- range $p := .Packages}}{{range .Artifacts}}{{if or .Files .Parameters}}
+<h2>{{$p.Id}} / {{.Id}}</h2>
+{{- if .Parameters}}
+<table>
+<tr><th>Parameter</th><th>{{$.Source}}</th><th>{{$.Target}}</th></tr>
+{{- range .Parameters}}
+<tr><td>{{.Key}}</td><td>{{.SourceValue}}</td><td>{{.TargetValue}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Files}}
+<h3>{{.Path}} ({{.Status}})</h3>
+<pre>{{.Diff}}</pre>
+{{- end}}
+{{- end}}{{end}}{{end}}
+</body>
+</html>
+`
+
+// Write outputs the report in the format (markdown or html). The summary table shows the versions of each artifact side
+// by side, followed by the differing parameters and the file-level diffs of each artifact.
+func (r *Report) Write(w io.Writer, format string) error {
+	var err error
+	switch format {
+	case "markdown":
+		tmpl := texttemplate.Must(texttemplate.New("markdown").Funcs(funcs).Parse(markdownTemplate))
+		err = tmpl.Execute(w, r)
+	case "html":
+		tmpl := htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(htmlTemplate))
+		err = tmpl.Execute(w, r)
+	default:
+		return fmt.Errorf("invalid value for output format = %v", format)
+	}
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
@@ -32,6 +32,11 @@ func GetInt(cmd *cobra.Command, flagName string) int {
 	return val
 }
 
+func GetFloat64(cmd *cobra.Command, flagName string) float64 {
+	val, _ := cmd.Flags().GetFloat64(flagName)
+	return val
+}
+
 func GetBool(cmd *cobra.Command, flagName string) bool {
 	val, _ := cmd.Flags().GetBool(flagName)
 	return val
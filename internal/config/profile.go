@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ProfilesKey is the key in the config file that contains the named profiles of tenants
+const ProfilesKey = "tenants"
+
+// InheritsKey is the key in a profile that names the profile it inherits settings from
+const InheritsKey = "inherits"
+
+// ResolveProfile returns the settings of the named profile including the settings it inherits. Settings of the
+// profile override inherited settings. Keys that are not in knownKeys are rejected. Profile names are not case-sensitive.
+func ResolveProfile(profiles map[string]any, name string, knownKeys map[string]bool) (map[string]any, error) {
+	return resolveProfile(profiles, strings.ToLower(name), knownKeys, nil)
+}
+
+func resolveProfile(profiles map[string]any, name string, knownKeys map[string]bool, chain []string) (map[string]any, error) {
+	if slices.Contains(chain, name) {
+		return nil, fmt.Errorf("Profile %v has circular inheritance: %v", chain[0], strings.Join(append(chain, name), " -> "))
+	}
+	chain = append(chain, name)
+	raw, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("Profile %v not found in %v of config file. Available profiles: %v", name, ProfilesKey, strings.Join(profileNames(profiles), ", "))
+	}
+	settings, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("Profile %v in config file must contain key-value settings", name)
+	}
+
+	resolved := map[string]any{}
+	if parent, ok := settings[InheritsKey]; ok {
+		parentName, ok := parent.(string)
+		if !ok || parentName == "" {
+			return nil, fmt.Errorf("Value of %v in profile %v must be a profile name", InheritsKey, name)
+		}
+		inherited, err := resolveProfile(profiles, strings.ToLower(parentName), knownKeys, chain)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range inherited {
+			resolved[key] = value
+		}
+	}
+	var unknownKeys []string
+	for key, value := range settings {
+		if key == InheritsKey {
+			continue
+		}
+		if !knownKeys[key] {
+			unknownKeys = append(unknownKeys, key)
+			continue
+		}
+		resolved[key] = value
+	}
+	if len(unknownKeys) > 0 {
+		sort.Strings(unknownKeys)
+		return nil, fmt.Errorf("Profile %v in config file has unknown key(s): %v", name, strings.Join(unknownKeys, ", "))
+	}
+	return resolved, nil
+}
+
+func profileNames(profiles map[string]any) []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var knownKeys = map[string]bool{"tmn-host": true, "oauth-host": true, "oauth-clientid": true, "oauth-clientsecret": true}
+
+func testProfiles() map[string]any {
+	return map[string]any{
+		"cpi": map[string]any{
+			"oauth-host": "cpi.authentication.eu10.hana.ondemand.com",
+		},
+		"dev": map[string]any{
+			"inherits":       "CPI",
+			"tmn-host":       "dev.it-cpi.cfapps.eu10.hana.ondemand.com",
+			"oauth-clientid": "dev-client",
+		},
+		"prd": map[string]any{
+			"inherits":   "dev",
+			"tmn-host":   "prd.it-cpi.cfapps.eu10.hana.ondemand.com",
+			"oauth-host": "prd.authentication.eu10.hana.ondemand.com",
+		},
+	}
+}
+
+func TestResolveProfile_Inheritance(t *testing.T) {
+	settings, err := ResolveProfile(testProfiles(), "PRD", knownKeys)
+	if err != nil {
+		t.Fatalf("ResolveProfile failed with error - %v", err)
+	}
+	assert.Equal(t, map[string]any{
+		"tmn-host":       "prd.it-cpi.cfapps.eu10.hana.ondemand.com",
+		"oauth-host":     "prd.authentication.eu10.hana.ondemand.com",
+		"oauth-clientid": "dev-client",
+	}, settings, "Expected settings of profile to override inherited settings")
+}
+
+func TestResolveProfile_Errors(t *testing.T) {
+	profiles := testProfiles()
+	_, err := ResolveProfile(profiles, "qa", knownKeys)
+	assert.ErrorContains(t, err, "Profile qa not found in tenants of config file. Available profiles: cpi, dev, prd")
+
+	profiles["cpi"].(map[string]any)["tmn-hots"] = "typo"
+	_, err = ResolveProfile(profiles, "dev", knownKeys)
+	assert.ErrorContains(t, err, "Profile cpi in config file has unknown key(s): tmn-hots")
+
+	profiles = testProfiles()
+	profiles["cpi"].(map[string]any)["inherits"] = "prd"
+	_, err = ResolveProfile(profiles, "prd", knownKeys)
+	assert.ErrorContains(t, err, "Profile prd has circular inheritance: prd -> dev -> cpi -> prd")
+
+	profiles["cpi"] = "not a map"
+	_, err = ResolveProfile(profiles, "cpi", knownKeys)
+	assert.ErrorContains(t, err, "must contain key-value settings")
+}
@@ -0,0 +1,117 @@
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-errors/errors"
+)
+
+// Status of a package or artifact in the tenant compared to Git
+const (
+	StatusUnchanged       = "UNCHANGED"
+	StatusChanged         = "CHANGED"
+	StatusMissingInGit    = "MISSING_IN_GIT"
+	StatusMissingInTenant = "MISSING_IN_TENANT"
+	StatusDraft           = "DRAFT"
+)
+
+type ArtifactResult struct {
+	Id     string `json:"id"`
+	Type   string `json:"type"`
+	Status string `json:"status"`
+}
+
+// PackageResult is the status of the package details and the artifacts of the package. ChangedFields lists the fields
+// of the package details that differ when the status is CHANGED.
+type PackageResult struct {
+	Id            string            `json:"id"`
+	Status        string            `json:"status"`
+	ChangedFields []string          `json:"changedFields,omitempty"`
+	Artifacts     []*ArtifactResult `json:"artifacts"`
+}
+
+type Report struct {
+	Drift    bool             `json:"drift"`
+	Packages []*PackageResult `json:"packages"`
+}
+
+// Add includes the package in the report. Packages and their artifacts are sorted by ID.
+func (r *Report) Add(p *PackageResult) {
+	sort.Slice(p.Artifacts, func(i, j int) bool { return p.Artifacts[i].Id < p.Artifacts[j].Id })
+	r.Packages = append(r.Packages, p)
+	sort.Slice(r.Packages, func(i, j int) bool { return r.Packages[i].Id < r.Packages[j].Id })
+	if p.Drifted() {
+		r.Drift = true
+	}
+}
+
+// Drifted returns true if the package details or any of the artifacts differ between the tenant and Git
+func (p *PackageResult) Drifted() bool {
+	if p.Status != StatusUnchanged {
+		return true
+	}
+	for _, artifact := range p.Artifacts {
+		if artifact.Status != StatusUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of packages and artifacts with the status
+func (r *Report) Count(status string) (packages int, artifacts int) {
+	for _, p := range r.Packages {
+		if p.Status == status {
+			packages++
+		}
+		for _, artifact := range p.Artifacts {
+			if artifact.Status == status {
+				artifacts++
+			}
+		}
+	}
+	return
+}
+
+// Write outputs the report in the format (table or json). In table format, the package details are shown with
+// artifact ID * and type Package.
+func (r *Report) Write(w io.Writer, format string) error {
+	var err error
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, row := range r.rows() {
+			_, err = fmt.Fprintln(tw, strings.Join(row, "\t"))
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+		}
+		err = tw.Flush()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(r)
+	default:
+		return fmt.Errorf("invalid value for output format = %v", format)
+	}
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+func (r *Report) rows() [][]string {
+	rows := [][]string{{"PACKAGE", "ARTIFACT", "TYPE", "STATUS", "DETAILS"}}
+	for _, p := range r.Packages {
+		rows = append(rows, []string{p.Id, "*", "Package", p.Status, strings.Join(p.ChangedFields, ",")})
+		for _, artifact := range p.Artifacts {
+			rows = append(rows, []string{p.Id, artifact.Id, artifact.Type, artifact.Status, ""})
+		}
+	}
+	return rows
+}
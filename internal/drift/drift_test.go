@@ -0,0 +1,78 @@
+package drift
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testReport() *Report {
+	report := &Report{}
+	report.Add(&PackageResult{Id: "PackageB", Status: StatusUnchanged, Artifacts: []*ArtifactResult{
+		{Id: "IFlowB", Type: "Integration", Status: StatusUnchanged},
+		{Id: "IFlowA", Type: "Integration", Status: StatusUnchanged},
+	}})
+	report.Add(&PackageResult{Id: "PackageA", Status: StatusChanged, ChangedFields: []string{"Description", "Version"}, Artifacts: []*ArtifactResult{
+		{Id: "IFlowC", Type: "Integration", Status: StatusChanged},
+		{Id: "MappingD", Type: "MessageMapping", Status: StatusDraft},
+		{Id: "ScriptE", Type: "ScriptCollection", Status: StatusMissingInTenant},
+	}})
+	return report
+}
+
+func TestAdd(t *testing.T) {
+	report := &Report{}
+	report.Add(&PackageResult{Id: "PackageB", Status: StatusUnchanged, Artifacts: []*ArtifactResult{{Id: "IFlowA", Status: StatusUnchanged}}})
+	assert.False(t, report.Drift, "Expected no drift for unchanged package")
+
+	report = testReport()
+	assert.True(t, report.Drift, "Expected drift for changed package")
+	assert.Equal(t, "PackageA", report.Packages[0].Id, "Expected packages sorted by ID")
+	assert.Equal(t, "IFlowA", report.Packages[1].Artifacts[0].Id, "Expected artifacts sorted by ID")
+
+	packages, artifacts := report.Count(StatusChanged)
+	assert.Equal(t, 1, packages, "Incorrect number of changed packages")
+	assert.Equal(t, 1, artifacts, "Incorrect number of changed artifacts")
+	packages, artifacts = report.Count(StatusUnchanged)
+	assert.Equal(t, 1, packages, "Incorrect number of unchanged packages")
+	assert.Equal(t, 2, artifacts, "Incorrect number of unchanged artifacts")
+}
+
+func TestDrifted(t *testing.T) {
+	p := &PackageResult{Id: "PackageA", Status: StatusUnchanged, Artifacts: []*ArtifactResult{{Id: "IFlowA", Status: StatusUnchanged}}}
+	assert.False(t, p.Drifted(), "Expected no drift")
+	p.Artifacts = append(p.Artifacts, &ArtifactResult{Id: "IFlowB", Status: StatusMissingInGit})
+	assert.True(t, p.Drifted(), "Expected drift for artifact missing in Git")
+}
+
+func TestWrite_Table(t *testing.T) {
+	var buffer bytes.Buffer
+	err := testReport().Write(&buffer, "table")
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, 8, len(lines), "Expected header and one line per package and artifact")
+	assert.Equal(t, []string{"PACKAGE", "ARTIFACT", "TYPE", "STATUS", "DETAILS"}, strings.Fields(lines[0]), "Incorrect header")
+	assert.Equal(t, []string{"PackageA", "*", "Package", "CHANGED", "Description,Version"}, strings.Fields(lines[1]), "Incorrect package line")
+	assert.Equal(t, []string{"PackageA", "MappingD", "MessageMapping", "DRAFT"}, strings.Fields(lines[3]), "Incorrect artifact line")
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buffer bytes.Buffer
+	err := testReport().Write(&buffer, "json")
+	assert.NoError(t, err)
+
+	var output Report
+	err = json.Unmarshal(buffer.Bytes(), &output)
+	if assert.NoError(t, err, "Expected valid JSON") {
+		assert.True(t, output.Drift, "Expected drift in JSON")
+		assert.Equal(t, 2, len(output.Packages), "Incorrect number of packages in JSON")
+		assert.Equal(t, StatusMissingInTenant, output.Packages[0].Artifacts[2].Status, "Incorrect artifact status in JSON")
+	}
+
+	err = testReport().Write(&buffer, "csv")
+	assert.Error(t, err, "Expected error for invalid format")
+}
@@ -1,46 +1,142 @@
 package file
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+
 	"github.com/beevik/etree"
 	"github.com/engswee/flashpipe/internal/str"
+	"github.com/go-errors/errors"
 	"github.com/rs/zerolog/log"
-	"os"
 )
 
-func UpdateBPMN(artifactDir string, scriptMap []string) error {
-	if len(scriptMap) > 0 {
-		// Extract collection into key pairs
-		output := map[string]string{}
-		log.Debug().Msgf("Updating files in %v with collection %v", artifactDir, scriptMap)
+// BPMNRule describes a value in the BPMN2 XML of an IFlow that is converted
+// when the IFlow is moved between Git and the tenant. The value is located either
+// by the key of an ifl:property, or by an etree path (optionally with an attribute).
+// Source is the value stored in Git, Target is the value used in the tenant.
+type BPMNRule struct {
+	Key       string `json:"key,omitempty"`
+	XPath     string `json:"xpath,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+type bpmnRulesFile struct {
+	Rules []*BPMNRule `json:"rules"`
+}
+
+// LoadBPMNRules reads the conversion rules from a JSON file
+func LoadBPMNRules(rulesFile string) ([]*BPMNRule, error) {
+	content, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	var rulesData *bpmnRulesFile
+	err = json.Unmarshal(content, &rulesData)
+	if err != nil {
+		log.Error().Msgf("Error unmarshalling file as JSON. File content = %s", content)
+		return nil, errors.Wrap(err, 0)
+	}
+	for i, rule := range rulesData.Rules {
+		if (rule.Key == "") == (rule.XPath == "") {
+			return nil, fmt.Errorf("Rule %d in %v must have either key or xpath", i+1, rulesFile)
+		}
+		if rule.Attribute != "" && rule.XPath == "" {
+			return nil, fmt.Errorf("Rule %d in %v must have xpath when attribute is used", i+1, rulesFile)
+		}
+		if rule.Source == "" || rule.Target == "" {
+			return nil, fmt.Errorf("Rule %d in %v must have both source and target", i+1, rulesFile)
+		}
+	}
+	return rulesData.Rules, nil
+}
+
+// ScriptMapToBPMNRules converts source-target pairs of script collection IDs into rules
+func ScriptMapToBPMNRules(scriptMap []string) []*BPMNRule {
+	var rules []*BPMNRule
+	for _, pair := range scriptMap {
+		srcTgt := str.ExtractDelimitedValues(pair, "=")
+		if len(srcTgt) == 2 && srcTgt[0] != "" && srcTgt[1] != "" {
+			rules = append(rules, &BPMNRule{Key: "scriptBundleId", Source: srcTgt[0], Target: srcTgt[1]})
+		}
+	}
+	return rules
+}
+
+// ReverseBPMNRules returns the rules for converting in the opposite direction (from tenant to Git)
+func ReverseBPMNRules(rules []*BPMNRule) []*BPMNRule {
+	var reversed []*BPMNRule
+	for _, rule := range rules {
+		reversed = append(reversed, &BPMNRule{
+			Key:       rule.Key,
+			XPath:     rule.XPath,
+			Attribute: rule.Attribute,
+			Source:    rule.Target,
+			Target:    rule.Source,
+		})
+	}
+	return reversed
+}
+
+func UpdateBPMN(artifactDir string, rules []*BPMNRule) error {
+	if len(rules) > 0 {
+		log.Debug().Msgf("Updating files in %v with %d conversion rule(s)", artifactDir, len(rules))
 
-		for _, pair := range scriptMap {
-			srcTgt := str.ExtractDelimitedValues(pair, "=")
-			output[srcTgt[0]] = srcTgt[1]
+		bpmnDir := fmt.Sprintf("%v/src/main/resources/scenarioflows/integrationflow", artifactDir)
+		entries, err := os.ReadDir(bpmnDir)
+		if err != nil {
+			return err
 		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				artifactFile := fmt.Sprintf("%v/%v", bpmnDir, entry.Name())
 
-		if len(output) != 0 {
-			bpmnDir := fmt.Sprintf("%v/src/main/resources/scenarioflows/integrationflow", artifactDir)
-			entries, err := os.ReadDir(bpmnDir)
+				err = updateXML(artifactFile, rules)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateBPMNContent converts the values in the IFlow BPMN2 XML files of the content with the rules
+func UpdateBPMNContent(content *Content, rules []*BPMNRule) error {
+	if len(rules) > 0 {
+		log.Debug().Msgf("Updating content with %d conversion rule(s)", len(rules))
+
+		bpmnDir := "src/main/resources/scenarioflows/integrationflow/"
+		for _, name := range content.Paths() {
+			if !strings.HasPrefix(name, bpmnDir) || strings.Contains(strings.TrimPrefix(name, bpmnDir), "/") {
+				continue
+			}
+			log.Info().Msgf("Processing BPMN2 file %v", name)
+			doc := etree.NewDocument()
+			err := doc.ReadFromBytes(content.files[name])
+			if err != nil {
+				return err
+			}
+			contentUpdated, err := applyRules(doc, rules)
 			if err != nil {
 				return err
 			}
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					artifactFile := fmt.Sprintf("%v/%v", bpmnDir, entry.Name())
-
-					err = updateXML(artifactFile, output)
-					if err != nil {
-						return err
-					}
+			if contentUpdated {
+				data, err := doc.WriteToBytes()
+				if err != nil {
+					return err
 				}
+				content.WriteFile(name, data)
 			}
 		}
 	}
 	return nil
 }
 
-func updateXML(filePath string, scripts map[string]string) error {
+func updateXML(filePath string, rules []*BPMNRule) error {
 	log.Info().Msgf("Processing BPMN2 file %v", filePath)
 	// Read XML file into tree
 	doc := etree.NewDocument()
@@ -49,17 +145,9 @@ func updateXML(filePath string, scripts map[string]string) error {
 		return err
 	}
 
-	contentUpdated := false
-	// Look for occurrence of scriptBundleId
-	for _, bundles := range doc.FindElements("//ifl:property[key='scriptBundleId']") {
-		v := bundles.SelectElement("value")
-		sourceValue := v.Text()
-		targetValue := scripts[sourceValue]
-		if sourceValue != "" && targetValue != "" {
-			log.Debug().Msgf("Changing scriptBundleId from %v to %v", sourceValue, targetValue)
-			v.SetText(targetValue)
-			contentUpdated = true
-		}
+	contentUpdated, err := applyRules(doc, rules)
+	if err != nil {
+		return err
 	}
 	// Update the BPMN XML file with the changes
 	if contentUpdated {
@@ -70,3 +158,72 @@ func updateXML(filePath string, scripts map[string]string) error {
 	}
 	return nil
 }
+
+func applyRules(doc *etree.Document, rules []*BPMNRule) (bool, error) {
+	contentUpdated := false
+	// Keep track of values already converted so that a value is not converted twice by rules
+	// that swap values, e.g. A=B and B=A
+	converted := map[*etree.Element]map[string]bool{}
+	isConverted := func(e *etree.Element, attr string) bool {
+		return converted[e] != nil && converted[e][attr]
+	}
+	markConverted := func(e *etree.Element, attr string) {
+		if converted[e] == nil {
+			converted[e] = map[string]bool{}
+		}
+		converted[e][attr] = true
+		contentUpdated = true
+	}
+
+	for _, rule := range rules {
+		if rule.Key != "" {
+			// Look for occurrence of ifl:property with matching key
+			path := fmt.Sprintf("//ifl:property[key='%v']", rule.Key)
+			for _, property := range doc.FindElements(path) {
+				v := property.SelectElement("value")
+				if v != nil && !isConverted(v, "") && v.Text() == rule.Source {
+					log.Debug().Msgf("Changing %v from %v to %v", rule.Key, rule.Source, rule.Target)
+					v.SetText(rule.Target)
+					markConverted(v, "")
+				}
+			}
+			continue
+		}
+		path, err := etree.CompilePath(rule.XPath)
+		if err != nil {
+			return false, fmt.Errorf("Invalid xpath %v: %w", rule.XPath, err)
+		}
+		for _, element := range doc.FindElementsPath(path) {
+			if rule.Attribute != "" {
+				attr := element.SelectAttr(rule.Attribute)
+				if attr != nil && !isConverted(element, rule.Attribute) && attr.Value == rule.Source {
+					log.Debug().Msgf("Changing attribute %v of %v from %v to %v", rule.Attribute, rule.XPath, rule.Source, rule.Target)
+					attr.Value = rule.Target
+					markConverted(element, rule.Attribute)
+				}
+			} else if !isConverted(element, "") && element.Text() == rule.Source {
+				log.Debug().Msgf("Changing %v from %v to %v", rule.XPath, rule.Source, rule.Target)
+				element.SetText(rule.Target)
+				markConverted(element, "")
+			}
+		}
+	}
+	return contentUpdated, nil
+}
+
+// GetIFlowProperties returns the ifl:property key-value pairs in the extension elements of a BPMN2 element
+func GetIFlowProperties(element *etree.Element) map[string]string {
+	properties := map[string]string{}
+	extensionElements := element.SelectElement("bpmn2:extensionElements")
+	if extensionElements == nil {
+		return properties
+	}
+	for _, property := range extensionElements.SelectElements("ifl:property") {
+		key := property.SelectElement("key")
+		value := property.SelectElement("value")
+		if key != nil && value != nil {
+			properties[key.Text()] = value.Text()
+		}
+	}
+	return properties
+}
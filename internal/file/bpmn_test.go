@@ -0,0 +1,62 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadBPMNRules(t *testing.T) {
+	rules, err := LoadBPMNRules("../../test/testdata/BPMNRules/rules.json")
+	if err != nil {
+		t.Fatalf("LoadBPMNRules failed with error - %v", err)
+	}
+
+	assert.Equal(t, 3, len(rules), "Expected number of rules = 3")
+	assert.Equal(t, "name", rules[1].Attribute, "Expected attribute of second rule = name")
+}
+
+func TestScriptMapToBPMNRules(t *testing.T) {
+	rules := ScriptMapToBPMNRules([]string{"ScriptA=ScriptB", "Invalid"})
+
+	assert.Equal(t, 1, len(rules), "Expected number of rules = 1")
+	assert.Equal(t, "scriptBundleId", rules[0].Key, "Expected key = scriptBundleId")
+	assert.Equal(t, "ScriptB", rules[0].Target, "Expected target = ScriptB")
+}
+
+func TestUpdateBPMN_ForwardAndReverse(t *testing.T) {
+	rules, err := LoadBPMNRules("../../test/testdata/BPMNRules/rules.json")
+	if err != nil {
+		t.Fatalf("LoadBPMNRules failed with error - %v", err)
+	}
+	artifactDir := t.TempDir() + "/Integration_Test_IFlow"
+	err = copyDir("../../test/testdata/artifacts/update/Integration_Test_IFlow", artifactDir)
+	if err != nil {
+		t.Fatalf("copyDir failed with error - %v", err)
+	}
+	bpmnFile := artifactDir + "/src/main/resources/scenarioflows/integrationflow/Integration Test IFlow.iflw"
+
+	err = UpdateBPMN(artifactDir, rules)
+	if err != nil {
+		t.Fatalf("UpdateBPMN failed with error - %v", err)
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromFile(bpmnFile); err != nil {
+		t.Fatalf("Reading BPMN file failed with error - %v", err)
+	}
+	assert.Equal(t, "ERP", doc.FindElement("//bpmn2:participant[@id='Participant_1']").SelectAttrValue("name", ""), "Sender participant not renamed")
+	assert.Equal(t, "Receiver", doc.FindElement("//bpmn2:participant[@id='Participant_2']").SelectAttrValue("name", ""), "Receiver participant should not be renamed")
+	assert.Equal(t, "ERP", doc.FindElement("//ifl:property[key='system']/value").Text(), "system property not converted")
+
+	err = UpdateBPMN(artifactDir, ReverseBPMNRules(rules))
+	if err != nil {
+		t.Fatalf("UpdateBPMN failed with error - %v", err)
+	}
+	doc = etree.NewDocument()
+	if err = doc.ReadFromFile(bpmnFile); err != nil {
+		t.Fatalf("Reading BPMN file failed with error - %v", err)
+	}
+	assert.Equal(t, "Sender", doc.FindElement("//bpmn2:participant[@id='Participant_1']").SelectAttrValue("name", ""), "Sender participant not reverted")
+	assert.Equal(t, "Sender", doc.FindElement("//ifl:property[key='system']/value").Text(), "system property not reverted")
+}
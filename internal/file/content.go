@@ -0,0 +1,379 @@
+package file
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// zipModified is the fixed modification time of entries in zip files created from Content, so that the same content
+// always results in the same zip file. It is the earliest time that can be stored in a zip file.
+var zipModified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Content is the content of an artifact held in memory, with files keyed by their slash-separated path relative to the
+// root of the artifact (e.g. META-INF/MANIFEST.MF). Directories are implied by the paths of the files.
+// Content implements fs.FS, so it can be used with fs.WalkDir, fs.ReadFile, etc.
+type Content struct {
+	files map[string][]byte
+}
+
+// NewContent returns an empty Content instance.
+func NewContent() *Content {
+	return &Content{files: map[string][]byte{}}
+}
+
+// ReadContent returns the content of all regular files in fsys. Symbolic links are skipped.
+func ReadContent(fsys fs.FS) (*Content, error) {
+	c := NewContent()
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		c.files[name] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadDirContent returns the content of all regular files in the directory.
+func ReadDirContent(dir string) (*Content, error) {
+	return ReadContent(os.DirFS(dir))
+}
+
+// ReadZipContent returns the content of the files in the zip archive.
+func ReadZipContent(data []byte) (*Content, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	c := NewContent()
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		// Check if file paths are not vulnerable to Zip Slip
+		name := strings.TrimPrefix(f.Name, "./")
+		if !fs.ValidPath(name) {
+			return nil, errors.Wrap(fmt.Errorf("invalid file path: %s", f.Name), 0)
+		}
+		zippedFile, err := f.Open()
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		fileContent, err := io.ReadAll(zippedFile)
+		zippedFile.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		c.files[name] = fileContent
+	}
+	return c, nil
+}
+
+// Paths returns the paths of all files in sorted order.
+func (c *Content) Paths() []string {
+	paths := make([]string, 0, len(c.files))
+	for name := range c.files {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Exists returns true if the content has a file or a directory with the path.
+func (c *Content) Exists(name string) bool {
+	if _, ok := c.files[name]; ok {
+		return true
+	}
+	return c.isDir(name)
+}
+
+// ReadFile returns a copy of the content of the file with the path. It implements fs.ReadFileFS.
+func (c *Content) ReadFile(name string) ([]byte, error) {
+	data, ok := c.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(data), nil
+}
+
+// WriteFile adds or replaces the file with the path.
+func (c *Content) WriteFile(name string, data []byte) {
+	c.files[name] = data
+}
+
+// Remove removes the file with the path, or all files in the directory with the path.
+func (c *Content) Remove(name string) {
+	delete(c.files, name)
+	for _, p := range c.Paths() {
+		if strings.HasPrefix(p, name+"/") {
+			delete(c.files, p)
+		}
+	}
+}
+
+// Replace replaces the file or directory with the path by the one in src. If it does not exist in src, it is removed.
+func (c *Content) Replace(src *Content, name string) {
+	c.Remove(name)
+	for _, p := range src.Paths() {
+		if p == name || strings.HasPrefix(p, name+"/") {
+			c.files[p] = src.files[p]
+		}
+	}
+}
+
+// Zip writes the content as zip archive. The output is deterministic: entries are sorted by path, and all entries have
+// the same modification time and permissions.
+func (c *Content) Zip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	dirs := map[string]bool{}
+	for _, name := range c.Paths() {
+		// Add entries for parent directories before the first file in them
+		segments := strings.Split(name, "/")
+		for i := 1; i < len(segments); i++ {
+			dir := strings.Join(segments[:i], "/") + "/"
+			if dirs[dir] {
+				continue
+			}
+			dirs[dir] = true
+			header := &zip.FileHeader{Name: dir, Method: zip.Store, Modified: zipModified}
+			header.SetMode(fs.ModeDir | 0755)
+			_, err := archive.CreateHeader(header)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+		}
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: zipModified}
+		header.SetMode(0644)
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		_, err = writer.Write(c.files[name])
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	err := archive.Close()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// ZipBase64 returns the content as base64-encoded zip archive.
+func (c *Content) ZipBase64() (string, error) {
+	var buffer bytes.Buffer
+	err := c.Zip(&buffer)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+// WriteDir writes the content to the directory. Files that are unchanged are not rewritten, and regular files in the
+// directory that are not in the content are removed.
+func (c *Content) WriteDir(dir string) error {
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	existing := map[string]bool{}
+	err = filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		existing[filepath.ToSlash(name)] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for name := range existing {
+		if _, ok := c.files[name]; ok {
+			continue
+		}
+		err := os.Remove(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	for _, name := range c.Paths() {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if existing[name] {
+			current, err := os.ReadFile(filePath)
+			if err == nil && bytes.Equal(current, c.files[name]) {
+				continue
+			}
+		}
+		err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		err = os.WriteFile(filePath, c.files[name], 0644)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return removeEmptyDirs(dir)
+}
+
+// removeEmptyDirs removes the empty subdirectories of the directory, e.g. after files are removed
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		subDir := filepath.Join(dir, entry.Name())
+		err = removeEmptyDirs(subDir)
+		if err != nil {
+			return err
+		}
+		remaining, err := os.ReadDir(subDir)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if len(remaining) == 0 {
+			err = os.Remove(subDir)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Content) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	for p := range c.files {
+		if strings.HasPrefix(p, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Open implements fs.FS.
+func (c *Content) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if data, ok := c.files[name]; ok {
+		return &contentFile{Reader: bytes.NewReader(data), info: contentInfo{name: path.Base(name), size: int64(len(data))}}, nil
+	}
+	if !c.isDir(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	// Collect the direct children of the directory
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	children := map[string]fs.DirEntry{}
+	for p, data := range c.files {
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		child, _, isDir := strings.Cut(strings.TrimPrefix(p, prefix), "/")
+		if isDir {
+			children[child] = fs.FileInfoToDirEntry(contentInfo{name: child, dir: true})
+		} else {
+			children[child] = fs.FileInfoToDirEntry(contentInfo{name: child, size: int64(len(data))})
+		}
+	}
+	var entries []fs.DirEntry
+	for _, entry := range children {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return &contentDir{info: contentInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+type contentInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i contentInfo) Name() string       { return i.name }
+func (i contentInfo) Size() int64        { return i.size }
+func (i contentInfo) ModTime() time.Time { return zipModified }
+func (i contentInfo) IsDir() bool        { return i.dir }
+func (i contentInfo) Sys() any           { return nil }
+func (i contentInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+type contentFile struct {
+	*bytes.Reader
+	info contentInfo
+}
+
+func (f *contentFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *contentFile) Close() error               { return nil }
+
+type contentDir struct {
+	info    contentInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *contentDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *contentDir) Close() error               { return nil }
+func (d *contentDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (d *contentDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	d.offset += count
+	return remaining[:count], nil
+}
@@ -0,0 +1,122 @@
+package file
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readContent(t *testing.T, dir string) *Content {
+	content, err := ReadDirContent(dir)
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	return content
+}
+
+func TestContent_FS(t *testing.T) {
+	content := readContent(t, "../../test/testdata/artifacts/collection/IFlow1")
+
+	err := fstest.TestFS(content, "META-INF/MANIFEST.MF", "QA/MANIFEST.MF", "metainfo.prop", "src/main/resources/parameters.prop")
+	assert.NoError(t, err)
+}
+
+func TestContent_ZipDeterministic(t *testing.T) {
+	content := readContent(t, "../../test/testdata/artifacts/collection/IFlow1")
+	var first, second bytes.Buffer
+	err := content.Zip(&first)
+	if err != nil {
+		t.Fatalf("Zip failed with error - %v", err)
+	}
+
+	// Add the files in a different order
+	copied := NewContent()
+	paths := content.Paths()
+	for i := len(paths) - 1; i >= 0; i-- {
+		data, _ := content.ReadFile(paths[i])
+		copied.WriteFile(paths[i], data)
+	}
+	err = copied.Zip(&second)
+	if err != nil {
+		t.Fatalf("Zip failed with error - %v", err)
+	}
+	assert.Equal(t, first.Bytes(), second.Bytes(), "Expected identical zip archives for the same content")
+
+	unzipped, err := ReadZipContent(first.Bytes())
+	if err != nil {
+		t.Fatalf("ReadZipContent failed with error - %v", err)
+	}
+	assert.Equal(t, content, unzipped, "Expected same content after zip and unzip")
+}
+
+func TestContent_WriteDir(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "src/main/resources/script"), os.ModePerm)
+	if err != nil {
+		t.Fatalf("MkdirAll failed with error - %v", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "src/main/resources/script/stale.groovy"), []byte("stale"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+
+	content := NewContent()
+	content.WriteFile("META-INF/MANIFEST.MF", []byte("Bundle-Version: 1.0.0\n"))
+	err = content.WriteDir(dir)
+	if err != nil {
+		t.Fatalf("WriteDir failed with error - %v", err)
+	}
+
+	assert.Equal(t, content, readContent(t, dir), "Expected directory to match content")
+	assert.False(t, Exists(filepath.Join(dir, "src")), "Expected empty directories to be removed")
+}
+
+func TestContent_Replace(t *testing.T) {
+	src := NewContent()
+	src.WriteFile("META-INF/MANIFEST.MF", []byte("new"))
+	tgt := NewContent()
+	tgt.WriteFile("META-INF/MANIFEST.MF", []byte("old"))
+	tgt.WriteFile("META-INF/old.txt", []byte("old"))
+	tgt.WriteFile("src/main/resources/script/a.groovy", []byte("old"))
+	tgt.WriteFile("QA/MANIFEST.MF", []byte("qa"))
+
+	tgt.Replace(src, "META-INF")
+	tgt.Replace(src, "src/main/resources")
+
+	assert.Equal(t, []string{"META-INF/MANIFEST.MF", "QA/MANIFEST.MF"}, tgt.Paths())
+	data, _ := tgt.ReadFile("META-INF/MANIFEST.MF")
+	assert.Equal(t, "new", string(data))
+}
+
+func TestReadZipContent_ZipSlip(t *testing.T) {
+	content := NewContent()
+	content.files["../evil.txt"] = []byte("evil")
+	var buffer bytes.Buffer
+	err := content.Zip(&buffer)
+	if err != nil {
+		t.Fatalf("Zip failed with error - %v", err)
+	}
+
+	_, err = ReadZipContent(buffer.Bytes())
+	assert.ErrorContains(t, err, "invalid file path")
+}
+
+func TestContentHash(t *testing.T) {
+	content := readContent(t, "../../test/testdata/artifacts/collection/IFlow1")
+	hash := ContentHash(content, "META-INF", "src/main/resources", "metainfo.prop")
+	assert.Len(t, hash, 64, "Expected SHA-256 hash in hex")
+
+	// Formatting, Origin headers and parameters.prop do not change the hash
+	manifest, _ := content.ReadFile("META-INF/MANIFEST.MF")
+	content.WriteFile("META-INF/MANIFEST.MF", append(bytes.ReplaceAll(manifest, []byte("\n"), []byte("\r\n\r\n")), []byte("Origin-Bundle-Version: 9.9.9\n")...))
+	content.WriteFile("src/main/resources/parameters.prop", []byte("Changed=true"))
+	content.WriteFile("QA/MANIFEST.MF", []byte("Not included"))
+	assert.Equal(t, hash, ContentHash(content, "META-INF", "src/main/resources", "metainfo.prop"), "Expected same hash after normalization")
+
+	content.WriteFile("src/main/resources/script/new.groovy", []byte("def x = 1"))
+	assert.NotEqual(t, hash, ContentHash(content, "META-INF", "src/main/resources", "metainfo.prop"), "Expected different hash for new file")
+}
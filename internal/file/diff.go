@@ -1,10 +1,31 @@
 package file
 
 import (
-	"github.com/rs/zerolog/log"
+	"fmt"
 	"os/exec"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/rs/zerolog/log"
+)
+
+// Status of a file in FileDiff
+const (
+	FileAdded   = "ADDED"
+	FileRemoved = "REMOVED"
+	FileChanged = "CHANGED"
 )
 
+// FileDiff is a file that differs between two contents. Diff is the unified diff of the file.
+type FileDiff struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+	Diff   string `json:"diff"`
+}
+
 func DiffDirectories(firstDir string, secondDir string) bool {
 	log.Info().Msgf("Executing command: diff --ignore-matching-lines=^Origin.* --strip-trailing-cr --recursive --ignore-all-space --ignore-blank-lines --exclude=parameters.prop --exclude=.DS_Store %v %v", firstDir, secondDir)
 	cmd := exec.Command("diff", "--ignore-matching-lines=^Origin.*", "--strip-trailing-cr", "--recursive", "--ignore-all-space", "--ignore-blank-lines", "--exclude=parameters.prop", "--exclude=.DS_Store", firstDir, secondDir)
@@ -32,3 +53,135 @@ func DiffFile(firstFile string, secondFile string) bool {
 
 	return err != nil
 }
+
+// DiffContent compares the files in the directory (or "." for all files) of both contents with the same rules as
+// DiffDirectories:
+// - ignoring lines beginning with Origin
+// - ignoring blank lines, white space and carriage returns
+// - excluding parameters.prop and .DS_Store files
+func DiffContent(first *Content, second *Content, dir string) bool {
+	log.Info().Msgf("Comparing content of directory %v", dir)
+	excluded := func(name string) bool {
+		base := path.Base(name)
+		return base == "parameters.prop" || base == ".DS_Store"
+	}
+	var results []string
+	firstPaths := contentPaths(first, dir)
+	secondPaths := contentPaths(second, dir)
+	for name := range firstPaths {
+		if excluded(name) {
+			continue
+		}
+		if !secondPaths[name] {
+			results = append(results, fmt.Sprintf("Only in first: %v", name))
+		} else if !equalLines(first.files[name], second.files[name], "Origin") {
+			results = append(results, fmt.Sprintf("Files differ: %v", name))
+		}
+	}
+	for name := range secondPaths {
+		if !excluded(name) && !firstPaths[name] {
+			results = append(results, fmt.Sprintf("Only in second: %v", name))
+		}
+	}
+	if len(results) > 0 {
+		sort.Strings(results)
+		log.Info().Msgf("Diff results:\n%v", strings.Join(results, "\n"))
+	}
+	return len(results) > 0
+}
+
+// DiffContentFile compares the file in both contents with the same rules as DiffFile:
+// - ignoring commented lines (beginning with #)
+// - ignoring blank lines, white space and carriage returns
+func DiffContentFile(first *Content, second *Content, name string) bool {
+	log.Info().Msgf("Comparing content of file %v", name)
+	firstData, firstErr := first.ReadFile(name)
+	secondData, secondErr := second.ReadFile(name)
+	if firstErr != nil || secondErr != nil {
+		log.Info().Msgf("Diff results:\nFile %v does not exist in both contents", name)
+		return true
+	}
+	if !equalLines(firstData, secondData, "#") {
+		log.Info().Msgf("Diff results:\nFiles differ: %v", name)
+		return true
+	}
+	return false
+}
+
+// DiffContentFiles returns the files in the paths (files or directories) that differ between both contents, normalized
+// in the same way as ContentHash. Files only in second are ADDED and files only in first are REMOVED. The names of
+// both contents are used in the headers of the unified diffs.
+func DiffContentFiles(first *Content, second *Content, firstName string, secondName string, paths ...string) ([]*FileDiff, error) {
+	var names []string
+	for _, name := range first.Paths() {
+		if compared(name, paths) {
+			names = append(names, name)
+		}
+	}
+	for _, name := range second.Paths() {
+		if _, ok := first.files[name]; !ok && compared(name, paths) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []*FileDiff
+	for _, name := range names {
+		firstData, inFirst := first.files[name]
+		secondData, inSecond := second.files[name]
+		var status string
+		switch {
+		case !inFirst:
+			status = FileAdded
+		case !inSecond:
+			status = FileRemoved
+		case !equalLines(firstData, secondData, ignoredPrefix(name)):
+			status = FileChanged
+		default:
+			continue
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(strings.ReplaceAll(string(firstData), "\r\n", "\n")),
+			B:        difflib.SplitLines(strings.ReplaceAll(string(secondData), "\r\n", "\n")),
+			FromFile: fmt.Sprintf("%v/%v", firstName, name),
+			ToFile:   fmt.Sprintf("%v/%v", secondName, name),
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, &FileDiff{Path: name, Status: status, Diff: diff})
+	}
+	return diffs, nil
+}
+
+func contentPaths(content *Content, dir string) map[string]bool {
+	paths := map[string]bool{}
+	for _, name := range content.Paths() {
+		if dir == "." || strings.HasPrefix(name, dir+"/") {
+			paths[name] = true
+		}
+	}
+	return paths
+}
+
+// equalLines returns true if both contents have the same lines after removing white space, blank lines and lines with
+// the ignored prefix
+func equalLines(first []byte, second []byte, ignoredPrefix string) bool {
+	return slices.Equal(significantLines(first, ignoredPrefix), significantLines(second, ignoredPrefix))
+}
+
+func significantLines(data []byte, ignoredPrefix string) []string {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, ignoredPrefix) {
+			continue
+		}
+		line = strings.Join(strings.Fields(line), "")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
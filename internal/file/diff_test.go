@@ -22,3 +22,45 @@ func TestDiffFile_Different(t *testing.T) {
 
 	assert.True(t, fileDiffer, "File contents do not differ")
 }
+
+func TestDiffContent_SameIgnoringOrigin(t *testing.T) {
+	contentDiffer := DiffContent(readContent(t, "../../test/testdata/DiffComparison/Dir1"), readContent(t, "../../test/testdata/DiffComparison/Dir2"), ".")
+
+	assert.False(t, contentDiffer, "Directory contents differ")
+}
+
+func TestDiffContent_Different(t *testing.T) {
+	contentDiffer := DiffContent(readContent(t, "../../test/testdata/DiffComparison/Dir1"), readContent(t, "../../test/testdata/DiffComparison/Dir3"), ".")
+
+	assert.True(t, contentDiffer, "Directory contents do not differ")
+}
+
+func TestDiffContentFile_Different(t *testing.T) {
+	fileDiffer := DiffContentFile(readContent(t, "../../test/testdata/DiffComparison/Dir1"), readContent(t, "../../test/testdata/DiffComparison/Dir3"), "MANIFEST.MF")
+
+	assert.True(t, fileDiffer, "File contents do not differ")
+}
+
+func TestDiffContentFiles(t *testing.T) {
+	first := readContent(t, "../../test/testdata/DiffComparison/Dir1")
+	second := readContent(t, "../../test/testdata/DiffComparison/Dir3")
+	first.WriteFile("parameters.prop", []byte("Excluded=true"))
+	second.WriteFile("script.groovy", []byte("def x = 1\n"))
+
+	diffs, err := DiffContentFiles(first, second, "qa", "prd", "MANIFEST.MF", "parameters.prop", "script.groovy")
+	if err != nil {
+		t.Fatalf("DiffContentFiles failed with error - %v", err)
+	}
+	if assert.Len(t, diffs, 2, "Unexpected number of file differences") {
+		assert.Equal(t, "MANIFEST.MF", diffs[0].Path, "Unexpected path of changed file")
+		assert.Equal(t, FileChanged, diffs[0].Status, "Unexpected status of changed file")
+		assert.Contains(t, diffs[0].Diff, "--- qa/MANIFEST.MF", "Expected name of first content in diff header")
+		assert.Contains(t, diffs[0].Diff, "-Bundle-Version: 1.0.0\n+Bundle-Version: 1.0.1\n", "Expected changed line in diff")
+		assert.Equal(t, "script.groovy", diffs[1].Path, "Unexpected path of added file")
+		assert.Equal(t, FileAdded, diffs[1].Status, "Unexpected status of added file")
+	}
+
+	diffs, err = DiffContentFiles(first, first, "qa", "prd", "MANIFEST.MF")
+	assert.NoError(t, err)
+	assert.Empty(t, diffs, "Expected no differences for same content")
+}
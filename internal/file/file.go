@@ -2,7 +2,6 @@ package file
 
 import (
 	"archive/zip"
-	"encoding/base64"
 	"fmt"
 	"io"
 	"io/fs"
@@ -262,19 +261,11 @@ func ZipDir(src string, dst string, includeSrc bool) error {
 	return err
 }
 
+// ZipDirToBase64 compresses a directory into a deterministic zip archive and returns it base64-encoded
 func ZipDirToBase64(src string) (string, error) {
-	zipFile := src + ".zip"
-	err := ZipDir(src, zipFile, false)
+	content, err := ReadDirContent(src)
 	if err != nil {
 		return "", err
 	}
-	fileContent, err := os.ReadFile(zipFile)
-	if err != nil {
-		return "", errors.Wrap(err, 0)
-	}
-	err = os.Remove(zipFile)
-	if err != nil {
-		return "", errors.Wrap(err, 0)
-	}
-	return base64.StdEncoding.EncodeToString(fileContent), nil
+	return content.ZipBase64()
 }
@@ -0,0 +1,52 @@
+package file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+)
+
+// ContentHash returns the canonical SHA-256 hash of the files in the paths (files or directories) of the content.
+// The files are normalized with the same rules as the diff comparison, so contents that do not differ have the same hash:
+//   - ignoring lines beginning with Origin in files of directories, and commented lines (beginning with #) in files
+//     at the root of the content
+//   - ignoring blank lines, white space and carriage returns
+//   - excluding parameters.prop and .DS_Store files
+func ContentHash(content *Content, paths ...string) string {
+	hash := sha256.New()
+	for _, name := range content.Paths() {
+		if !compared(name, paths) {
+			continue
+		}
+		hash.Write([]byte(name + "\x00"))
+		for _, line := range significantLines(content.files[name], ignoredPrefix(name)) {
+			hash.Write([]byte(line + "\n"))
+		}
+		hash.Write([]byte("\x00"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// compared returns true if the file is in the paths and is not excluded from comparison
+func compared(name string, paths []string) bool {
+	base := path.Base(name)
+	return base != "parameters.prop" && base != ".DS_Store" && inPaths(name, paths)
+}
+
+// ignoredPrefix returns the prefix of lines that are ignored in the comparison of the file
+func ignoredPrefix(name string) string {
+	if !strings.Contains(name, "/") {
+		return "#"
+	}
+	return "Origin"
+}
+
+func inPaths(name string, paths []string) bool {
+	for _, p := range paths {
+		if name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// contentManifestPath is the path of MANIFEST.MF in the content of an artifact
+const contentManifestPath = "META-INF/MANIFEST.MF"
+
+func GetManifestHeaders(manifestPath string) (textproto.MIMEHeader, error) {
+	manifestFile, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	defer manifestFile.Close()
+	return readManifestHeaders(manifestFile)
+}
+
+// GetContentManifestHeaders returns the headers of META-INF/MANIFEST.MF in the content
+func GetContentManifestHeaders(content *Content) (textproto.MIMEHeader, error) {
+	manifest, err := content.ReadFile(contentManifestPath)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return readManifestHeaders(bytes.NewReader(manifest))
+}
+
+func readManifestHeaders(r io.Reader) (textproto.MIMEHeader, error) {
+	tp := textproto.NewReader(bufio.NewReader(r))
+	headers, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return headers, nil
+}
+
+// GetArtifactId returns the artifact ID from the Bundle-SymbolicName header of MANIFEST.MF
+func GetArtifactId(headers textproto.MIMEHeader) string {
+	artifactId := headers.Get("Bundle-SymbolicName")
+	// remove spaces then remove ;singleton:=true
+	artifactId = strings.ReplaceAll(artifactId, " ", "")
+	artifactId = strings.ReplaceAll(artifactId, ";singleton:=true", "")
+	return artifactId
+}
+
+// UpdateManifestHeader replaces the value of a single-line header in MANIFEST.MF, keeping the rest of the file unchanged
+func UpdateManifestHeader(manifestPath string, key string, value string) error {
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	updated, found := replaceManifestHeader(content, key, value)
+	if !found {
+		return fmt.Errorf("Header %v not found in %v", key, manifestPath)
+	}
+	err = os.WriteFile(manifestPath, updated, 0644)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// UpdateContentManifestHeader replaces the value of a single-line header in META-INF/MANIFEST.MF of the content
+func UpdateContentManifestHeader(content *Content, key string, value string) error {
+	manifest, err := content.ReadFile(contentManifestPath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	updated, found := replaceManifestHeader(manifest, key, value)
+	if !found {
+		return fmt.Errorf("Header %v not found in %v", key, contentManifestPath)
+	}
+	content.WriteFile(contentManifestPath, updated)
+	return nil
+}
+
+func replaceManifestHeader(content []byte, key string, value string) ([]byte, bool) {
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, key+":") {
+			lineEnding := ""
+			if strings.HasSuffix(line, "\r") {
+				lineEnding = "\r"
+			}
+			lines[i] = fmt.Sprintf("%v: %v%v", key, value, lineEnding)
+			return []byte(strings.Join(lines, "\n")), true
+		}
+	}
+	return content, false
+}
@@ -0,0 +1,29 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateManifestHeader(t *testing.T) {
+	manifestPath := t.TempDir() + "/MANIFEST.MF"
+	err := CopyFile("../../test/testdata/artifacts/update/Integration_Test_IFlow/META-INF/MANIFEST.MF", manifestPath)
+	if err != nil {
+		t.Fatalf("CopyFile failed with error - %v", err)
+	}
+
+	err = UpdateManifestHeader(manifestPath, "Bundle-Version", "1.0.7")
+	if err != nil {
+		t.Fatalf("UpdateManifestHeader failed with error - %v", err)
+	}
+	headers, err := GetManifestHeaders(manifestPath)
+	if err != nil {
+		t.Fatalf("GetManifestHeaders failed with error - %v", err)
+	}
+	assert.Equal(t, "1.0.7", headers.Get("Bundle-Version"), "Expected Bundle-Version = 1.0.7")
+	assert.Equal(t, "Integration_Test_IFlow", GetArtifactId(headers), "Expected other headers unchanged")
+
+	err = UpdateManifestHeader(manifestPath, "Bundle-Unknown", "1")
+	assert.Error(t, err, "Expected error for missing header")
+}
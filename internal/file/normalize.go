@@ -0,0 +1,209 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultAPIMIgnoredFields are the volatile fields that are set by the API portal on every change
+var DefaultAPIMIgnoredFields = []string{"created_at", "created_by", "changed_at", "changed_by"}
+
+// apimSortedElements are the elements whose order is not significant but differs between downloads
+var apimSortedElements = map[string]bool{"apiResourceName": true}
+
+var apimTextFileExtensions = map[string]bool{
+	".xml": true, ".json": true, ".js": true, ".py": true, ".html": true, ".txt": true,
+	".wsdl": true, ".xsd": true, ".xsl": true, ".yaml": true, ".yml": true,
+}
+
+// NormalizeAPIMContent rewrites the files of API Management content in the directory into a canonical form so that
+// only relevant changes are detected when comparing content. Line endings are normalized, XML attributes and JSON keys
+// are sorted, and the ignored fields (XML elements/attributes, JSON keys) are removed. If ignoredFields is nil,
+// DefaultAPIMIgnoredFields is used.
+func NormalizeAPIMContent(dir string, ignoredFields []string) error {
+	if ignoredFields == nil {
+		ignoredFields = DefaultAPIMIgnoredFields
+	}
+	ignored := map[string]bool{}
+	for _, field := range ignoredFields {
+		ignored[field] = true
+	}
+	log.Debug().Msgf("Normalizing API Management content in %v", dir)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if d.IsDir() || !apimTextFileExtensions[ext] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+		var structured []byte
+		switch ext {
+		case ".xml":
+			structured, err = normalizeXML(normalized, ignored)
+		case ".json":
+			structured, err = normalizeJSON(normalized, ignored, "    ")
+		}
+		if err != nil {
+			// Content that cannot be parsed is compared as is
+			log.Warn().Msgf("⚠️ File %v could not be normalized - %v", path, err)
+		} else if structured != nil {
+			normalized = structured
+		}
+		if !bytes.Equal(content, normalized) {
+			return os.WriteFile(path, normalized, 0644)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+func normalizeXML(content []byte, ignored map[string]bool) ([]byte, error) {
+	doc := etree.NewDocument()
+	doc.WriteSettings.CanonicalText = true
+	err := doc.ReadFromBytes(content)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Root() == nil {
+		return content, nil
+	}
+	normalizeElement(doc.Root(), ignored)
+	normalized, err := doc.WriteToBytes()
+	if err != nil {
+		return nil, err
+	}
+	// Keep the trailing line break of the original content
+	if bytes.HasSuffix(content, []byte("\n")) && !bytes.HasSuffix(normalized, []byte("\n")) {
+		normalized = append(normalized, '\n')
+	}
+	return normalized, nil
+}
+
+func normalizeElement(element *etree.Element, ignored map[string]bool) {
+	// Remove ignored attributes and sort the remaining attributes
+	for _, attr := range append([]etree.Attr{}, element.Attr...) {
+		if ignored[attr.Key] {
+			element.RemoveAttr(attr.FullKey())
+		}
+	}
+	element.SortAttrs()
+
+	// Remove ignored child elements together with their indentation
+	for i := len(element.Child) - 1; i >= 0; i-- {
+		child, ok := element.Child[i].(*etree.Element)
+		if !ok || !ignored[child.Tag] {
+			continue
+		}
+		element.RemoveChildAt(i)
+		if i > 0 {
+			if charData, ok := element.Child[i-1].(*etree.CharData); ok && strings.TrimSpace(charData.Data) == "" {
+				element.RemoveChildAt(i - 1)
+			}
+		}
+	}
+
+	children := element.ChildElements()
+	if len(children) == 0 {
+		// JSON stored in text of elements (e.g. releaseMetadata) can also contain ignored fields
+		text := strings.TrimSpace(element.Text())
+		if strings.HasPrefix(text, "{") && json.Valid([]byte(text)) {
+			normalized, err := normalizeJSON([]byte(text), ignored, "")
+			if err == nil {
+				element.SetText(string(normalized))
+			}
+		}
+		return
+	}
+	for _, child := range children {
+		normalizeElement(child, ignored)
+	}
+	sortChildElements(element)
+}
+
+// sortChildElements sorts the child elements listed in apimSortedElements by their text, keeping the positions of other tokens
+func sortChildElements(element *etree.Element) {
+	slots := map[string][]int{}
+	for i, token := range element.Child {
+		if child, ok := token.(*etree.Element); ok && apimSortedElements[child.Tag] {
+			slots[child.Tag] = append(slots[child.Tag], i)
+		}
+	}
+	for _, indices := range slots {
+		var sorted []etree.Token
+		for _, i := range indices {
+			sorted = append(sorted, element.Child[i])
+		}
+		sort.SliceStable(sorted, func(a, b int) bool {
+			return sorted[a].(*etree.Element).Text() < sorted[b].(*etree.Element).Text()
+		})
+		for j, i := range indices {
+			element.Child[i] = sorted[j]
+		}
+	}
+	if len(slots) > 0 {
+		element.ReindexChildren()
+	}
+}
+
+func normalizeJSON(content []byte, ignored map[string]bool, indent string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var data any
+	err := dec.Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+	data = removeJSONFields(data, ignored)
+
+	// Keys of maps are sorted when encoded
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	err = enc.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	normalized := buf.Bytes()
+	if !bytes.HasSuffix(content, []byte("\n")) {
+		normalized = bytes.TrimSuffix(normalized, []byte("\n"))
+	}
+	return normalized, nil
+}
+
+func removeJSONFields(data any, ignored map[string]bool) any {
+	switch value := data.(type) {
+	case map[string]any:
+		for key, v := range value {
+			if ignored[key] {
+				delete(value, key)
+				continue
+			}
+			value[key] = removeJSONFields(v, ignored)
+		}
+	case []any:
+		for i, v := range value {
+			value[i] = removeJSONFields(v, ignored)
+		}
+	}
+	return data
+}
@@ -0,0 +1,53 @@
+package file
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAPIMContent(t *testing.T) {
+	gitDir := t.TempDir() + "/Northwind_V4"
+	err := copyDir("../../test/testdata/apim/Northwind_V4", gitDir)
+	if err != nil {
+		t.Fatalf("copyDir failed with error - %v", err)
+	}
+	tenantDir := t.TempDir() + "/Northwind_V4"
+	err = copyDir("../../test/testdata/apim/Northwind_V4", tenantDir)
+	if err != nil {
+		t.Fatalf("copyDir failed with error - %v", err)
+	}
+	// Content downloaded again has different volatile values, order of resources and line endings
+	proxyFile := tenantDir + "/APIProxies/Northwind_V4/Northwind_V4.xml"
+	content, _ := os.ReadFile(proxyFile)
+	downloaded := strings.NewReplacer(
+		`"changed_at":1695040378472`, `"changed_at":1700000000000`,
+		"<apiResourceName>Products</apiResourceName>\n            <apiResourceName>SWAGGER_JSON</apiResourceName>", "<apiResourceName>SWAGGER_JSON</apiResourceName>\n            <apiResourceName>Products</apiResourceName>",
+		"<changed_by>sb-apiaccess1694691998217!b17935|api-portal-xsuaa!b447</changed_by>", "<changed_by>another-user</changed_by>",
+	).Replace(string(content))
+	_ = os.WriteFile(proxyFile, []byte(strings.ReplaceAll(downloaded, "\n", "\r\n")), 0644)
+
+	assert.True(t, DiffDirectories(gitDir, tenantDir), "Expected raw content to differ")
+	for _, dir := range []string{gitDir, tenantDir} {
+		err = NormalizeAPIMContent(dir, nil)
+		if err != nil {
+			t.Fatalf("NormalizeAPIMContent failed with error - %v", err)
+		}
+	}
+	assert.False(t, DiffDirectories(gitDir, tenantDir), "Expected normalized content to be the same")
+
+	normalized, _ := os.ReadFile(proxyFile)
+	assert.NotContains(t, string(normalized), "changed_by", "Expected volatile fields removed")
+	assert.Contains(t, string(normalized), `<releaseMetadata>{"date":0,"reason":""}</releaseMetadata>`, "Expected volatile fields removed from JSON in element")
+	assert.NotContains(t, string(normalized), "\r\n", "Expected line endings normalized")
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	normalized, err := normalizeJSON([]byte(`{"name":"Orders","changed_at":1,"items":[{"b":1,"a":"<x>","created_by":"me"}]}`), map[string]bool{"changed_at": true, "created_by": true}, "")
+	if err != nil {
+		t.Fatalf("normalizeJSON failed with error - %v", err)
+	}
+	assert.Equal(t, `{"items":[{"a":"<x>","b":1}],"name":"Orders"}`, string(normalized), "Expected sorted keys without ignored fields")
+}
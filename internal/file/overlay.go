@@ -0,0 +1,307 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// OverlayRule describes an environment-specific value in a file of an APIProxy that is converted
+// when the APIProxy is moved between Git and the tenant. File is relative to the APIProxies/<name>
+// directory of the APIProxy. The value is located by an etree path (optionally with an attribute)
+// for XML files, or by a JSON path (e.g. $.a.b[0].c) for JSON files. Source is the value stored in
+// Git, Target is the value used in the tenant.
+type OverlayRule struct {
+	File      string `json:"file"`
+	XPath     string `json:"xpath,omitempty"`
+	Attribute string `json:"attribute,omitempty"`
+	JSONPath  string `json:"jsonPath,omitempty"`
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+// APIProxyOverlay contains the overlay rules for each APIProxy. Rules for "*" apply to all APIProxies.
+type APIProxyOverlay struct {
+	Proxies map[string][]*OverlayRule `json:"proxies"`
+}
+
+var jsonPathRegex = regexp.MustCompile(`^\$(\.[^.\[\]]+|\[\d+\])*$`)
+
+// LoadAPIProxyOverlay reads the overlay rules from a JSON file
+func LoadAPIProxyOverlay(overlayFile string) (*APIProxyOverlay, error) {
+	content, err := os.ReadFile(overlayFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	var overlay *APIProxyOverlay
+	err = json.Unmarshal(content, &overlay)
+	if err != nil {
+		log.Error().Msgf("Error unmarshalling file as JSON. File content = %s", content)
+		return nil, errors.Wrap(err, 0)
+	}
+	for proxyName, rules := range overlay.Proxies {
+		for i, rule := range rules {
+			if rule.File == "" {
+				return nil, fmt.Errorf("Rule %d of %v in %v must have file", i+1, proxyName, overlayFile)
+			}
+			if (rule.XPath == "") == (rule.JSONPath == "") {
+				return nil, fmt.Errorf("Rule %d of %v in %v must have either xpath or jsonPath", i+1, proxyName, overlayFile)
+			}
+			if rule.Attribute != "" && rule.XPath == "" {
+				return nil, fmt.Errorf("Rule %d of %v in %v must have xpath when attribute is used", i+1, proxyName, overlayFile)
+			}
+			if rule.JSONPath != "" && !jsonPathRegex.MatchString(rule.JSONPath) {
+				return nil, fmt.Errorf("Rule %d of %v in %v has invalid jsonPath %v", i+1, proxyName, overlayFile, rule.JSONPath)
+			}
+			if rule.Source == "" || rule.Target == "" {
+				return nil, fmt.Errorf("Rule %d of %v in %v must have both source and target", i+1, proxyName, overlayFile)
+			}
+		}
+	}
+	return overlay, nil
+}
+
+// Rules returns the rules that apply to the APIProxy. Rules for all APIProxies are applied first.
+func (o *APIProxyOverlay) Rules(proxyName string) []*OverlayRule {
+	if o == nil {
+		return nil
+	}
+	var rules []*OverlayRule
+	rules = append(rules, o.Proxies["*"]...)
+	rules = append(rules, o.Proxies[proxyName]...)
+	return rules
+}
+
+// Reverse returns the overlay for converting in the opposite direction (from tenant to Git)
+func (o *APIProxyOverlay) Reverse() *APIProxyOverlay {
+	if o == nil {
+		return nil
+	}
+	reversed := &APIProxyOverlay{Proxies: map[string][]*OverlayRule{}}
+	for proxyName, rules := range o.Proxies {
+		for _, rule := range rules {
+			reversed.Proxies[proxyName] = append(reversed.Proxies[proxyName], &OverlayRule{
+				File:      rule.File,
+				XPath:     rule.XPath,
+				Attribute: rule.Attribute,
+				JSONPath:  rule.JSONPath,
+				Source:    rule.Target,
+				Target:    rule.Source,
+			})
+		}
+	}
+	return reversed
+}
+
+// ApplyOverlay converts the values in the files of the APIProxy in artifactDir (the directory containing manifest.json)
+func ApplyOverlay(artifactDir string, proxyName string, rules []*OverlayRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	log.Debug().Msgf("Updating files in %v with %d overlay rule(s)", artifactDir, len(rules))
+
+	// Group rules by file so that each file is only written once
+	var files []string
+	fileRules := map[string][]*OverlayRule{}
+	for _, rule := range rules {
+		if fileRules[rule.File] == nil {
+			files = append(files, rule.File)
+		}
+		fileRules[rule.File] = append(fileRules[rule.File], rule)
+	}
+	proxyDir := filepath.Join(artifactDir, "APIProxies", proxyName)
+	for _, fileName := range files {
+		filePath := filepath.Join(proxyDir, filepath.FromSlash(fileName))
+		if !Exists(filePath) {
+			log.Warn().Msgf("⚠️ File %v of overlay rule does not exist in APIProxy %v", fileName, proxyName)
+			continue
+		}
+		var err error
+		if fileRules[fileName][0].JSONPath != "" || strings.HasSuffix(fileName, ".json") {
+			err = updateJSONFile(filePath, fileRules[fileName])
+		} else {
+			err = updateOverlayXML(filePath, fileRules[fileName])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateOverlayXML(filePath string, rules []*OverlayRule) error {
+	log.Info().Msgf("Applying overlay to file %v", filePath)
+	doc := etree.NewDocument()
+	// Only escape the characters that are required so that unchanged text (e.g. JSON in elements) is kept as is
+	doc.WriteSettings.CanonicalText = true
+	err := doc.ReadFromFile(filePath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	var xmlRules []*BPMNRule
+	for _, rule := range rules {
+		if rule.XPath == "" {
+			return fmt.Errorf("Overlay rule for XML file %v must have xpath", rule.File)
+		}
+		xmlRules = append(xmlRules, &BPMNRule{XPath: rule.XPath, Attribute: rule.Attribute, Source: rule.Source, Target: rule.Target})
+	}
+	contentUpdated, err := applyRules(doc, xmlRules)
+	if err != nil {
+		return err
+	}
+	if contentUpdated {
+		err = doc.WriteToFile(filePath)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+func updateJSONFile(filePath string, rules []*OverlayRule) error {
+	log.Info().Msgf("Applying overlay to file %v", filePath)
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	contentUpdated := false
+	for _, rule := range rules {
+		if rule.JSONPath == "" {
+			return fmt.Errorf("Overlay rule for JSON file %v must have jsonPath", rule.File)
+		}
+		var updated bool
+		content, updated, err = applyJSONRule(content, rule)
+		if err != nil {
+			return err
+		}
+		contentUpdated = contentUpdated || updated
+	}
+	if contentUpdated {
+		err = os.WriteFile(filePath, content, 0644)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+// applyJSONRule replaces the scalar value at the JSON path in place, so that the formatting and order of the
+// rest of the content is kept. A string value stays a string, other values are replaced by the raw target.
+func applyJSONRule(content []byte, rule *OverlayRule) ([]byte, bool, error) {
+	start, end, found, err := findJSONValue(content, rule.JSONPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return content, false, nil
+	}
+	raw := content[start:end]
+	isString := len(raw) > 0 && raw[0] == '"'
+	current := string(raw)
+	if isString {
+		if err = json.Unmarshal(raw, &current); err != nil {
+			return nil, false, errors.Wrap(err, 0)
+		}
+	}
+	if current != rule.Source {
+		return content, false, nil
+	}
+	replacement := []byte(rule.Target)
+	if isString {
+		replacement, err = json.Marshal(rule.Target)
+		if err != nil {
+			return nil, false, errors.Wrap(err, 0)
+		}
+	} else if !json.Valid(replacement) {
+		return nil, false, fmt.Errorf("Target %v for %v is not a valid JSON value", rule.Target, rule.JSONPath)
+	}
+	log.Debug().Msgf("Changing %v from %v to %v", rule.JSONPath, rule.Source, rule.Target)
+	updated := append(append(append([]byte{}, content[:start]...), replacement...), content[end:]...)
+	return updated, true, nil
+}
+
+type jsonFrame struct {
+	isArray   bool
+	index     int
+	key       string
+	expectKey bool
+}
+
+// findJSONValue returns the byte offsets of the scalar value at the JSON path
+func findJSONValue(content []byte, jsonPath string) (int, int, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	var stack []*jsonFrame
+	advance := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := stack[len(stack)-1]
+		if top.isArray {
+			top.index++
+		} else {
+			top.expectKey = true
+		}
+	}
+	currentPath := func() string {
+		var sb strings.Builder
+		sb.WriteString("$")
+		for _, frame := range stack {
+			if frame.isArray {
+				sb.WriteString("[" + strconv.Itoa(frame.index) + "]")
+			} else {
+				sb.WriteString("." + frame.key)
+			}
+		}
+		return sb.String()
+	}
+
+	for {
+		start := int(dec.InputOffset())
+		token, err := dec.Token()
+		if err == io.EOF {
+			return 0, 0, false, nil
+		}
+		if err != nil {
+			return 0, 0, false, errors.Wrap(err, 0)
+		}
+		end := int(dec.InputOffset())
+
+		if len(stack) > 0 && !stack[len(stack)-1].isArray && stack[len(stack)-1].expectKey {
+			if token == json.Delim('}') {
+				stack = stack[:len(stack)-1]
+				advance()
+			} else {
+				stack[len(stack)-1].key = token.(string)
+				stack[len(stack)-1].expectKey = false
+			}
+			continue
+		}
+		switch token {
+		case json.Delim('{'):
+			stack = append(stack, &jsonFrame{expectKey: true})
+		case json.Delim('['):
+			stack = append(stack, &jsonFrame{isArray: true})
+		case json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			advance()
+		default:
+			if currentPath() == jsonPath {
+				// Skip the separators and whitespace before the value
+				start += len(content[start:end]) - len(bytes.TrimLeft(content[start:end], " \t\r\n:,"))
+				return start, end, true, nil
+			}
+			advance()
+		}
+	}
+}
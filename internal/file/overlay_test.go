@@ -0,0 +1,78 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAPIProxyOverlay(t *testing.T) {
+	overlay, err := LoadAPIProxyOverlay("../../test/testdata/apim-overlay/overlay.json")
+	if err != nil {
+		t.Fatalf("LoadAPIProxyOverlay failed with error - %v", err)
+	}
+
+	rules := overlay.Rules("Northwind_V4")
+	assert.Equal(t, 3, len(rules), "Expected number of rules = 3")
+	assert.Equal(t, "/TargetEndPoint/loadBalancerConfigurations/isRetry", rules[0].XPath, "Expected rules for all APIProxies first")
+	assert.Equal(t, 1, len(overlay.Rules("Other")), "Expected only rules for all APIProxies")
+	assert.Equal(t, "Northwind", overlay.Reverse().Rules("Northwind_V4")[1].Target, "Expected source and target swapped")
+	assert.Nil(t, (*APIProxyOverlay)(nil).Rules("Northwind_V4"), "Expected no rules without overlay")
+}
+
+func TestApplyOverlay_ForwardAndReverse(t *testing.T) {
+	overlay, err := LoadAPIProxyOverlay("../../test/testdata/apim-overlay/overlay.json")
+	if err != nil {
+		t.Fatalf("LoadAPIProxyOverlay failed with error - %v", err)
+	}
+	artifactDir := t.TempDir() + "/Northwind_V4"
+	err = copyDir("../../test/testdata/apim/Northwind_V4", artifactDir)
+	if err != nil {
+		t.Fatalf("copyDir failed with error - %v", err)
+	}
+	targetEndpointFile := artifactDir + "/APIProxies/Northwind_V4/APITargetEndpoint/default.xml"
+
+	err = ApplyOverlay(artifactDir, "Northwind_V4", overlay.Rules("Northwind_V4"))
+	if err != nil {
+		t.Fatalf("ApplyOverlay failed with error - %v", err)
+	}
+	doc := etree.NewDocument()
+	if err = doc.ReadFromFile(targetEndpointFile); err != nil {
+		t.Fatalf("Reading target endpoint file failed with error - %v", err)
+	}
+	assert.Equal(t, "Northwind_PRD", doc.FindElement("/TargetEndPoint/provider_id").Text(), "API provider not converted")
+	assert.Equal(t, "true", doc.FindElement("/TargetEndPoint/loadBalancerConfigurations/isRetry").Text(), "Rule for all APIProxies not applied")
+
+	err = ApplyOverlay(artifactDir, "Northwind_V4", overlay.Reverse().Rules("Northwind_V4"))
+	if err != nil {
+		t.Fatalf("ApplyOverlay failed with error - %v", err)
+	}
+	assert.False(t, DiffDirectories("../../test/testdata/apim/Northwind_V4", artifactDir), "Expected content reverted to Git values")
+}
+
+func TestApplyJSONRule(t *testing.T) {
+	content := []byte(`{
+    "name": "Orders",
+    "quota": {"count": 100, "interval": "minute"},
+    "targets": [{"url": "https://dev.example.com"}, {"url": "https://dev.example.com"}]
+}`)
+
+	updated, changed, err := applyJSONRule(content, &OverlayRule{JSONPath: "$.targets[1].url", Source: "https://dev.example.com", Target: "https://prd.example.com"})
+	if err != nil {
+		t.Fatalf("applyJSONRule failed with error - %v", err)
+	}
+	assert.True(t, changed, "Expected content changed")
+	assert.Contains(t, string(updated), `[{"url": "https://dev.example.com"}, {"url": "https://prd.example.com"}]`, "Expected only second URL changed with formatting kept")
+
+	updated, changed, err = applyJSONRule(updated, &OverlayRule{JSONPath: "$.quota.count", Source: "100", Target: "1000"})
+	if err != nil {
+		t.Fatalf("applyJSONRule failed with error - %v", err)
+	}
+	assert.True(t, changed, "Expected content changed")
+	assert.Contains(t, string(updated), `{"count": 1000, "interval": "minute"}`, "Expected number replaced")
+
+	_, changed, err = applyJSONRule(updated, &OverlayRule{JSONPath: "$.name", Source: "Invoices", Target: "Bills"})
+	assert.NoError(t, err)
+	assert.False(t, changed, "Expected no change when value does not match source")
+}
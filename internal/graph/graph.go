@@ -0,0 +1,365 @@
+package graph
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/go-errors/errors"
+	"github.com/magiconair/properties"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	NodeIntegrationFlow  = "IntegrationFlow"
+	NodeScriptCollection = "ScriptCollection"
+	NodeMessageMapping   = "MessageMapping"
+	NodeValueMapping     = "ValueMapping"
+	NodeProcessDirect    = "ProcessDirect"
+	NodeJMSQueue         = "JMSQueue"
+
+	// Edges point from the dependent node to the node it depends on
+	EdgeUses          = "uses"
+	EdgeCalls         = "calls"
+	EdgeImplementedBy = "implemented-by"
+	EdgeConsumes      = "consumes"
+	EdgeProducedBy    = "produced-by"
+)
+
+var placeholderRegex = regexp.MustCompile(`{{([^{}]+)}}`)
+
+// mappedValueRegex matches value mapping lookups of the ValueMappingApi in scripts with literal source and target
+// agencies and identifiers, e.g. getMappedValue("SAP", "Country", value, "Legacy", "CountryName")
+var mappedValueRegex = regexp.MustCompile(`getMappedValues?\(\s*['"]([^'"]+)['"]\s*,\s*['"]([^'"]+)['"]\s*,[^,]+,\s*['"]([^'"]+)['"]\s*,\s*['"]([^'"]+)['"]`)
+
+type Node struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	Dir  string `json:"dir,omitempty"`
+}
+
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+	nodes map[string]*Node
+	edges map[Edge]bool
+	// ID of the value mapping artifact of each agency and identifier pair
+	valueMappings map[valueMappingKey]string
+}
+
+type valueMappingKey struct {
+	agency     string
+	identifier string
+}
+
+func New() *Graph {
+	g := new(Graph)
+	g.Nodes = []*Node{}
+	g.Edges = []*Edge{}
+	g.nodes = map[string]*Node{}
+	g.edges = map[Edge]bool{}
+	g.valueMappings = map[valueMappingKey]string{}
+	return g
+}
+
+// Build parses all artifact directories (directories containing META-INF/MANIFEST.MF) under
+// the root directory and returns the dependency graph between them
+func Build(rootDir string) (*Graph, error) {
+	g := New()
+	var iflowDirs, scriptCollectionDirs []string
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != rootDir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !file.Exists(filepath.Join(path, "META-INF", "MANIFEST.MF")) {
+			return nil
+		}
+		headers, err := file.GetManifestHeaders(filepath.Join(path, "META-INF", "MANIFEST.MF"))
+		if err != nil {
+			return err
+		}
+		nodeType := headers.Get("SAP-BundleType")
+		switch nodeType {
+		case NodeIntegrationFlow:
+			iflowDirs = append(iflowDirs, path)
+		case NodeScriptCollection:
+			scriptCollectionDirs = append(scriptCollectionDirs, path)
+		case NodeValueMapping:
+			if err = g.addValueMappingIdentifiers(file.GetArtifactId(headers), path); err != nil {
+				return err
+			}
+		case NodeMessageMapping:
+		default:
+			log.Warn().Msgf("Skipping artifact in %v with unsupported bundle type %v", path, nodeType)
+			return filepath.SkipDir
+		}
+		g.AddNode(&Node{Id: file.GetArtifactId(headers), Type: nodeType, Name: headers.Get("Bundle-Name"), Dir: path})
+		// Artifacts are not nested
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	// References are processed after all artifacts are known so that the type of referenced artifacts is available
+	for _, iflowDir := range iflowDirs {
+		headers, err := file.GetManifestHeaders(filepath.Join(iflowDir, "META-INF", "MANIFEST.MF"))
+		if err != nil {
+			return nil, err
+		}
+		err = g.addIFlowReferences(file.GetArtifactId(headers), iflowDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Value mappings are also referenced by scripts of script collections
+	for _, scriptCollectionDir := range scriptCollectionDirs {
+		headers, err := file.GetManifestHeaders(filepath.Join(scriptCollectionDir, "META-INF", "MANIFEST.MF"))
+		if err != nil {
+			return nil, err
+		}
+		err = g.addScriptReferences(file.GetArtifactId(headers), scriptCollectionDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	g.sort()
+	return g, nil
+}
+
+func (g *Graph) AddNode(node *Node) *Node {
+	if existing, ok := g.nodes[node.Id]; ok {
+		return existing
+	}
+	g.nodes[node.Id] = node
+	g.Nodes = append(g.Nodes, node)
+	return node
+}
+
+func (g *Graph) AddEdge(from string, to string, edgeType string) {
+	edge := Edge{From: from, To: to, Type: edgeType}
+	if !g.edges[edge] {
+		g.edges[edge] = true
+		g.Edges = append(g.Edges, &edge)
+	}
+}
+
+func (g *Graph) Node(id string) *Node {
+	return g.nodes[id]
+}
+
+func (g *Graph) addIFlowReferences(iflowId string, iflowDir string) error {
+	// Externalized values are resolved from parameters.prop of the IFlow
+	parameters := map[string]string{}
+	parametersFile := filepath.Join(iflowDir, "src", "main", "resources", "parameters.prop")
+	if file.Exists(parametersFile) {
+		p, err := properties.LoadFile(parametersFile, properties.UTF8)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		parameters = p.Map()
+	}
+	resolve := func(value string) string {
+		return placeholderRegex.ReplaceAllStringFunc(value, func(placeholder string) string {
+			key := placeholderRegex.FindStringSubmatch(placeholder)[1]
+			if v, ok := parameters[key]; ok {
+				return v
+			}
+			return placeholder
+		})
+	}
+
+	// Value mappings referenced in scripts of the IFlow
+	err := g.addScriptReferences(iflowId, iflowDir)
+	if err != nil {
+		return err
+	}
+
+	bpmnDir := filepath.Join(iflowDir, "src", "main", "resources", "scenarioflows", "integrationflow")
+	entries, err := os.ReadDir(bpmnDir)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".iflw") {
+			continue
+		}
+		doc := etree.NewDocument()
+		if err = doc.ReadFromFile(filepath.Join(bpmnDir, entry.Name())); err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		// Script collections and mappings referenced in steps
+		for _, activity := range doc.FindElements("//bpmn2:callActivity") {
+			props := file.GetIFlowProperties(activity)
+			if scriptBundleId := resolve(props["scriptBundleId"]); scriptBundleId != "" {
+				g.addArtifactReference(iflowId, scriptBundleId, NodeScriptCollection)
+			}
+			if mappingId := getMappingId(resolve(props["mappinguri"])); mappingId != "" {
+				g.addArtifactReference(iflowId, mappingId, NodeMessageMapping)
+			}
+		}
+
+		// ProcessDirect and JMS adapters
+		for _, messageFlow := range doc.FindElements("//bpmn2:messageFlow") {
+			props := file.GetIFlowProperties(messageFlow)
+			direction := props["direction"]
+			switch props["ComponentType"] {
+			case "ProcessDirect":
+				address := resolve(props["address"])
+				if address == "" {
+					continue
+				}
+				endpointId := fmt.Sprintf("%v:%v", NodeProcessDirect, address)
+				g.AddNode(&Node{Id: endpointId, Type: NodeProcessDirect, Name: address})
+				if direction == "Receiver" {
+					g.AddEdge(iflowId, endpointId, EdgeCalls)
+				} else if direction == "Sender" {
+					g.AddEdge(endpointId, iflowId, EdgeImplementedBy)
+				}
+			case "JMS":
+				if direction == "Receiver" {
+					if queue := resolve(props["QueueName_outbound"]); queue != "" {
+						queueId := fmt.Sprintf("%v:%v", NodeJMSQueue, queue)
+						g.AddNode(&Node{Id: queueId, Type: NodeJMSQueue, Name: queue})
+						g.AddEdge(queueId, iflowId, EdgeProducedBy)
+					}
+				} else if direction == "Sender" {
+					if queue := resolve(props["QueueName_inbound"]); queue != "" {
+						queueId := fmt.Sprintf("%v:%v", NodeJMSQueue, queue)
+						g.AddNode(&Node{Id: queueId, Type: NodeJMSQueue, Name: queue})
+						g.AddEdge(iflowId, queueId, EdgeConsumes)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addValueMappingIdentifiers registers the agency and identifier pairs of the value mapping artifact so that
+// references to them can be linked to the artifact
+func (g *Graph) addValueMappingIdentifiers(valueMappingId string, valueMappingDir string) error {
+	valueMappingFile := filepath.Join(valueMappingDir, "value_mapping.xml")
+	if !file.Exists(valueMappingFile) {
+		return nil
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromFile(valueMappingFile); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	for _, entry := range doc.FindElements("//group/entry") {
+		agency, identifier := entry.SelectElement("agency"), entry.SelectElement("schema")
+		if agency != nil && identifier != nil {
+			g.valueMappings[valueMappingKey{agency: agency.Text(), identifier: identifier.Text()}] = valueMappingId
+		}
+	}
+	return nil
+}
+
+// addScriptReferences adds edges to the value mappings that are looked up with the ValueMappingApi in the Groovy
+// scripts of the artifact. Lookups are linked by their source or target agency and identifier.
+func (g *Graph) addScriptReferences(artifactId string, artifactDir string) error {
+	scriptDir := filepath.Join(artifactDir, "src", "main", "resources", "script")
+	if !file.Exists(scriptDir) {
+		return nil
+	}
+	return filepath.WalkDir(scriptDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if d.IsDir() || (!strings.HasSuffix(path, ".groovy") && !strings.HasSuffix(path, ".gsh")) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		for _, match := range mappedValueRegex.FindAllStringSubmatch(string(content), -1) {
+			for _, key := range []valueMappingKey{{agency: match[1], identifier: match[2]}, {agency: match[3], identifier: match[4]}} {
+				if valueMappingId, ok := g.valueMappings[key]; ok {
+					g.addArtifactReference(artifactId, valueMappingId, NodeValueMapping)
+					break
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// addArtifactReference adds an edge to a referenced artifact. If the artifact is not part of the
+// parsed content, it is added with the default type.
+func (g *Graph) addArtifactReference(iflowId string, referencedId string, defaultType string) {
+	node := g.AddNode(&Node{Id: referencedId, Type: defaultType})
+	g.AddEdge(iflowId, node.Id, EdgeUses)
+}
+
+// ImpactedBy returns the subgraph of nodes that directly or indirectly depend on the node
+func (g *Graph) ImpactedBy(id string) (*Graph, error) {
+	if g.nodes[id] == nil {
+		return nil, fmt.Errorf("Node %v not found in dependency graph", id)
+	}
+	// Index edges by target for traversal in reverse direction
+	dependents := map[string][]*Edge{}
+	for _, edge := range g.Edges {
+		dependents[edge.To] = append(dependents[edge.To], edge)
+	}
+
+	sub := New()
+	sub.AddNode(g.nodes[id])
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, edge := range dependents[current] {
+			if sub.nodes[edge.From] == nil {
+				sub.AddNode(g.nodes[edge.From])
+				queue = append(queue, edge.From)
+			}
+			sub.AddEdge(edge.From, edge.To, edge.Type)
+		}
+	}
+	sub.sort()
+	return sub, nil
+}
+
+func (g *Graph) sort() {
+	sort.SliceStable(g.Nodes, func(i, j int) bool {
+		return g.Nodes[i].Id < g.Nodes[j].Id
+	})
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+}
+
+// getMappingId returns the ID of a mapping artifact referenced as p://<package>/.../<id>.
+// Mappings that are local to the IFlow (dir://) are not separate artifacts.
+func getMappingId(mappingUri string) string {
+	if !strings.HasPrefix(mappingUri, "p://") {
+		return ""
+	}
+	segments := strings.Split(strings.TrimSuffix(mappingUri, "/"), "/")
+	return segments[len(segments)-1]
+}
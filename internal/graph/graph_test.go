@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findEdge(g *Graph, from string, to string) *Edge {
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to {
+			return edge
+		}
+	}
+	return nil
+}
+
+func nodeIds(g *Graph) []string {
+	var ids []string
+	for _, node := range g.Nodes {
+		ids = append(ids, node.Id)
+	}
+	return ids
+}
+
+func TestBuild(t *testing.T) {
+	g, err := Build("../../test/testdata/Graph")
+	if err != nil {
+		t.Fatalf("Build failed with error - %v", err)
+	}
+
+	assert.Equal(t, 8, len(g.Nodes), "Expected number of nodes = 8")
+	assert.Equal(t, 8, len(g.Edges), "Expected number of edges = 8")
+	assert.Equal(t, NodeMessageMapping, g.Node("Orders_Mapping").Type, "Expected type of Orders_Mapping = MessageMapping")
+	assert.NotNil(t, findEdge(g, "Orders_Inbound", "Orders_Scripts"), "Expected edge from Orders_Inbound to Orders_Scripts")
+	assert.NotNil(t, findEdge(g, "Orders_Inbound", "Orders_Mapping"), "Expected edge from Orders_Inbound to Orders_Mapping")
+	// ProcessDirect address is resolved from parameters.prop
+	assert.Equal(t, EdgeCalls, findEdge(g, "Orders_Inbound", "ProcessDirect:/orders/process").Type, "Expected calls edge to ProcessDirect")
+	assert.Equal(t, EdgeImplementedBy, findEdge(g, "ProcessDirect:/orders/process", "Orders_Process").Type, "Expected implemented-by edge from ProcessDirect")
+	assert.Equal(t, EdgeProducedBy, findEdge(g, "JMSQueue:Orders_Audit", "Orders_Inbound").Type, "Expected produced-by edge from JMS queue")
+	assert.Equal(t, EdgeConsumes, findEdge(g, "Orders_Audit", "JMSQueue:Orders_Audit").Type, "Expected consumes edge to JMS queue")
+	// Value mappings are linked by the agency and identifier of lookups in scripts
+	assert.Equal(t, NodeValueMapping, g.Node("Orders_ValueMapping").Type, "Expected type of Orders_ValueMapping = ValueMapping")
+	assert.Equal(t, EdgeUses, findEdge(g, "Orders_Process", "Orders_ValueMapping").Type, "Expected uses edge from IFlow script to value mapping")
+	assert.Equal(t, EdgeUses, findEdge(g, "Orders_Scripts", "Orders_ValueMapping").Type, "Expected uses edge from script collection to value mapping")
+}
+
+func TestImpactedByValueMapping(t *testing.T) {
+	g, err := Build("../../test/testdata/Graph")
+	if err != nil {
+		t.Fatalf("Build failed with error - %v", err)
+	}
+
+	impacted, err := g.ImpactedBy("Orders_ValueMapping")
+	if err != nil {
+		t.Fatalf("ImpactedBy failed with error - %v", err)
+	}
+	assert.Contains(t, nodeIds(impacted), "Orders_Process", "Expected IFlow with value mapping lookup impacted")
+	assert.Contains(t, nodeIds(impacted), "Orders_Inbound", "Expected IFlow using script collection with value mapping lookup impacted")
+}
+
+func TestImpactedBy(t *testing.T) {
+	g, err := Build("../../test/testdata/Graph")
+	if err != nil {
+		t.Fatalf("Build failed with error - %v", err)
+	}
+
+	impacted, err := g.ImpactedBy("Orders_Scripts")
+	if err != nil {
+		t.Fatalf("ImpactedBy failed with error - %v", err)
+	}
+	// Impact is transitive, Orders_Audit consumes the JMS queue that Orders_Inbound produces to
+	assert.Equal(t, []string{"JMSQueue:Orders_Audit", "Orders_Audit", "Orders_Inbound", "Orders_Scripts"}, nodeIds(impacted), "Expected Orders_Inbound and Orders_Audit impacted by Orders_Scripts")
+
+	impacted, err = g.ImpactedBy("Orders_Process")
+	if err != nil {
+		t.Fatalf("ImpactedBy failed with error - %v", err)
+	}
+	assert.Contains(t, nodeIds(impacted), "Orders_Inbound", "Expected ProcessDirect producer impacted by consumer")
+	assert.NotContains(t, nodeIds(impacted), "Orders_Scripts", "Expected dependencies of impacted nodes not included")
+
+	impacted, err = g.ImpactedBy("Orders_Inbound")
+	if err != nil {
+		t.Fatalf("ImpactedBy failed with error - %v", err)
+	}
+	assert.Equal(t, []string{"JMSQueue:Orders_Audit", "Orders_Audit", "Orders_Inbound"}, nodeIds(impacted), "Expected JMS consumer impacted by producer")
+
+	_, err = g.ImpactedBy("Unknown")
+	assert.Error(t, err, "Expected error for unknown node")
+}
+
+func TestWrite(t *testing.T) {
+	g, err := Build("../../test/testdata/Graph")
+	if err != nil {
+		t.Fatalf("Build failed with error - %v", err)
+	}
+
+	var dot bytes.Buffer
+	err = g.Write(&dot, "dot")
+	if err != nil {
+		t.Fatalf("Write failed with error - %v", err)
+	}
+	assert.Contains(t, dot.String(), `"Orders_Inbound" -> "Orders_Scripts" [label="uses"];`, "Expected DOT edge")
+
+	var mermaid bytes.Buffer
+	err = g.Write(&mermaid, "mermaid")
+	if err != nil {
+		t.Fatalf("Write failed with error - %v", err)
+	}
+	assert.Contains(t, mermaid.String(), "graph LR", "Expected Mermaid graph")
+	assert.Contains(t, mermaid.String(), "-->|consumes|", "Expected Mermaid edge")
+
+	err = g.Write(&bytes.Buffer{}, "svg")
+	assert.Error(t, err, "Expected error for invalid format")
+}
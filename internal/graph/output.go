@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+var dotShapes = map[string]string{
+	NodeIntegrationFlow:  "box",
+	NodeScriptCollection: "note",
+	NodeMessageMapping:   "component",
+	NodeValueMapping:     "component",
+	NodeProcessDirect:    "ellipse",
+	NodeJMSQueue:         "cylinder",
+}
+
+// Write outputs the graph in the format (dot, mermaid or json)
+func (g *Graph) Write(w io.Writer, format string) error {
+	var err error
+	switch format {
+	case "dot":
+		err = g.writeDOT(w)
+	case "mermaid":
+		err = g.writeMermaid(w)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(g)
+	default:
+		return fmt.Errorf("invalid value for output format = %v", format)
+	}
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+func (g *Graph) writeDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		sb.WriteString(fmt.Sprintf("  %q [label=%q, shape=%v];\n", node.Id, fmt.Sprintf("%v\n(%v)", nodeLabel(node), node.Type), dotShapes[node.Type]))
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Type))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (g *Graph) writeMermaid(w io.Writer) error {
+	// Mermaid node IDs cannot contain special characters, so nodes are referred to by index
+	ids := map[string]string{}
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for i, node := range g.Nodes {
+		ids[node.Id] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(fmt.Sprintf("%v<br/>(%v)", nodeLabel(node), node.Type), `"`, "#quot;")
+		switch node.Type {
+		case NodeJMSQueue:
+			sb.WriteString(fmt.Sprintf("  %v[(\"%v\")]\n", ids[node.Id], label))
+		case NodeProcessDirect:
+			sb.WriteString(fmt.Sprintf("  %v([\"%v\"])\n", ids[node.Id], label))
+		default:
+			sb.WriteString(fmt.Sprintf("  %v[\"%v\"]\n", ids[node.Id], label))
+		}
+	}
+	for _, edge := range g.Edges {
+		sb.WriteString(fmt.Sprintf("  %v -->|%v| %v\n", ids[edge.From], edge.Type, ids[edge.To]))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func nodeLabel(node *Node) string {
+	if node.Type == NodeProcessDirect || node.Type == NodeJMSQueue {
+		return node.Name
+	}
+	return node.Id
+}
@@ -2,8 +2,10 @@ package httpclnt
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 	"io"
 	"net/http"
@@ -23,6 +25,13 @@ type HTTPExecuter struct {
 
 // New returns an initialised HTTPExecuter instance.
 func New(oauthHost string, oauthPath string, clientId string, clientSecret string, userId string, password string, host string, scheme string, port int, showLogs bool) *HTTPExecuter {
+	return NewWithTLS(oauthHost, oauthPath, clientId, clientSecret, userId, password, host, scheme, port, showLogs, nil)
+}
+
+// NewWithTLS returns an initialised HTTPExecuter instance that uses the TLS configuration (e.g. with a client
+// certificate) for calls to the host and the OAuth token server. If the TLS configuration has a client certificate,
+// OAuth tokens are requested without client secret.
+func NewWithTLS(oauthHost string, oauthPath string, clientId string, clientSecret string, userId string, password string, host string, scheme string, port int, showLogs bool, tlsConfig *tls.Config) *HTTPExecuter {
 	e := new(HTTPExecuter)
 	e.host = host
 	e.scheme = scheme
@@ -46,13 +55,32 @@ func New(oauthHost string, oauthPath string, clientId string, clientSecret strin
 		}
 
 		ctx := context.Background()
+		if tlsConfig != nil {
+			// The token request and the API calls both use the TLS configuration of the context client
+			ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: 30 * time.Second, Transport: newTransport(tlsConfig)})
+			if len(tlsConfig.Certificates) > 0 {
+				if showLogs {
+					log.Debug().Msg("Using client certificate for OAuth 2.0 token request")
+				}
+				conf.AuthStyle = oauth2.AuthStyleInParams
+			}
+		}
 		e.httpClient = conf.Client(ctx)
 		e.AuthType = "OAUTH"
+	} else if userId == "" && tlsConfig != nil && len(tlsConfig.Certificates) > 0 {
+		if showLogs {
+			log.Debug().Msg("Initialising HTTP client with client certificate")
+		}
+		e.httpClient = &http.Client{Timeout: 30 * time.Second, Transport: newTransport(tlsConfig)}
+		e.AuthType = "CERTIFICATE"
 	} else {
 		if showLogs {
 			log.Debug().Msg("Initialising HTTP client with Basic Authentication")
 		}
 		e.httpClient = &http.Client{Timeout: 30 * time.Second}
+		if tlsConfig != nil {
+			e.httpClient.Transport = newTransport(tlsConfig)
+		}
 		e.basicUserId = userId
 		e.basicPassword = password
 		e.AuthType = "BASIC"
@@ -60,6 +88,12 @@ func New(oauthHost string, oauthPath string, clientId string, clientSecret strin
 	return e
 }
 
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
 func (e *HTTPExecuter) ExecRequestWithCookies(method string, path string, body io.Reader, headers map[string]string, cookies []*http.Cookie) (resp *http.Response, err error) {
 
 	url := fmt.Sprintf("%v://%v:%d%v", e.scheme, e.host, e.port, path)
@@ -6,7 +6,7 @@ import (
 )
 
 func GetHostPort(url string) (string, int) {
-	urlParts := strings.Split(strings.TrimPrefix(url, "http://"), ":")
+	urlParts := strings.Split(strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://"), ":")
 	i, _ := strconv.Atoi(urlParts[1])
 	return urlParts[0], i
 }
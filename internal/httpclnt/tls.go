@@ -0,0 +1,65 @@
+package httpclnt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/go-errors/errors"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// LoadClientCertificate returns the client certificate for mutual TLS from PEM files (certificate and private key) or
+// from a PKCS#12 file. It returns nil if no files are provided.
+func LoadClientCertificate(certFile string, keyFile string, pkcs12File string, pkcs12Password string) (*tls.Certificate, error) {
+	switch {
+	case pkcs12File != "" && (certFile != "" || keyFile != ""):
+		return nil, fmt.Errorf("Client certificate must be provided either as PEM files or as PKCS#12 file, not both")
+	case pkcs12File != "":
+		content, err := os.ReadFile(pkcs12File)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		// The certificate chain in the file is also sent to the server
+		privateKey, leaf, caCerts, err := pkcs12.DecodeChain(content, pkcs12Password)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading PKCS#12 file %v: %w", pkcs12File, err)
+		}
+		certificate := tls.Certificate{Certificate: [][]byte{leaf.Raw}, PrivateKey: privateKey, Leaf: leaf}
+		for _, caCert := range caCerts {
+			certificate.Certificate = append(certificate.Certificate, caCert.Raw)
+		}
+		return &certificate, nil
+	case certFile != "" || keyFile != "":
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("Client certificate in PEM format requires both certificate and private key files")
+		}
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading client certificate %v and private key %v: %w", certFile, keyFile, err)
+		}
+		return &certificate, nil
+	}
+	return nil, nil
+}
+
+// LoadCABundle returns the pool of CA certificates in the PEM file that are trusted in addition to the system
+// CA certificates. It returns nil if no file is provided.
+func LoadCABundle(caBundleFile string) (*x509.CertPool, error) {
+	if caBundleFile == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(caBundleFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("No certificates found in CA bundle %v", caBundleFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,172 @@
+package httpclnt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCertFile = "../../test/testdata/tls/client.crt"
+const testKeyFile = "../../test/testdata/tls/client.key"
+const testPKCS12File = "../../test/testdata/tls/client.p12"
+
+// testPKCS12OpenSSL3File is created with the defaults of OpenSSL 3 (PBES2 with AES-256-CBC and SHA-256 MAC)
+const testPKCS12OpenSSL3File = "../../test/testdata/tls/client-openssl3.p12"
+
+// newMutualTLSServer returns a started local TLS server that requires the test client certificate
+func newMutualTLSServer(t *testing.T, handler http.Handler) *httptest.Server {
+	content, err := os.ReadFile(testCertFile)
+	if err != nil {
+		t.Fatalf("ReadFile failed with error - %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(content)
+
+	svr := httptest.NewUnstartedServer(handler)
+	svr.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	svr.StartTLS()
+	t.Cleanup(svr.Close)
+	return svr
+}
+
+// clientTLSConfig returns the TLS configuration of the client that trusts the local TLS server
+func clientTLSConfig(t *testing.T, svr *httptest.Server) *tls.Config {
+	certificate, err := LoadClientCertificate(testCertFile, testKeyFile, "", "")
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed with error - %v", err)
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(svr.Certificate())
+	return &tls.Config{RootCAs: rootCAs, Certificates: []tls.Certificate{*certificate}}
+}
+
+func TestMockClientCertificate(t *testing.T) {
+	svr := newMutualTLSServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			http.Error(w, "Unexpected authorization header", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{ "d": { "Id": "Dummy" } }`))
+	}))
+
+	host, port := GetHostPort(svr.URL)
+	exe := NewWithTLS("", "", "", "", "", "", host, "https", port, true, clientTLSConfig(t, svr))
+	assert.Equal(t, "CERTIFICATE", exe.AuthType, "Incorrect authentication type")
+
+	resp, err := exe.ExecGetRequest("/api/v1/IntegrationDesigntimeArtifacts(Id='Dummy',Version='Active')", map[string]string{"Accept": "application/json"})
+	if err != nil {
+		t.Fatalf("HTTP call failed with error - %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("HTTP call failed with response code - %v", resp.StatusCode)
+	}
+}
+
+func TestMockClientCertificateRejected(t *testing.T) {
+	svr := newMutualTLSServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	host, port := GetHostPort(svr.URL)
+	tlsConfig := clientTLSConfig(t, svr)
+	tlsConfig.Certificates = nil
+	exe := NewWithTLS("", "", "", "", "dummyuser", "dummypassword", host, "https", port, true, tlsConfig)
+
+	_, err := exe.ExecGetRequest("/api/v1/IntegrationDesigntimeArtifacts", nil)
+	assert.Error(t, err, "Expected TLS handshake to fail without client certificate")
+}
+
+func TestMockOauthClientCertificate(t *testing.T) {
+	const clientId = "dummyid"
+	const token = "token123"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.FormValue("client_id") != clientId || r.FormValue("client_secret") != "" {
+			http.Error(w, "Invalid credentials for token URL authorization", http.StatusUnauthorized)
+			return
+		}
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "flashpipe-test-client" {
+			http.Error(w, "Missing client certificate", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{ "access_token": "` + token + `" }`))
+	})
+	mux.HandleFunc("/api/v1/IntegrationDesigntimeArtifacts(Id='Dummy',Version='Active')", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "Invalid token for endpoint authorization", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{ "d": { "Id": "Dummy" } }`))
+	})
+	svr := newMutualTLSServer(t, mux)
+
+	host, port := GetHostPort(svr.URL)
+	exe := NewWithTLS(host, "/oauth/token", clientId, "", "", "", host, "https", port, true, clientTLSConfig(t, svr))
+
+	resp, err := exe.ExecGetRequest("/api/v1/IntegrationDesigntimeArtifacts(Id='Dummy',Version='Active')", map[string]string{"Accept": "application/json"})
+	if err != nil {
+		t.Fatalf("HTTP call failed with error - %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("HTTP call failed with response code - %v", resp.StatusCode)
+	}
+}
+
+func TestLoadClientCertificate_PKCS12(t *testing.T) {
+	fromPKCS12, err := LoadClientCertificate("", "", testPKCS12File, "changeit")
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed with error - %v", err)
+	}
+	fromPEM, err := LoadClientCertificate(testCertFile, testKeyFile, "", "")
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed with error - %v", err)
+	}
+	assert.Equal(t, fromPEM.Certificate, fromPKCS12.Certificate, "Expected same certificate from PKCS#12 and PEM files")
+
+	_, err = LoadClientCertificate("", "", testPKCS12File, "wrong")
+	assert.ErrorContains(t, err, "Error reading PKCS#12 file")
+}
+
+func TestLoadClientCertificate_PKCS12OpenSSL3(t *testing.T) {
+	fromPKCS12, err := LoadClientCertificate("", "", testPKCS12OpenSSL3File, "changeit")
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed with error - %v", err)
+	}
+	fromPEM, err := LoadClientCertificate(testCertFile, testKeyFile, "", "")
+	if err != nil {
+		t.Fatalf("LoadClientCertificate failed with error - %v", err)
+	}
+	assert.Equal(t, fromPEM.Certificate, fromPKCS12.Certificate, "Expected same certificate from PKCS#12 and PEM files")
+	assert.Equal(t, fromPEM.PrivateKey, fromPKCS12.PrivateKey, "Expected same private key from PKCS#12 and PEM files")
+}
+
+func TestLoadClientCertificate_Invalid(t *testing.T) {
+	certificate, err := LoadClientCertificate("", "", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, certificate, "Expected no certificate without files")
+
+	_, err = LoadClientCertificate(testCertFile, testKeyFile, testPKCS12File, "changeit")
+	assert.ErrorContains(t, err, "either as PEM files or as PKCS#12 file, not both")
+	_, err = LoadClientCertificate(testCertFile, "", "", "")
+	assert.ErrorContains(t, err, "requires both certificate and private key files")
+	_, err = LoadClientCertificate(testKeyFile, testKeyFile, "", "")
+	assert.ErrorContains(t, err, "Error reading client certificate")
+}
+
+func TestLoadCABundle(t *testing.T) {
+	pool, err := LoadCABundle(testCertFile)
+	if err != nil {
+		t.Fatalf("LoadCABundle failed with error - %v", err)
+	}
+	assert.NotNil(t, pool, "Expected CA certificate pool")
+
+	_, err = LoadCABundle(testKeyFile)
+	assert.ErrorContains(t, err, "No certificates found in CA bundle")
+}
@@ -0,0 +1,291 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+// Config is the content of the lint configuration file. Rules overrides the settings of
+// built-in rules by rule ID, Custom contains additional user-defined rules.
+type Config struct {
+	Rules  map[string]*RuleConfig `json:"rules"`
+	Custom []*CustomRule          `json:"custom"`
+}
+
+type RuleConfig struct {
+	Severity string   `json:"severity,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Max      int      `json:"max,omitempty"`
+	Keys     []string `json:"keys,omitempty"`
+}
+
+// CustomRule checks an element (located by xpath) of the IFlow BPMN2 XML or parameters.propdef,
+// or a header of MANIFEST.MF. When a pattern is provided, only elements/headers with values
+// matching the pattern are considered. Mode "required" reports a finding when nothing matches,
+// mode "forbidden" reports a finding for each match.
+type CustomRule struct {
+	Id          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	File        string `json:"file"`
+	XPath       string `json:"xpath,omitempty"`
+	Header      string `json:"header,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	Mode        string `json:"mode"`
+}
+
+type Finding struct {
+	RuleId     string `json:"ruleId"`
+	Severity   string `json:"severity"`
+	ArtifactId string `json:"artifactId"`
+	File       string `json:"file"`
+	Element    string `json:"element,omitempty"`
+	Message    string `json:"message"`
+}
+
+type RuleInfo struct {
+	Id          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+}
+
+type Linter struct {
+	rules []*rule
+}
+
+type rule struct {
+	id          string
+	description string
+	severity    string
+	config      *RuleConfig
+	check       func(a *artifact, r *rule) []*Finding
+}
+
+type artifact struct {
+	id          string
+	dir         string
+	rootDir     string
+	headers     textproto.MIMEHeader
+	iflws       map[string]*etree.Document
+	propdefFile string
+	propdef     *etree.Document
+	scriptFiles []string
+}
+
+// LoadConfig reads the lint configuration from a JSON file. An empty file name returns
+// the default configuration with only the built-in rules.
+func LoadConfig(configFile string) (*Config, error) {
+	config := &Config{}
+	if configFile == "" {
+		return config, nil
+	}
+	content, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	err = json.Unmarshal(content, config)
+	if err != nil {
+		log.Error().Msgf("Error unmarshalling file as JSON. File content = %s", content)
+		return nil, errors.Wrap(err, 0)
+	}
+	return config, nil
+}
+
+func New(config *Config) (*Linter, error) {
+	l := new(Linter)
+	for _, builtIn := range builtInRules() {
+		ruleConfig := config.Rules[builtIn.id]
+		if ruleConfig != nil {
+			if ruleConfig.Severity != "" {
+				builtIn.severity = ruleConfig.Severity
+			}
+			if ruleConfig.Pattern != "" {
+				builtIn.config.Pattern = ruleConfig.Pattern
+			}
+			if ruleConfig.Max > 0 {
+				builtIn.config.Max = ruleConfig.Max
+			}
+			if len(ruleConfig.Keys) > 0 {
+				builtIn.config.Keys = ruleConfig.Keys
+			}
+		}
+		if builtIn.config.Pattern != "" {
+			if _, err := regexp.Compile(builtIn.config.Pattern); err != nil {
+				return nil, fmt.Errorf("Invalid pattern for rule %v: %w", builtIn.id, err)
+			}
+		}
+		l.rules = append(l.rules, builtIn)
+	}
+	for id := range config.Rules {
+		if !isBuiltInRule(id) {
+			return nil, fmt.Errorf("Unknown built-in rule %v in lint configuration", id)
+		}
+	}
+	for _, custom := range config.Custom {
+		r, err := newCustomRule(custom)
+		if err != nil {
+			return nil, err
+		}
+		l.rules = append(l.rules, r)
+	}
+	for _, r := range l.rules {
+		switch r.severity {
+		case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return nil, fmt.Errorf("Invalid severity %v for rule %v", r.severity, r.id)
+		}
+	}
+	return l, nil
+}
+
+// Rules returns the active rules
+func (l *Linter) Rules() []*RuleInfo {
+	var rules []*RuleInfo
+	for _, r := range l.rules {
+		if r.severity != SeverityOff {
+			rules = append(rules, &RuleInfo{Id: r.id, Description: r.description, Severity: r.severity})
+		}
+	}
+	return rules
+}
+
+// LintDir lints the artifact in the directory, or when the directory does not contain
+// META-INF/MANIFEST.MF, each artifact in its subdirectories
+func (l *Linter) LintDir(dir string, includedIds []string, excludedIds []string) (*Report, error) {
+	report := &Report{Rules: l.Rules()}
+
+	var artifactDirs []string
+	if file.Exists(manifestPath(dir)) {
+		artifactDirs = append(artifactDirs, dir)
+	} else {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && file.Exists(manifestPath(filepath.Join(dir, entry.Name()))) {
+				artifactDirs = append(artifactDirs, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	for _, artifactDir := range artifactDirs {
+		a, err := loadArtifact(dir, artifactDir)
+		if err != nil {
+			return nil, err
+		}
+		if str.FilterIDs(a.id, includedIds, excludedIds) {
+			continue
+		}
+		log.Debug().Msgf("Linting artifact %v in %v", a.id, artifactDir)
+		report.Artifacts++
+		for _, r := range l.rules {
+			if r.severity == SeverityOff {
+				continue
+			}
+			for _, finding := range r.check(a, r) {
+				finding.RuleId = r.id
+				finding.Severity = r.severity
+				finding.ArtifactId = a.id
+				report.Findings = append(report.Findings, finding)
+			}
+		}
+	}
+	return report, nil
+}
+
+func loadArtifact(rootDir string, artifactDir string) (*artifact, error) {
+	headers, err := file.GetManifestHeaders(manifestPath(artifactDir))
+	if err != nil {
+		return nil, err
+	}
+	a := &artifact{
+		id:      file.GetArtifactId(headers),
+		dir:     artifactDir,
+		rootDir: rootDir,
+		headers: headers,
+		iflws:   map[string]*etree.Document{},
+	}
+
+	bpmnDir := filepath.Join(artifactDir, "src", "main", "resources", "scenarioflows", "integrationflow")
+	if file.Exists(bpmnDir) {
+		entries, err := os.ReadDir(bpmnDir)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".iflw") {
+				doc := etree.NewDocument()
+				if err = doc.ReadFromFile(filepath.Join(bpmnDir, entry.Name())); err != nil {
+					return nil, errors.Wrap(err, 0)
+				}
+				a.iflws[filepath.Join(bpmnDir, entry.Name())] = doc
+			}
+		}
+	}
+
+	propdefFile := filepath.Join(artifactDir, "src", "main", "resources", "parameters.propdef")
+	if file.Exists(propdefFile) {
+		a.propdefFile = propdefFile
+		a.propdef = etree.NewDocument()
+		if err = a.propdef.ReadFromFile(propdefFile); err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+	}
+
+	scriptDir := filepath.Join(artifactDir, "src", "main", "resources", "script")
+	if file.Exists(scriptDir) {
+		entries, err := os.ReadDir(scriptDir)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".groovy") || strings.HasSuffix(entry.Name(), ".gsh")) {
+				a.scriptFiles = append(a.scriptFiles, filepath.Join(scriptDir, entry.Name()))
+			}
+		}
+	}
+	return a, nil
+}
+
+func manifestPath(artifactDir string) string {
+	return filepath.Join(artifactDir, "META-INF", "MANIFEST.MF")
+}
+
+// iflwFiles returns the BPMN2 files of the artifact in a deterministic order
+func (a *artifact) iflwFiles() []string {
+	var files []string
+	for f := range a.iflws {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// relPath returns the path of the file relative to the linted directory, used in findings
+func (a *artifact) relPath(filePath string) string {
+	rel, err := filepath.Rel(a.rootDir, filePath)
+	if err != nil {
+		return filePath
+	}
+	return filepath.ToSlash(rel)
+}
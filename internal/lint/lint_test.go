@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countByRule(report *Report) map[string]int {
+	counts := map[string]int{}
+	for _, finding := range report.Findings {
+		counts[finding.RuleId]++
+	}
+	return counts
+}
+
+func TestLintDir_DefaultRules(t *testing.T) {
+	linter, err := New(&Config{})
+	if err != nil {
+		t.Fatalf("New failed with error - %v", err)
+	}
+	report, err := linter.LintDir("../../test/testdata/Lint", nil, nil)
+	if err != nil {
+		t.Fatalf("LintDir failed with error - %v", err)
+	}
+
+	counts := countByRule(report)
+	assert.Equal(t, 1, report.Artifacts, "Expected number of artifacts = 1")
+	assert.Equal(t, 1, counts[RuleArtifactNaming], "Expected artifact-naming finding")
+	assert.Equal(t, 1, counts[RuleExceptionSubprocess], "Expected exception-subprocess finding")
+	assert.Equal(t, 1, counts[RuleNoHardcodedHosts], "Expected no-hardcoded-hosts finding")
+	assert.Equal(t, 1, counts[RuleExternalizedAddresses], "Expected externalized-addresses finding")
+	assert.Equal(t, 1, counts[RuleGroovyHeader], "Expected groovy-header finding")
+	assert.Equal(t, 0, counts[RuleMaxSteps], "Expected no max-steps finding")
+	assert.Equal(t, 1, report.Count(SeverityError), "Expected number of errors = 1")
+	assert.Equal(t, "1_Lint_IFlow/src/main/resources/script/NoHeader.groovy", report.Findings[4].File, "Expected file relative to linted directory")
+}
+
+func TestLintDir_ConfiguredRules(t *testing.T) {
+	config, err := LoadConfig("../../test/testdata/Lint/lint-config.json")
+	if err != nil {
+		t.Fatalf("LoadConfig failed with error - %v", err)
+	}
+	linter, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed with error - %v", err)
+	}
+
+	report, err := linter.LintDir("../../test/testdata/Lint/1_Lint_IFlow", nil, nil)
+	if err != nil {
+		t.Fatalf("LintDir failed with error - %v", err)
+	}
+	counts := countByRule(report)
+	assert.Equal(t, 0, counts[RuleGroovyHeader], "Expected groovy-header to be switched off")
+	assert.Equal(t, 1, counts[RuleMaxSteps], "Expected max-steps finding")
+	assert.Equal(t, 0, counts["bundle-version-semver"], "Expected no bundle-version-semver finding")
+	assert.Equal(t, 1, counts["required-param-description"], "Expected required-param-description finding")
+	assert.Equal(t, 2, report.Count(SeverityError), "Expected number of errors = 2")
+
+	report, err = linter.LintDir("../../test/testdata/artifacts/update", []string{"Integration_Test_IFlow"}, nil)
+	if err != nil {
+		t.Fatalf("LintDir failed with error - %v", err)
+	}
+	counts = countByRule(report)
+	assert.Equal(t, 1, report.Artifacts, "Expected number of artifacts = 1")
+	assert.Equal(t, 1, counts["no-content-modifier"], "Expected no-content-modifier finding")
+	assert.Equal(t, 0, counts[RuleNoHardcodedHosts], "Expected no no-hardcoded-hosts finding")
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(&Config{Rules: map[string]*RuleConfig{"unknown": {Severity: SeverityError}}})
+	assert.Error(t, err, "Expected error for unknown rule")
+
+	_, err = New(&Config{Rules: map[string]*RuleConfig{RuleMaxSteps: {Severity: "fatal"}}})
+	assert.Error(t, err, "Expected error for invalid severity")
+
+	_, err = New(&Config{Custom: []*CustomRule{{Id: "custom", File: "iflw", Mode: "required"}}})
+	assert.Error(t, err, "Expected error for custom rule without xpath")
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	linter, err := New(&Config{})
+	if err != nil {
+		t.Fatalf("New failed with error - %v", err)
+	}
+	report, err := linter.LintDir("../../test/testdata/Lint", nil, nil)
+	if err != nil {
+		t.Fatalf("LintDir failed with error - %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = report.Write(&buf, "sarif", "test")
+	if err != nil {
+		t.Fatalf("Write failed with error - %v", err)
+	}
+	var sarif *sarifLog
+	err = json.Unmarshal(buf.Bytes(), &sarif)
+	if err != nil {
+		t.Fatalf("Unmarshal failed with error - %v", err)
+	}
+	assert.Equal(t, "2.1.0", sarif.Version, "Expected SARIF version = 2.1.0")
+	assert.Equal(t, len(report.Findings), len(sarif.Runs[0].Results), "Expected all findings in SARIF results")
+	assert.Equal(t, len(report.Rules), len(sarif.Runs[0].Tool.Driver.Rules), "Expected all rules in SARIF driver")
+}
@@ -0,0 +1,148 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/go-errors/errors"
+)
+
+type Report struct {
+	Artifacts int         `json:"artifacts"`
+	Rules     []*RuleInfo `json:"rules"`
+	Findings  []*Finding  `json:"findings"`
+}
+
+// Count returns the number of findings with the severity
+func (r *Report) Count(severity string) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Write outputs the report in the format (json or sarif)
+func (r *Report) Write(w io.Writer, format string, toolVersion string) error {
+	var content any
+	switch format {
+	case "json":
+		content = r
+	case "sarif":
+		content = r.toSARIF(toolVersion)
+	default:
+		return fmt.Errorf("invalid value for output format = %v", format)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(content); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// SARIF 2.1.0 structures, only the subset needed for reporting findings
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool      `json:"tool"`
+	Results []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version,omitempty"`
+	InformationUri string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleSettings `json:"defaultConfiguration"`
+}
+
+type sarifRuleSettings struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId    string           `json:"ruleId"`
+	Level     string           `json:"level"`
+	Message   sarifMessage     `json:"message"`
+	Locations []*sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation   `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+}
+
+func (r *Report) toSARIF(toolVersion string) *sarifLog {
+	run := &sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "flashpipe",
+			Version:        toolVersion,
+			InformationUri: "https://github.com/engswee/flashpipe",
+		}},
+		Results: []*sarifResult{},
+	}
+	for _, rule := range r.Rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, &sarifRule{
+			Id:                   rule.Id,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifRuleSettings{Level: sarifLevel(rule.Severity)},
+		})
+	}
+	for _, finding := range r.Findings {
+		location := &sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: finding.File}}}
+		if finding.Element != "" {
+			location.LogicalLocations = []*sarifLogicalLocation{{Name: finding.Element}}
+		}
+		run.Results = append(run.Results, &sarifResult{
+			RuleId:    finding.RuleId,
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{Text: fmt.Sprintf("[%v] %v", finding.ArtifactId, finding.Message)},
+			Locations: []*sarifLocation{location},
+		})
+	}
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []*sarifRun{run},
+	}
+}
+
+func sarifLevel(severity string) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+	return severity
+}
@@ -0,0 +1,332 @@
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+)
+
+const (
+	RuleArtifactNaming        = "artifact-naming"
+	RuleExceptionSubprocess   = "exception-subprocess"
+	RuleNoHardcodedHosts      = "no-hardcoded-hosts"
+	RuleExternalizedAddresses = "externalized-addresses"
+	RuleGroovyHeader          = "groovy-header"
+	RuleMaxSteps              = "max-steps"
+)
+
+var urlHostRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://([^/\s?#]+)`)
+
+func builtInRules() []*rule {
+	return []*rule{
+		{
+			id:          RuleArtifactNaming,
+			description: "Artifact ID (Bundle-SymbolicName) must follow the naming convention",
+			severity:    SeverityWarning,
+			config:      &RuleConfig{Pattern: `^[A-Za-z][A-Za-z0-9_.-]*$`},
+			check:       checkArtifactNaming,
+		},
+		{
+			id:          RuleExceptionSubprocess,
+			description: "Integration process must contain an exception subprocess",
+			severity:    SeverityWarning,
+			config:      &RuleConfig{},
+			check:       checkExceptionSubprocess,
+		},
+		{
+			id:          RuleNoHardcodedHosts,
+			description: "Hostnames in IFlow properties must be externalized",
+			severity:    SeverityError,
+			config:      &RuleConfig{Keys: []string{"namespaceMapping"}},
+			check:       checkNoHardcodedHosts,
+		},
+		{
+			id:          RuleExternalizedAddresses,
+			description: "Addresses of receiver adapters must be externalized",
+			severity:    SeverityWarning,
+			config:      &RuleConfig{Keys: []string{"address", "httpAddressWithoutQuery", "host", "url"}},
+			check:       checkExternalizedAddresses,
+		},
+		{
+			id:          RuleGroovyHeader,
+			description: "Groovy scripts must start with a header comment",
+			severity:    SeverityWarning,
+			config:      &RuleConfig{Pattern: `^(//|/\*)`},
+			check:       checkGroovyHeader,
+		},
+		{
+			id:          RuleMaxSteps,
+			description: "Number of steps in a process must not exceed the maximum",
+			severity:    SeverityWarning,
+			config:      &RuleConfig{Max: 25},
+			check:       checkMaxSteps,
+		},
+	}
+}
+
+func isBuiltInRule(id string) bool {
+	for _, r := range builtInRules() {
+		if r.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func checkArtifactNaming(a *artifact, r *rule) []*Finding {
+	if !regexp.MustCompile(r.config.Pattern).MatchString(a.id) {
+		return []*Finding{{
+			File:    a.relPath(manifestPath(a.dir)),
+			Message: fmt.Sprintf("Artifact ID %v does not match pattern %v", a.id, r.config.Pattern),
+		}}
+	}
+	return nil
+}
+
+func checkExceptionSubprocess(a *artifact, r *rule) []*Finding {
+	var findings []*Finding
+	for _, iflwFile := range a.iflwFiles() {
+		doc := a.iflws[iflwFile]
+		for _, participant := range doc.FindElements("//bpmn2:participant[@ifl:type='IntegrationProcess']") {
+			processId := participant.SelectAttrValue("processRef", "")
+			process := doc.FindElement(fmt.Sprintf("//bpmn2:process[@id='%v']", processId))
+			// Local integration processes are called from the main process and do not need their own
+			if process == nil || file.GetIFlowProperties(process)["processType"] == "directCall" {
+				continue
+			}
+			found := false
+			for _, subProcess := range process.FindElements(".//bpmn2:subProcess") {
+				if file.GetIFlowProperties(subProcess)["activityType"] == "ErrorEventSubProcessTemplate" {
+					found = true
+					break
+				}
+			}
+			if !found {
+				findings = append(findings, &Finding{
+					File:    a.relPath(iflwFile),
+					Element: processId,
+					Message: fmt.Sprintf("Process %v does not contain an exception subprocess", process.SelectAttrValue("name", processId)),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func checkNoHardcodedHosts(a *artifact, r *rule) []*Finding {
+	var findings []*Finding
+	for _, iflwFile := range a.iflwFiles() {
+		for _, property := range a.iflws[iflwFile].FindElements("//ifl:property") {
+			key := property.SelectElement("key")
+			value := property.SelectElement("value")
+			if key == nil || value == nil || slices.Contains(r.config.Keys, key.Text()) {
+				continue
+			}
+			for _, match := range urlHostRegex.FindAllStringSubmatch(value.Text(), -1) {
+				if !isExternalized(match[1]) {
+					findings = append(findings, &Finding{
+						File:    a.relPath(iflwFile),
+						Element: getElementId(property),
+						Message: fmt.Sprintf("Property %v contains hard-coded host %v", key.Text(), match[1]),
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkExternalizedAddresses(a *artifact, r *rule) []*Finding {
+	var findings []*Finding
+	for _, iflwFile := range a.iflwFiles() {
+		for _, messageFlow := range a.iflws[iflwFile].FindElements("//bpmn2:messageFlow") {
+			properties := file.GetIFlowProperties(messageFlow)
+			if properties["direction"] != "Receiver" {
+				continue
+			}
+			for _, key := range r.config.Keys {
+				value := properties[key]
+				if value != "" && !isExternalized(value) {
+					findings = append(findings, &Finding{
+						File:    a.relPath(iflwFile),
+						Element: messageFlow.SelectAttrValue("id", ""),
+						Message: fmt.Sprintf("Receiver adapter %v has %v that is not externalized: %v", messageFlow.SelectAttrValue("name", ""), key, value),
+					})
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkGroovyHeader(a *artifact, r *rule) []*Finding {
+	var findings []*Finding
+	pattern := regexp.MustCompile(r.config.Pattern)
+	for _, scriptFile := range a.scriptFiles {
+		firstLine, err := readFirstLine(scriptFile)
+		if err != nil || !pattern.MatchString(firstLine) {
+			findings = append(findings, &Finding{
+				File:    a.relPath(scriptFile),
+				Message: "Script does not start with a header comment",
+			})
+		}
+	}
+	return findings
+}
+
+func checkMaxSteps(a *artifact, r *rule) []*Finding {
+	var findings []*Finding
+	for _, iflwFile := range a.iflwFiles() {
+		doc := a.iflws[iflwFile]
+		processes := append(doc.FindElements("//bpmn2:process"), doc.FindElements("//bpmn2:subProcess")...)
+		for _, process := range processes {
+			steps := 0
+			for _, child := range process.ChildElements() {
+				switch child.Tag {
+				case "extensionElements", "sequenceFlow", "startEvent", "endEvent":
+				default:
+					steps++
+				}
+			}
+			if steps > r.config.Max {
+				findings = append(findings, &Finding{
+					File:    a.relPath(iflwFile),
+					Element: process.SelectAttrValue("id", ""),
+					Message: fmt.Sprintf("%v has %d steps, maximum allowed is %d", process.SelectAttrValue("name", process.Tag), steps, r.config.Max),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func newCustomRule(custom *CustomRule) (*rule, error) {
+	if custom.Id == "" {
+		return nil, fmt.Errorf("Custom lint rule must have an id")
+	}
+	if isBuiltInRule(custom.Id) {
+		return nil, fmt.Errorf("Custom lint rule %v has the same id as a built-in rule", custom.Id)
+	}
+	switch custom.Mode {
+	case "required", "forbidden":
+	default:
+		return nil, fmt.Errorf("Invalid mode %v for custom lint rule %v. Allowed values: required, forbidden", custom.Mode, custom.Id)
+	}
+	var pattern *regexp.Regexp
+	if custom.Pattern != "" {
+		var err error
+		pattern, err = regexp.Compile(custom.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid pattern for custom lint rule %v: %w", custom.Id, err)
+		}
+	}
+	severity := custom.Severity
+	if severity == "" {
+		severity = SeverityWarning
+	}
+
+	r := &rule{id: custom.Id, description: custom.Description, severity: severity, config: &RuleConfig{Pattern: custom.Pattern}}
+	switch custom.File {
+	case "iflw", "propdef":
+		if custom.XPath == "" {
+			return nil, fmt.Errorf("Custom lint rule %v must have xpath", custom.Id)
+		}
+		path, err := etree.CompilePath(custom.XPath)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid xpath for custom lint rule %v: %w", custom.Id, err)
+		}
+		r.check = func(a *artifact, r *rule) []*Finding {
+			var files []string
+			docs := a.iflws
+			if custom.File == "iflw" {
+				files = a.iflwFiles()
+			} else if a.propdef != nil {
+				files = []string{a.propdefFile}
+				docs = map[string]*etree.Document{a.propdefFile: a.propdef}
+			}
+			var findings []*Finding
+			for _, filePath := range files {
+				var matches []*etree.Element
+				for _, element := range docs[filePath].FindElementsPath(path) {
+					if pattern == nil || pattern.MatchString(strings.TrimSpace(element.Text())) {
+						matches = append(matches, element)
+					}
+				}
+				findings = append(findings, customFindings(custom, a.relPath(filePath), len(matches), func(i int) string {
+					return getElementId(matches[i])
+				})...)
+			}
+			return findings
+		}
+	case "manifest":
+		if custom.Header == "" {
+			return nil, fmt.Errorf("Custom lint rule %v must have header", custom.Id)
+		}
+		r.check = func(a *artifact, r *rule) []*Finding {
+			matches := 0
+			values := a.headers.Values(custom.Header)
+			if len(values) > 0 && (pattern == nil || pattern.MatchString(strings.Join(values, ""))) {
+				matches = 1
+			}
+			return customFindings(custom, a.relPath(manifestPath(a.dir)), matches, func(int) string {
+				return custom.Header
+			})
+		}
+	default:
+		return nil, fmt.Errorf("Invalid file %v for custom lint rule %v. Allowed values: iflw, manifest, propdef", custom.File, custom.Id)
+	}
+	return r, nil
+}
+
+func customFindings(custom *CustomRule, filePath string, matches int, element func(i int) string) []*Finding {
+	message := custom.Description
+	if message == "" {
+		message = fmt.Sprintf("Custom rule %v violated", custom.Id)
+	}
+	var findings []*Finding
+	if custom.Mode == "required" && matches == 0 {
+		findings = append(findings, &Finding{File: filePath, Message: message})
+	}
+	if custom.Mode == "forbidden" {
+		for i := 0; i < matches; i++ {
+			findings = append(findings, &Finding{File: filePath, Element: element(i), Message: message})
+		}
+	}
+	return findings
+}
+
+// getElementId returns the ID of the element or its nearest ancestor with an ID
+func getElementId(element *etree.Element) string {
+	for e := element; e != nil; e = e.Parent() {
+		if id := e.SelectAttrValue("id", ""); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func isExternalized(value string) bool {
+	return strings.Contains(value, "{{") || strings.Contains(value, "${")
+}
+
+func readFirstLine(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line != "" {
+			return line, nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Methods are the HTTP methods of an OpenAPI path item that are mapped to an APIResource, in the order used in the
+// generated content
+var Methods = []string{"get", "post", "put", "delete", "head", "options", "patch"}
+
+var nameRegex = regexp.MustCompile(`[^A-Za-z0-9_\-.]+`)
+
+var pathParamRegex = regexp.MustCompile(`{[^/{}]+}`)
+
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// Spec contains the parts of an OpenAPI 3 specification that are used to generate an APIProxy
+type Spec struct {
+	OpenAPI string                    `yaml:"openapi"`
+	Info    Info                      `yaml:"info"`
+	Servers []*Server                 `yaml:"servers"`
+	Paths   map[string]map[string]any `yaml:"paths"`
+}
+
+// Resource is an API resource of the APIProxy for a path of the specification
+type Resource struct {
+	Name    string
+	Path    string
+	Methods []string
+}
+
+// Load reads an OpenAPI 3 specification in YAML or JSON format
+func Load(specFile string) (*Spec, error) {
+	content, err := os.ReadFile(specFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	// JSON is a subset of YAML, so both formats are parsed by the YAML decoder
+	spec := &Spec{}
+	err = yaml.Unmarshal(content, spec)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing OpenAPI specification %v: %w", specFile, err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("OpenAPI specification %v has version %q - only OpenAPI 3 is supported", specFile, spec.OpenAPI)
+	}
+	if len(spec.Paths) == 0 {
+		return nil, fmt.Errorf("OpenAPI specification %v does not have any paths", specFile)
+	}
+	if _, err = spec.Resources(); err != nil {
+		return nil, fmt.Errorf("OpenAPI specification %v: %w", specFile, err)
+	}
+	return spec, nil
+}
+
+// ProxyName returns a name for the APIProxy derived from the title of the specification
+func (s *Spec) ProxyName() string {
+	return strings.Trim(nameRegex.ReplaceAllString(s.Info.Title, "_"), "_")
+}
+
+// ServerPath returns the path of the first server URL, which is used as the relative path of the API provider
+func (s *Spec) ServerPath() (string, error) {
+	if len(s.Servers) == 0 {
+		return "", nil
+	}
+	serverURL, err := url.Parse(s.Servers[0].URL)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return strings.TrimSuffix(serverURL.Path, "/"), nil
+}
+
+// Resources returns an API resource for each path with operations, sorted by name. An error is returned if there
+// are no operations, or if different paths have the same resource name
+func (s *Spec) Resources() ([]*Resource, error) {
+	var resources []*Resource
+	paths := map[string]string{}
+	for path, item := range s.Paths {
+		resource := &Resource{Name: resourceName(path), Path: path}
+		for _, method := range Methods {
+			if _, ok := item[method]; ok {
+				resource.Methods = append(resource.Methods, method)
+			}
+		}
+		if len(resource.Methods) == 0 {
+			continue
+		}
+		if otherPath, ok := paths[resource.Name]; ok {
+			first, second := min(path, otherPath), max(path, otherPath)
+			return nil, fmt.Errorf("Paths %v and %v have the same API resource name %v", first, second, resource.Name)
+		}
+		paths[resource.Name] = path
+		resources = append(resources, resource)
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("No operations found in paths - supported methods are %v", strings.Join(Methods, ", "))
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+	return resources, nil
+}
+
+// resourceName derives the name of the API resource (also used as its file name) from the path, e.g. /orders/{orderId}
+// becomes orders_orderId
+func resourceName(path string) string {
+	name := strings.Trim(nameRegex.ReplaceAllString(path, "_"), "_")
+	if name == "" {
+		return "root"
+	}
+	return name
+}
+
+// Condition returns the condition of the conditional flow that matches the requests of the resource
+func (r *Resource) Condition() string {
+	// Path parameters match any value of a path segment
+	path := pathParamRegex.ReplaceAllString(r.Path, "*")
+	var verbs []string
+	for _, method := range r.Methods {
+		verbs = append(verbs, fmt.Sprintf("request.verb = %q", strings.ToUpper(method)))
+	}
+	return fmt.Sprintf(`(proxy.pathsuffix MatchesPath %q OR proxy.pathsuffix MatchesPath "%v/**" OR proxy.pathsuffix MatchesPath "%v(**")AND(%v)`,
+		path, strings.TrimSuffix(path, "/"), path, strings.Join(verbs, " OR "))
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	spec, err := Load("../../test/testdata/openapi/orders.yaml")
+	if err != nil {
+		t.Fatalf("Load failed with error - %v", err)
+	}
+	assert.Equal(t, "Orders_API", spec.ProxyName(), "Unexpected proxy name")
+	serverPath, err := spec.ServerPath()
+	if err != nil {
+		t.Fatalf("ServerPath failed with error - %v", err)
+	}
+	assert.Equal(t, "/api/v1", serverPath, "Unexpected server path")
+
+	resources, err := spec.Resources()
+	if err != nil {
+		t.Fatalf("Resources failed with error - %v", err)
+	}
+	assert.Equal(t, 2, len(resources), "Expected number of resources = 2")
+	assert.Equal(t, "orders", resources[0].Name, "Unexpected resource name")
+	assert.Equal(t, []string{"get", "post"}, resources[0].Methods, "Unexpected methods")
+	assert.Equal(t, "orders_orderId", resources[1].Name, "Unexpected resource name")
+	assert.Equal(t, []string{"get", "delete"}, resources[1].Methods, "Expected parameters of path item to be skipped")
+	assert.Equal(t, `(proxy.pathsuffix MatchesPath "/orders/*" OR proxy.pathsuffix MatchesPath "/orders/*/**" OR proxy.pathsuffix MatchesPath "/orders/*(**")AND(request.verb = "GET" OR request.verb = "DELETE")`, resources[1].Condition(), "Unexpected condition")
+}
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, "root", resourceName("/"), "Unexpected name of root path")
+	assert.Equal(t, "orders", resourceName("/orders"), "Unexpected resource name")
+	assert.Equal(t, "orders_orderId_items", resourceName("/orders/{orderId}/items"), "Expected braces of path parameter removed")
+	assert.Equal(t, "orders_orderId", resourceName("/orders/{orderId}/"), "Expected trailing separator removed")
+}
+
+func TestResources_DuplicateName(t *testing.T) {
+	spec := &Spec{Paths: map[string]map[string]any{
+		"/orders/{id}": {"get": nil},
+		"/orders/id":   {"get": nil},
+	}}
+
+	_, err := spec.Resources()
+	assert.EqualError(t, err, "Paths /orders/id and /orders/{id} have the same API resource name orders_id")
+}
+
+func TestLoad_NoOperations(t *testing.T) {
+	specFile := t.TempDir() + "/orders.yaml"
+	err := os.WriteFile(specFile, []byte("openapi: 3.0.3\npaths:\n  /orders:\n    trace: {}\n    parameters: []\n"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+	_, err = Load(specFile)
+	assert.ErrorContains(t, err, "No operations found in paths")
+}
+
+func TestLoad_NotOpenAPI3(t *testing.T) {
+	specFile := t.TempDir() + "/swagger.yaml"
+	err := os.WriteFile(specFile, []byte("swagger: \"2.0\"\npaths:\n  /orders:\n    get: {}\n"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+	_, err = Load(specFile)
+	assert.ErrorContains(t, err, "only OpenAPI 3 is supported")
+}
+
+func TestGenerateAPIProxy_CreateAndUpdate(t *testing.T) {
+	spec, err := Load("../../test/testdata/openapi/orders.yaml")
+	if err != nil {
+		t.Fatalf("Load failed with error - %v", err)
+	}
+	artifactDir := t.TempDir() + "/Orders_API"
+	options := ProxyOptions{Name: "Orders_API", Provider: "OrdersBackend", BasePath: "/v1/orders"}
+	err = GenerateAPIProxy(spec, options, artifactDir)
+	if err != nil {
+		t.Fatalf("GenerateAPIProxy failed with error - %v", err)
+	}
+
+	proxyDir := artifactDir + "/APIProxies/Orders_API"
+	assert.True(t, file.Exists(artifactDir+"/manifest.json"), "Expected manifest.json to be created")
+	proxy := readDocument(t, proxyDir+"/Orders_API.xml")
+	assert.Equal(t, "Orders API", proxy.FindElement("/APIProxy/title").Text(), "Unexpected title")
+	assert.Equal(t, "Manage sales orders", proxy.FindElement("/APIProxy/description").Text(), "Unexpected description")
+	assert.Equal(t, 2, len(proxy.FindElements("//apiResourceName")), "Expected number of resources = 2")
+	proxyEndpoint := readDocument(t, proxyDir+"/APIProxyEndpoint/default.xml")
+	assert.Equal(t, "/v1/orders", proxyEndpoint.FindElement("//base_path").Text(), "Unexpected base path")
+	assert.Equal(t, 2, len(proxyEndpoint.FindElements("//conditionalFlow")), "Expected number of conditional flows = 2")
+	targetEndpoint := readDocument(t, proxyDir+"/APITargetEndpoint/default.xml")
+	assert.Equal(t, "OrdersBackend", targetEndpoint.FindElement("//provider_id").Text(), "Unexpected provider")
+	assert.Equal(t, "/api/v1", targetEndpoint.FindElement("//relativePath").Text(), "Unexpected relative path")
+	resource := readDocument(t, proxyDir+"/APIResource/orders_orderId.xml")
+	assert.Equal(t, "true", resource.FindElement("//isDeleteChecked").Text(), "Expected DELETE to be allowed")
+	assert.Equal(t, "false", resource.FindElement("//isPostChecked").Text(), "Expected POST to not be allowed")
+	assert.Equal(t, "/orders/{orderId}", resource.FindElement("//resource_path").Text(), "Unexpected resource path")
+
+	// Add a policy to a resource flow and a custom flow, as would be done in the API portal and synced to Git
+	flow := proxyEndpoint.FindElement("//conditionalFlow[name='orders']")
+	flow.CreateElement("request").CreateElement("steps").CreateElement("step").CreateElement("policy_name").SetText("verifyAPIKey")
+	customFlow := proxyEndpoint.FindElement("//conditionalFlows").CreateElement("conditionalFlow")
+	customFlow.CreateElement("name").SetText("Custom")
+	customFlow.CreateElement("readOnly").SetText("false")
+	err = proxyEndpoint.WriteToFile(proxyDir + "/APIProxyEndpoint/default.xml")
+	if err != nil {
+		t.Fatalf("WriteToFile failed with error - %v", err)
+	}
+	err = os.MkdirAll(proxyDir+"/Policy", os.ModePerm)
+	if err != nil {
+		t.Fatalf("MkdirAll failed with error - %v", err)
+	}
+	err = os.WriteFile(proxyDir+"/Policy/verifyAPIKey.xml", []byte("<VerifyAPIKey/>"), 0644)
+	if err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+
+	// Re-import a changed specification
+	spec, err = Load("../../test/testdata/openapi/orders-v2.json")
+	if err != nil {
+		t.Fatalf("Load failed with error - %v", err)
+	}
+	err = GenerateAPIProxy(spec, options, artifactDir)
+	if err != nil {
+		t.Fatalf("GenerateAPIProxy failed with error - %v", err)
+	}
+
+	assert.True(t, file.Exists(proxyDir+"/Policy/verifyAPIKey.xml"), "Expected policy to be kept")
+	assert.False(t, file.Exists(proxyDir+"/APIResource/orders_orderId.xml"), "Expected removed resource to be deleted")
+	assert.True(t, file.Exists(proxyDir+"/APIResource/orders_orderId_items.xml"), "Expected new resource to be added")
+	proxy = readDocument(t, proxyDir+"/Orders_API.xml")
+	var resourceNames []string
+	for _, element := range proxy.FindElements("//apiResourceName") {
+		resourceNames = append(resourceNames, element.Text())
+	}
+	assert.Equal(t, []string{"orders", "orders_orderId_items"}, resourceNames, "Unexpected resources")
+	proxyEndpoint = readDocument(t, proxyDir+"/APIProxyEndpoint/default.xml")
+	var flowNames []string
+	for _, element := range proxyEndpoint.FindElements("//conditionalFlow/name") {
+		flowNames = append(flowNames, element.Text())
+	}
+	assert.Equal(t, []string{"orders", "orders_orderId_items", "Custom"}, flowNames, "Unexpected conditional flows")
+	flow = proxyEndpoint.FindElement("//conditionalFlow[name='orders']")
+	assert.Equal(t, "verifyAPIKey", flow.FindElement("request/steps/step/policy_name").Text(), "Expected policy step of resource flow to be kept")
+	assert.True(t, strings.HasSuffix(flow.SelectElement("conditions").Text(), `AND(request.verb = "GET")`), "Expected condition to be updated")
+	assert.Equal(t, "3", proxyEndpoint.FindElement("//conditionalFlow[name='Custom']/sequence").Text(), "Unexpected sequence of custom flow")
+	targetEndpoint = readDocument(t, proxyDir+"/APITargetEndpoint/default.xml")
+	assert.Equal(t, "/api/v2", targetEndpoint.FindElement("//relativePath").Text(), "Unexpected relative path")
+}
+
+func readDocument(t *testing.T, filePath string) *etree.Document {
+	doc := etree.NewDocument()
+	err := doc.ReadFromFile(filePath)
+	if err != nil {
+		t.Fatalf("ReadFromFile failed with error - %v", err)
+	}
+	return doc
+}
@@ -0,0 +1,286 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const apimNamespace = "http://www.sap.com/apimgmt"
+
+// methodTags are the suffixes of the APIResource elements for the allowed methods (e.g. canShowGet, isGetChecked)
+var methodTags = map[string]string{
+	"get": "Get", "post": "Post", "put": "Put", "delete": "Delete", "head": "Head", "options": "Option", "patch": "Patch",
+}
+
+// ProxyOptions contains the values of the generated APIProxy that are not part of the OpenAPI specification
+type ProxyOptions struct {
+	Name     string
+	Provider string
+	BasePath string
+}
+
+// GenerateAPIProxy creates or updates the content of an APIProxy in artifactDir (the directory containing manifest.json)
+// from the OpenAPI specification. When the APIProxy already exists, only the resources, their conditional flows and the
+// endpoint details are updated. Policies, custom conditional flows and other content are kept.
+func GenerateAPIProxy(spec *Spec, options ProxyOptions, artifactDir string) error {
+	proxyDir := filepath.Join(artifactDir, "APIProxies", options.Name)
+	if file.Exists(proxyDir) {
+		log.Info().Msgf("Updating existing APIProxy content in %v", proxyDir)
+	} else {
+		log.Info().Msgf("Generating APIProxy content in %v", proxyDir)
+	}
+	relativePath, err := spec.ServerPath()
+	if err != nil {
+		return err
+	}
+	resources, err := spec.Resources()
+	if err != nil {
+		return err
+	}
+
+	manifestFile := filepath.Join(artifactDir, "manifest.json")
+	if !file.Exists(manifestFile) {
+		err = os.MkdirAll(artifactDir, os.ModePerm)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		err = os.WriteFile(manifestFile, []byte("{\n    \"contentType\": \"ContentArchive\",\n    \"modelVersion\": \"1.0\"\n}"), 0644)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	err = updateXMLFile(filepath.Join(proxyDir, options.Name+".xml"), "APIProxy", func(root *etree.Element, created bool) {
+		updateProxy(root, created, spec, options.Name, resources)
+	})
+	if err != nil {
+		return err
+	}
+	err = updateXMLFile(filepath.Join(proxyDir, "APIProxyEndpoint", "default.xml"), "ProxyEndPoint", func(root *etree.Element, created bool) {
+		updateProxyEndpoint(root, created, options.BasePath, resources)
+	})
+	if err != nil {
+		return err
+	}
+	err = updateXMLFile(filepath.Join(proxyDir, "APITargetEndpoint", "default.xml"), "TargetEndPoint", func(root *etree.Element, created bool) {
+		updateTargetEndpoint(root, created, options.Provider, relativePath)
+	})
+	if err != nil {
+		return err
+	}
+	return updateResources(filepath.Join(proxyDir, "APIResource"), resources)
+}
+
+// updateXMLFile reads the XML file (or creates a new document if it does not exist), updates it and writes it back
+func updateXMLFile(filePath string, rootTag string, update func(root *etree.Element, created bool)) error {
+	doc := etree.NewDocument()
+	doc.WriteSettings.CanonicalText = true
+	created := !file.Exists(filePath)
+	if created {
+		doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8" standalone="yes"`)
+		doc.CreateElement(rootTag).CreateAttr("xmlns", apimNamespace)
+	} else {
+		err := doc.ReadFromFile(filePath)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if doc.Root() == nil || doc.Root().Tag != rootTag {
+			return fmt.Errorf("File %v does not contain %v", filePath, rootTag)
+		}
+	}
+	update(doc.Root(), created)
+	doc.Indent(4)
+
+	err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	err = doc.WriteToFile(filePath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+func updateProxy(root *etree.Element, created bool, spec *Spec, name string, resources []*Resource) {
+	if created {
+		root.CreateElement("name").SetText(name)
+		root.CreateElement("title")
+		root.CreateElement("isVersioned").SetText("false")
+		root.CreateElement("service_code").SetText("REST")
+		root.CreateElement("APIState").SetText("Active")
+		root.CreateElement("proxyEndPoints").CreateElement("proxyEndPoint").CreateElement("proxyEndPointName").SetText("default")
+		root.CreateElement("targetEndPoints").CreateElement("targetEndPoint").SetText("default")
+		root.CreateElement("policies")
+		root.CreateElement("fileResources")
+	}
+	title := childElement(root, "title")
+	title.SetText(spec.Info.Title)
+	if description := root.SelectElement("description"); description != nil {
+		description.SetText(spec.Info.Description)
+	} else if spec.Info.Description != "" {
+		root.InsertChildAt(title.Index()+1, etree.NewElement("description"))
+		root.SelectElement("description").SetText(spec.Info.Description)
+	}
+
+	proxyEndpoint := root.FindElement("proxyEndPoints/proxyEndPoint[proxyEndPointName='default']")
+	if proxyEndpoint == nil {
+		proxyEndpoint = childElement(root, "proxyEndPoints").CreateElement("proxyEndPoint")
+		proxyEndpoint.CreateElement("proxyEndPointName").SetText("default")
+	}
+	for _, resourceName := range proxyEndpoint.SelectElements("apiResourceName") {
+		proxyEndpoint.RemoveChild(resourceName)
+	}
+	for _, resource := range resources {
+		proxyEndpoint.CreateElement("apiResourceName").SetText(resource.Name)
+	}
+}
+
+func updateProxyEndpoint(root *etree.Element, created bool, basePath string, resources []*Resource) {
+	if created {
+		root.CreateAttr("default", "true")
+		root.CreateElement("name").SetText("default")
+		root.CreateElement("base_path")
+		root.CreateElement("properties")
+		routeRule := root.CreateElement("routeRules").CreateElement("routeRule")
+		routeRule.CreateElement("name").SetText("default")
+		routeRule.CreateElement("targetEndPointName").SetText("default")
+		routeRule.CreateElement("sequence").SetText("1")
+		routeRule.CreateElement("faultRules")
+		root.CreateElement("faultRules")
+		root.CreateElement("preFlow").CreateElement("name").SetText("PreFlow")
+		root.CreateElement("postFlow").CreateElement("name").SetText("PostFlow")
+		root.CreateElement("conditionalFlows")
+	}
+	childElement(root, "base_path").SetText(basePath)
+
+	// Conditional flows of resources are read only. Other conditional flows are custom flows that are kept as is.
+	conditionalFlows := childElement(root, "conditionalFlows")
+	resourceFlows := map[string]*etree.Element{}
+	var customFlows []*etree.Element
+	for _, flow := range conditionalFlows.SelectElements("conditionalFlow") {
+		conditionalFlows.RemoveChild(flow)
+		name, readOnly := flow.SelectElement("name"), flow.SelectElement("readOnly")
+		if name != nil && readOnly != nil && readOnly.Text() == "true" {
+			resourceFlows[name.Text()] = flow
+		} else {
+			customFlows = append(customFlows, flow)
+		}
+	}
+
+	sequence := 0
+	for _, resource := range resources {
+		flow, exists := resourceFlows[resource.Name]
+		if exists {
+			// Policies attached to the flow of an existing resource are kept
+			delete(resourceFlows, resource.Name)
+		} else {
+			flow = etree.NewElement("conditionalFlow")
+			flow.CreateElement("name").SetText(resource.Name)
+		}
+		childElement(flow, "conditions").SetText(resource.Condition())
+		childElement(flow, "readOnly").SetText("true")
+		sequence++
+		childElement(flow, "sequence").SetText(fmt.Sprint(sequence))
+		conditionalFlows.AddChild(flow)
+	}
+	for _, flow := range customFlows {
+		sequence++
+		childElement(flow, "sequence").SetText(fmt.Sprint(sequence))
+		conditionalFlows.AddChild(flow)
+	}
+	for name, flow := range resourceFlows {
+		if flow.SelectElement("request") != nil || flow.SelectElement("response") != nil {
+			log.Warn().Msgf("⚠️ Conditional flow %v with policies is removed as its path is no longer in the OpenAPI specification", name)
+		}
+	}
+}
+
+func updateTargetEndpoint(root *etree.Element, created bool, provider string, relativePath string) {
+	if created {
+		root.CreateElement("name").SetText("default")
+		root.CreateElement("provider_id")
+		root.CreateElement("additionalAPIProviders")
+		root.CreateElement("isDefault").SetText("true")
+		root.CreateElement("relativePath")
+		root.CreateElement("properties")
+		root.CreateElement("faultRules")
+		root.CreateElement("preFlow").CreateElement("name").SetText("PreFlow")
+		root.CreateElement("postFlow").CreateElement("name").SetText("PostFlow")
+		root.CreateElement("conditionalFlows")
+	}
+	childElement(root, "provider_id").SetText(provider)
+	childElement(root, "relativePath").SetText(relativePath)
+}
+
+// updateResources writes a file for each resource and removes the files of resources that are no longer in the specification
+func updateResources(resourceDir string, resources []*Resource) error {
+	names := map[string]bool{}
+	for _, resource := range resources {
+		names[resource.Name] = true
+		err := updateXMLFile(filepath.Join(resourceDir, resource.Name+".xml"), "APIResource", func(root *etree.Element, created bool) {
+			updateResource(root, created, resource)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	entries, err := os.ReadDir(resourceDir)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		name, isXML := strings.CutSuffix(entry.Name(), ".xml")
+		if entry.IsDir() || !isXML || names[name] {
+			continue
+		}
+		log.Info().Msgf("Removing resource %v as its path is no longer in the OpenAPI specification", name)
+		err = os.Remove(filepath.Join(resourceDir, entry.Name()))
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+func updateResource(root *etree.Element, created bool, resource *Resource) {
+	if created {
+		root.CreateElement("name").SetText(resource.Name)
+		root.CreateElement("title").SetText(resource.Name)
+		for _, prefix := range []string{"canShow%v", "is%vChecked"} {
+			for _, method := range Methods {
+				root.CreateElement(fmt.Sprintf(prefix, methodTags[method]))
+			}
+		}
+		root.CreateElement("resource_path")
+		root.CreateElement("proxyEndPointName").SetText("default")
+		root.CreateElement("documentations")
+	}
+	allowed := map[string]bool{}
+	for _, method := range resource.Methods {
+		allowed[method] = true
+	}
+	for _, method := range Methods {
+		value := fmt.Sprint(allowed[method])
+		childElement(root, fmt.Sprintf("canShow%v", methodTags[method])).SetText(value)
+		childElement(root, fmt.Sprintf("is%vChecked", methodTags[method])).SetText(value)
+	}
+	childElement(root, "resource_path").SetText(resource.Path)
+}
+
+// childElement returns the child element with the tag, which is created if it does not exist
+func childElement(parent *etree.Element, tag string) *etree.Element {
+	child := parent.SelectElement(tag)
+	if child == nil {
+		child = parent.CreateElement(tag)
+	}
+	return child
+}
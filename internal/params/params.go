@@ -0,0 +1,217 @@
+package params
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/beevik/etree"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/go-errors/errors"
+	"github.com/magiconair/properties"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+
+	UnknownKey           = "unknown-key"
+	MissingRequired      = "missing-required"
+	TypeMismatch         = "type-mismatch"
+	UnusedDefinition     = "unused-definition"
+	UndefinedPlaceholder = "undefined-placeholder"
+)
+
+var placeholderRegex = regexp.MustCompile(`{{([^{}]+)}}`)
+
+type Definition struct {
+	Name       string
+	Type       string
+	IsRequired bool
+}
+
+type Finding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+}
+
+type Report struct {
+	ArtifactDir string     `json:"artifactDir"`
+	Findings    []*Finding `json:"findings"`
+}
+
+func (r *Report) Count(severity string) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// Log outputs the findings of the report
+func (r *Report) Log() {
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			log.Error().Msgf("[%v] %v", finding.Check, finding.Message)
+		} else {
+			log.Warn().Msgf("[%v] %v", finding.Check, finding.Message)
+		}
+	}
+}
+
+// Check cross-checks the values in the parameters file against the definitions in
+// parameters.propdef and the placeholders used in the IFlow BPMN2 files of the artifact
+func Check(artifactDir string, parametersFile string) (*Report, error) {
+	report := &Report{ArtifactDir: artifactDir}
+
+	definitions, err := GetDefinitions(filepath.Join(artifactDir, "src", "main", "resources", "parameters.propdef"))
+	if err != nil {
+		return nil, err
+	}
+	placeholders, err := GetPlaceholders(artifactDir)
+	if err != nil {
+		return nil, err
+	}
+	// Without a parameters file, values are not checked as the values configured in the tenant are kept
+	valuesExist := file.Exists(parametersFile)
+	values := map[string]string{}
+	if valuesExist {
+		p, err := properties.LoadFile(parametersFile, properties.UTF8)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		values = p.Map()
+	}
+
+	// Without parameters.propdef, the placeholders are the only definitions available. As parameters can also be
+	// used outside the placeholders (e.g. in scripts), keys that are not placeholders are only reported as warnings
+	unknownKeySeverity := SeverityError
+	if definitions == nil {
+		unknownKeySeverity = SeverityWarning
+		definitions = map[string]*Definition{}
+		for name := range placeholders {
+			definitions[name] = &Definition{Name: name}
+		}
+	}
+
+	add := func(check string, severity string, key string, format string, a ...any) {
+		report.Findings = append(report.Findings, &Finding{Check: check, Severity: severity, Key: key, Message: fmt.Sprintf(format, a...)})
+	}
+
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+		definition := definitions[key]
+		if definition == nil {
+			add(UnknownKey, unknownKeySeverity, key, "Parameter %v in %v is not defined in the artifact", key, filepath.Base(parametersFile))
+			continue
+		}
+		if err := checkType(definition.Type, value); err != nil {
+			add(TypeMismatch, SeverityError, key, "Value %v of parameter %v does not match type %v", value, key, definition.Type)
+		}
+	}
+	for _, name := range sortedKeys(definitions) {
+		definition := definitions[name]
+		if valuesExist && definition.IsRequired && values[name] == "" {
+			add(MissingRequired, SeverityError, name, "Required parameter %v does not have a value in %v", name, filepath.Base(parametersFile))
+		}
+		if !placeholders[name] {
+			add(UnusedDefinition, SeverityWarning, name, "Parameter %v is defined but not used in the IFlow", name)
+		}
+	}
+	for _, name := range sortedKeys(placeholders) {
+		if definitions[name] == nil {
+			add(UndefinedPlaceholder, SeverityWarning, name, "Placeholder {{%v}} in the IFlow is not defined in parameters.propdef", name)
+		}
+	}
+	return report, nil
+}
+
+// GetDefinitions returns the parameter definitions in parameters.propdef, or nil if the file does not exist
+func GetDefinitions(propdefFile string) (map[string]*Definition, error) {
+	if !file.Exists(propdefFile) {
+		return nil, nil
+	}
+	doc := etree.NewDocument()
+	if err := doc.ReadFromFile(propdefFile); err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	definitions := map[string]*Definition{}
+	for _, parameter := range doc.FindElements("//parameters/parameter") {
+		name := parameter.SelectElement("name")
+		if name == nil || name.Text() == "" {
+			continue
+		}
+		definition := &Definition{Name: name.Text()}
+		if t := parameter.SelectElement("type"); t != nil {
+			definition.Type = strings.TrimSpace(t.Text())
+		}
+		if isRequired := parameter.SelectElement("isRequired"); isRequired != nil {
+			definition.IsRequired = strings.TrimSpace(isRequired.Text()) == "true"
+		}
+		definitions[definition.Name] = definition
+	}
+	return definitions, nil
+}
+
+// GetPlaceholders returns the names of the {{placeholders}} used in the IFlow BPMN2 files
+func GetPlaceholders(artifactDir string) (map[string]bool, error) {
+	placeholders := map[string]bool{}
+	bpmnDir := filepath.Join(artifactDir, "src", "main", "resources", "scenarioflows", "integrationflow")
+	if !file.Exists(bpmnDir) {
+		return placeholders, nil
+	}
+	entries, err := os.ReadDir(bpmnDir)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".iflw") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(bpmnDir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		for _, match := range placeholderRegex.FindAllStringSubmatch(string(content), -1) {
+			placeholders[match[1]] = true
+		}
+	}
+	return placeholders, nil
+}
+
+func checkType(dataType string, value string) error {
+	// Empty values and dynamic expressions are resolved at runtime
+	if value == "" || strings.Contains(value, "${") {
+		return nil
+	}
+	var err error
+	switch dataType {
+	case "xsd:integer", "xsd:int", "xsd:long", "xsd:short":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "xsd:decimal", "xsd:double", "xsd:float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "xsd:boolean":
+		if value != "true" && value != "false" {
+			err = fmt.Errorf("invalid boolean %v", value)
+		}
+	}
+	return err
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package params
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func findingsByCheck(report *Report) map[string][]string {
+	keys := map[string][]string{}
+	for _, finding := range report.Findings {
+		keys[finding.Check] = append(keys[finding.Check], finding.Key)
+	}
+	return keys
+}
+
+func TestCheck(t *testing.T) {
+	artifactDir := "../../test/testdata/Params/Param_IFlow"
+	report, err := Check(artifactDir, artifactDir+"/src/main/resources/parameters.prop")
+	if err != nil {
+		t.Fatalf("Check failed with error - %v", err)
+	}
+
+	findings := findingsByCheck(report)
+	assert.Equal(t, []string{"Recevier Address"}, findings[UnknownKey], "Expected unknown key with typo")
+	assert.Equal(t, []string{"Receiver Address"}, findings[MissingRequired], "Expected missing required parameter")
+	assert.Equal(t, []string{"Timeout"}, findings[TypeMismatch], "Expected type mismatch for integer parameter")
+	assert.Equal(t, []string{"Legacy Queue"}, findings[UnusedDefinition], "Expected unused definition")
+	assert.Equal(t, []string{"Proxy Host"}, findings[UndefinedPlaceholder], "Expected undefined placeholder")
+	assert.Equal(t, 3, report.Count(SeverityError), "Expected number of errors = 3")
+}
+
+func TestCheck_WithoutParametersFile(t *testing.T) {
+	report, err := Check("../../test/testdata/Params/Param_IFlow", "non-existent.prop")
+	if err != nil {
+		t.Fatalf("Check failed with error - %v", err)
+	}
+
+	assert.Equal(t, 0, report.Count(SeverityError), "Expected no errors when values are not provided")
+}
+
+func TestCheck_ConsistentParameters(t *testing.T) {
+	artifactDir := "../../test/testdata/artifacts/update/Integration_Test_IFlow"
+	report, err := Check(artifactDir, artifactDir+"/src/main/resources/parameters.prop")
+	if err != nil {
+		t.Fatalf("Check failed with error - %v", err)
+	}
+
+	assert.Equal(t, 0, len(report.Findings), "Expected no findings")
+}
+
+func TestCheckType(t *testing.T) {
+	assert.NoError(t, checkType("xsd:integer", "42"), "Expected valid integer")
+	assert.Error(t, checkType("xsd:integer", "4.2"), "Expected invalid integer")
+	assert.NoError(t, checkType("xsd:boolean", "false"), "Expected valid boolean")
+	assert.Error(t, checkType("xsd:boolean", "yes"), "Expected invalid boolean")
+	assert.NoError(t, checkType("xsd:integer", "${property.timeout}"), "Expected dynamic expression to be skipped")
+	assert.NoError(t, checkType("xsd:string", "any"), "Expected any string")
+}
+
+func TestCheck_WithoutPropdef(t *testing.T) {
+	artifactDir := t.TempDir()
+	bpmnDir := artifactDir + "/src/main/resources/scenarioflows/integrationflow"
+	_ = os.MkdirAll(bpmnDir, os.ModePerm)
+	_ = os.WriteFile(bpmnDir+"/Orders.iflw", []byte(`<value>https://{{ReceiverHost}}/orders</value>`), 0644)
+	parametersFile := artifactDir + "/src/main/resources/parameters.prop"
+	_ = os.WriteFile(parametersFile, []byte("ReceiverHost=example.com\nScriptTimeout=30\n"), 0644)
+
+	report, err := Check(artifactDir, parametersFile)
+	if err != nil {
+		t.Fatalf("Check failed with error - %v", err)
+	}
+
+	assert.Equal(t, []string{"ScriptTimeout"}, findingsByCheck(report)[UnknownKey], "Expected key that is not a placeholder")
+	assert.Equal(t, 0, report.Count(SeverityError), "Expected no errors without parameters.propdef")
+}
@@ -1,10 +1,16 @@
 package repo
 
 import (
+	"fmt"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/rs/zerolog/log"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -70,3 +76,150 @@ func CommitToRepo(gitRepoDir string, commitMsg string, commitUser string, commit
 	}
 	return
 }
+
+// CommitFiles commits only the given files, leaving other changes in the working tree uncommitted
+func CommitFiles(gitRepoDir string, files []string, commitMsg string, commitUser string, commitEmail string) (err error) {
+	log.Info().Msgf("Opening Git repository at %v", gitRepoDir)
+	repo, err := git.PlainOpen(gitRepoDir)
+	if err != nil {
+		return
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return
+	}
+
+	repoDir, err := filepath.Abs(gitRepoDir)
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		var absPath, relPath string
+		absPath, err = filepath.Abs(f)
+		if err != nil {
+			return
+		}
+		relPath, err = filepath.Rel(repoDir, absPath)
+		if err != nil {
+			return
+		}
+		log.Info().Msgf("Adding %v for Git tracking", relPath)
+		_, err = w.Add(filepath.ToSlash(relPath))
+		if err != nil {
+			return
+		}
+	}
+
+	log.Info().Msg("Trying to commit changes")
+	commit, err := w.Commit(commitMsg, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  commitUser,
+			Email: commitEmail,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	obj, err := repo.CommitObject(commit)
+	if err != nil {
+		return
+	}
+
+	log.Info().Msgf("Commit object:\n%v", obj)
+	log.Info().Msg("🏆 Changes committed")
+	return
+}
+
+// ResolveRef returns the commit of the Git reference, which can be a commit hash, a branch, a tag, a revision
+// (e.g. HEAD~1) or a date (YYYY-MM-DD or RFC 3339). For a date, the last commit of HEAD on or before the date is returned.
+func ResolveRef(repo *git.Repository, ref string) (*object.Commit, error) {
+	until, isDate := parseRefDate(ref)
+	if isDate {
+		commits, err := repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime, Until: &until})
+		if err != nil {
+			return nil, err
+		}
+		defer commits.Close()
+		commit, err := commits.Next()
+		if err != nil {
+			return nil, fmt.Errorf("No commit found on or before %v", ref)
+		}
+		return commit, nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("Git reference %v cannot be resolved: %w", ref, err)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// parseRefDate returns the latest time included by the date of the Git reference
+func parseRefDate(ref string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, ref); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, ref, time.Local); err == nil {
+		// Include all commits of the day
+		return t.Add(24*time.Hour - time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// ExtractDir writes the files in the directory of the Git repository (relative to the repository root) as of the Git
+// reference to the target directory, without changing the working tree. It returns the resolved commit.
+func ExtractDir(gitRepoDir string, ref string, dir string, targetDir string) (commit *object.Commit, err error) {
+	log.Info().Msgf("Opening Git repository at %v", gitRepoDir)
+	repo, err := git.PlainOpen(gitRepoDir)
+	if err != nil {
+		return
+	}
+
+	commit, err = ResolveRef(repo, ref)
+	if err != nil {
+		return
+	}
+	log.Info().Msgf("Git reference %v resolved to commit %v (%v)", ref, commit.Hash, commit.Committer.When.Format(time.RFC3339))
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return
+	}
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if dir != "." {
+		tree, err = tree.Tree(dir)
+		if err != nil {
+			return nil, fmt.Errorf("Directory %v does not exist in commit %v: %w", dir, commit.Hash, err)
+		}
+	}
+
+	log.Info().Msgf("Extracting directory %v of commit %v to %v", dir, commit.Hash, targetDir)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		// Skip symlinks and submodules
+		if f.Mode != filemode.Regular && f.Mode != filemode.Executable {
+			return nil
+		}
+		if !fs.ValidPath(f.Name) {
+			return fmt.Errorf("invalid file path: %s", f.Name)
+		}
+		reader, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		filePath := filepath.Join(targetDir, filepath.FromSlash(f.Name))
+		err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filePath, data, 0644)
+	})
+	return
+}
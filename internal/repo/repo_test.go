@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func commitFile(t *testing.T, repo *git.Repository, gitRepoDir string, name string, content string, when time.Time) plumbing.Hash {
+	filePath := filepath.Join(gitRepoDir, name)
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		t.Fatalf("MkdirAll failed with error - %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed with error - %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree failed with error - %v", err)
+	}
+	if _, err = w.Add(name); err != nil {
+		t.Fatalf("Add failed with error - %v", err)
+	}
+	signature := &object.Signature{Name: "dummy", Email: "dummy@example.com", When: when}
+	hash, err := w.Commit("Update "+name, &git.CommitOptions{Author: signature, Committer: signature})
+	if err != nil {
+		t.Fatalf("Commit failed with error - %v", err)
+	}
+	return hash
+}
+
+func setupRepo(t *testing.T) (string, *git.Repository, []plumbing.Hash) {
+	gitRepoDir := t.TempDir()
+	repo, err := git.PlainInit(gitRepoDir, false)
+	if err != nil {
+		t.Fatalf("PlainInit failed with error - %v", err)
+	}
+	first := commitFile(t, repo, gitRepoDir, "packages/DummyPackage/DummyIFlow/META-INF/MANIFEST.MF", "Bundle-Version: 1.0.0\n", time.Date(2024, 5, 14, 10, 0, 0, 0, time.Local))
+	if _, err = repo.CreateTag("v1", first, nil); err != nil {
+		t.Fatalf("CreateTag failed with error - %v", err)
+	}
+	second := commitFile(t, repo, gitRepoDir, "packages/DummyPackage/DummyIFlow/META-INF/MANIFEST.MF", "Bundle-Version: 1.0.1\n", time.Date(2024, 5, 16, 10, 0, 0, 0, time.Local))
+	return gitRepoDir, repo, []plumbing.Hash{first, second}
+}
+
+func TestResolveRef(t *testing.T) {
+	_, repo, hashes := setupRepo(t)
+
+	refs := map[string]plumbing.Hash{
+		hashes[0].String():     hashes[0],
+		hashes[0].String()[:7]: hashes[0],
+		"v1":                   hashes[0],
+		"HEAD":                 hashes[1],
+		"HEAD~1":               hashes[0],
+		"2024-05-14":           hashes[0],
+		"2024-05-15":           hashes[0],
+		"2024-05-16":           hashes[1],
+	}
+	for ref, expected := range refs {
+		commit, err := ResolveRef(repo, ref)
+		if assert.NoError(t, err, "Expected %v to be resolved", ref) {
+			assert.Equal(t, expected, commit.Hash, "Unexpected commit for %v", ref)
+		}
+	}
+
+	_, err := ResolveRef(repo, "2024-05-13")
+	assert.Error(t, err, "Expected no commit before first commit")
+	_, err = ResolveRef(repo, "dummy")
+	assert.Error(t, err, "Expected unknown reference not resolved")
+}
+
+func TestExtractDir(t *testing.T) {
+	gitRepoDir, _, hashes := setupRepo(t)
+	targetDir := t.TempDir()
+
+	commit, err := ExtractDir(gitRepoDir, "v1", "packages", targetDir)
+	if err != nil {
+		t.Fatalf("ExtractDir failed with error - %v", err)
+	}
+	assert.Equal(t, hashes[0], commit.Hash, "Unexpected commit extracted")
+
+	extracted, err := os.ReadFile(filepath.Join(targetDir, "DummyPackage/DummyIFlow/META-INF/MANIFEST.MF"))
+	if assert.NoError(t, err, "Expected MANIFEST.MF extracted") {
+		assert.Equal(t, "Bundle-Version: 1.0.0\n", string(extracted), "Unexpected content of extracted file")
+	}
+	current, _ := os.ReadFile(filepath.Join(gitRepoDir, "packages/DummyPackage/DummyIFlow/META-INF/MANIFEST.MF"))
+	assert.Equal(t, "Bundle-Version: 1.0.1\n", string(current), "Expected working tree unchanged")
+
+	_, err = ExtractDir(gitRepoDir, "v1", "dummy", t.TempDir())
+	assert.Error(t, err, "Expected error for directory not in commit")
+}
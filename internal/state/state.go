@@ -0,0 +1,141 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/go-errors/errors"
+)
+
+// DefaultFile is the location of the state file relative to the Git repository
+const DefaultFile = ".flashpipe/state.json"
+
+// Artifact is the metadata of an artifact at the time it was last synced to Git or uploaded to the tenant
+type Artifact struct {
+	Id         string `json:"id"`
+	Type       string `json:"type"`
+	Version    string `json:"version,omitempty"`
+	ModifiedAt string `json:"modifiedAt,omitempty"`
+	// Content hash of the artifact directory in Git after the last sync to Git
+	GitContentHash string `json:"gitContentHash,omitempty"`
+	// Content hash and version in the tenant after the last upload to the tenant
+	UploadContentHash string `json:"uploadContentHash,omitempty"`
+	UploadVersion     string `json:"uploadVersion,omitempty"`
+}
+
+// State records the metadata of artifacts synced between Git and the tenant so that unchanged artifacts can be skipped
+// in later runs
+type State struct {
+	Packages map[string]map[string]*Artifact `json:"packages"`
+	file     string
+}
+
+// Load reads the state file. If the file does not exist, an empty state is returned.
+func Load(stateFile string) (*State, error) {
+	s := &State{Packages: map[string]map[string]*Artifact{}, file: stateFile}
+	if !file.Exists(stateFile) {
+		return s, nil
+	}
+	content, err := os.ReadFile(stateFile)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	err = json.Unmarshal(content, s)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	if s.Packages == nil {
+		s.Packages = map[string]map[string]*Artifact{}
+	}
+	return s, nil
+}
+
+// Changed returns true if the metadata of the artifact differs from the state, or if it cannot be determined
+func (s *State) Changed(packageId string, artifact *api.ArtifactDetails) bool {
+	previous := s.Packages[packageId][artifact.Id]
+	if previous == nil || previous.Type != artifact.ArtifactType || previous.Version != artifact.Version {
+		return true
+	}
+	if previous.ModifiedAt != "" && artifact.ModifiedAt != "" {
+		return previous.ModifiedAt != artifact.ModifiedAt
+	}
+	// Draft versions do not change, so changes cannot be detected without the modification date
+	return artifact.IsDraft
+}
+
+// Update records the metadata of the artifact synced to Git and the content hash of its directory in Git
+func (s *State) Update(packageId string, artifact *api.ArtifactDetails, gitContentHash string) {
+	previous := s.get(packageId, artifact.Id, artifact.ArtifactType)
+	previous.Version = artifact.Version
+	previous.ModifiedAt = artifact.ModifiedAt
+	previous.GitContentHash = gitContentHash
+}
+
+// UpdateUpload records the content hash and version of the artifact uploaded to the tenant
+func (s *State) UpdateUpload(packageId string, artifactId string, artifactType string, contentHash string, version string) {
+	previous := s.get(packageId, artifactId, artifactType)
+	previous.UploadContentHash = contentHash
+	previous.UploadVersion = version
+}
+
+// GitContentHash returns the content hash of the artifact directory in Git after the last sync to Git, or an empty
+// string if it is not recorded
+func (s *State) GitContentHash(packageId string, artifactId string) string {
+	if previous := s.Packages[packageId][artifactId]; previous != nil {
+		return previous.GitContentHash
+	}
+	return ""
+}
+
+// Uploaded returns true if the artifact with the content hash was uploaded to the tenant, and the tenant still has the
+// version after the upload
+func (s *State) Uploaded(packageId string, artifactId string, contentHash string, version string) bool {
+	previous := s.Packages[packageId][artifactId]
+	return previous != nil && previous.UploadContentHash == contentHash && previous.UploadVersion == version
+}
+
+// get returns the recorded artifact, which is added if it does not exist yet or has a different type
+func (s *State) get(packageId string, artifactId string, artifactType string) *Artifact {
+	if s.Packages[packageId] == nil {
+		s.Packages[packageId] = map[string]*Artifact{}
+	}
+	previous := s.Packages[packageId][artifactId]
+	if previous == nil || previous.Type != artifactType {
+		previous = &Artifact{Id: artifactId, Type: artifactType}
+		s.Packages[packageId][artifactId] = previous
+	}
+	return previous
+}
+
+// Prune removes the artifacts of the package that are not in the list of artifacts of the tenant
+func (s *State) Prune(packageId string, artifacts []*api.ArtifactDetails) {
+	existing := map[string]bool{}
+	for _, artifact := range artifacts {
+		existing[artifact.Id] = true
+	}
+	for id := range s.Packages[packageId] {
+		if !existing[id] {
+			delete(s.Packages[packageId], id)
+		}
+	}
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	err := os.MkdirAll(filepath.Dir(s.file), os.ModePerm)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	err = os.WriteFile(s.file, content, 0644)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChanged(t *testing.T) {
+	s, err := Load(t.TempDir() + "/state.json")
+	if err != nil {
+		t.Fatalf("Load failed with error - %v", err)
+	}
+	artifact := &api.ArtifactDetails{Id: "DummyIFlow", ArtifactType: "Integration", Version: "1.0.0", ModifiedAt: "1700000000000"}
+	assert.True(t, s.Changed("DummyPackage", artifact), "Expected new artifact changed")
+
+	s.Update("DummyPackage", artifact, "")
+	assert.False(t, s.Changed("DummyPackage", artifact), "Expected same artifact unchanged")
+	assert.True(t, s.Changed("DummyPackage", &api.ArtifactDetails{Id: "DummyIFlow", ArtifactType: "Integration", Version: "1.0.1", ModifiedAt: "1700000000000"}), "Expected new version changed")
+	assert.True(t, s.Changed("DummyPackage", &api.ArtifactDetails{Id: "DummyIFlow", ArtifactType: "Integration", Version: "1.0.0", ModifiedAt: "1710000000000"}), "Expected new modification date changed")
+	assert.True(t, s.Changed("OtherPackage", artifact), "Expected artifact in other package changed")
+}
+
+func TestChangedDraft(t *testing.T) {
+	s, _ := Load(t.TempDir() + "/state.json")
+	draft := &api.ArtifactDetails{Id: "DummyIFlow", ArtifactType: "Integration", Version: "Active", IsDraft: true}
+	s.Update("DummyPackage", draft, "")
+
+	assert.True(t, s.Changed("DummyPackage", draft), "Expected draft without modification date changed")
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	stateFile := t.TempDir() + "/.flashpipe/state.json"
+	s, _ := Load(stateFile)
+	artifacts := []*api.ArtifactDetails{
+		{Id: "DummyIFlow", ArtifactType: "Integration", Version: "1.0.0"},
+		{Id: "DummyScript", ArtifactType: "ScriptCollection", Version: "1.0.2"},
+	}
+	for _, artifact := range artifacts {
+		s.Update("DummyPackage", artifact, "")
+	}
+	s.Prune("DummyPackage", artifacts[1:])
+	err := s.Save()
+	if err != nil {
+		t.Fatalf("Save failed with error - %v", err)
+	}
+
+	loaded, err := Load(stateFile)
+	if err != nil {
+		t.Fatalf("Load failed with error - %v", err)
+	}
+	assert.Equal(t, 1, len(loaded.Packages["DummyPackage"]), "Expected pruned artifact removed")
+	assert.Equal(t, "1.0.2", loaded.Packages["DummyPackage"]["DummyScript"].Version, "Expected version of DummyScript = 1.0.2")
+}
+
+func TestContentHashes(t *testing.T) {
+	s, _ := Load(t.TempDir() + "/state.json")
+	artifact := &api.ArtifactDetails{Id: "DummyIFlow", ArtifactType: "Integration", Version: "1.0.0", ModifiedAt: "1700000000000"}
+	s.Update("DummyPackage", artifact, "githash")
+	s.UpdateUpload("DummyPackage", "DummyIFlow", "Integration", "uploadhash", "1.0.1")
+
+	assert.Equal(t, "githash", s.GitContentHash("DummyPackage", "DummyIFlow"), "Expected content hash of Git directory")
+	assert.Equal(t, "", s.GitContentHash("DummyPackage", "OtherIFlow"), "Expected no content hash for unknown artifact")
+	assert.True(t, s.Uploaded("DummyPackage", "DummyIFlow", "uploadhash", "1.0.1"), "Expected same content and version uploaded")
+	assert.False(t, s.Uploaded("DummyPackage", "DummyIFlow", "uploadhash", "1.0.2"), "Expected different version in tenant not uploaded")
+	assert.False(t, s.Uploaded("DummyPackage", "DummyIFlow", "otherhash", "1.0.1"), "Expected different content not uploaded")
+	assert.False(t, s.Changed("DummyPackage", artifact), "Expected upload to keep metadata of sync to Git")
+}
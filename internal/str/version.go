@@ -0,0 +1,71 @@
+package str
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BumpVersion increments a version in the format major.minor.micro[.qualifier] based on the bump type.
+// Allowed values: patch, minor, major, timestamp. The timestamp bump replaces the qualifier with the current
+// UTC time so that the version increases even when the numeric parts are maintained manually.
+func BumpVersion(version string, bumpType string, now time.Time) (string, error) {
+	major, minor, micro, _, err := parseVersion(version)
+	if err != nil {
+		return "", err
+	}
+	switch bumpType {
+	case "patch":
+		micro++
+	case "minor":
+		minor++
+		micro = 0
+	case "major":
+		major++
+		minor = 0
+		micro = 0
+	case "timestamp":
+		return fmt.Sprintf("%d.%d.%d.%v", major, minor, micro, now.UTC().Format("20060102150405")), nil
+	default:
+		return "", fmt.Errorf("Invalid version bump type %v", bumpType)
+	}
+	return fmt.Sprintf("%d.%d.%d", major, minor, micro), nil
+}
+
+// CompareVersions returns -1, 0 or 1 if the first version is lower, equal or higher than the second version.
+// Versions that cannot be parsed are compared as strings.
+func CompareVersions(first string, second string) int {
+	firstMajor, firstMinor, firstMicro, firstQualifier, err1 := parseVersion(first)
+	secondMajor, secondMinor, secondMicro, secondQualifier, err2 := parseVersion(second)
+	if err1 != nil || err2 != nil {
+		return strings.Compare(first, second)
+	}
+	for _, diff := range []int{firstMajor - secondMajor, firstMinor - secondMinor, firstMicro - secondMicro} {
+		if diff < 0 {
+			return -1
+		} else if diff > 0 {
+			return 1
+		}
+	}
+	return strings.Compare(firstQualifier, secondQualifier)
+}
+
+func parseVersion(version string) (major int, minor int, micro int, qualifier string, err error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return 1, 0, 0, "", nil
+	}
+	segments := strings.SplitN(version, ".", 4)
+	numbers := make([]int, 3)
+	for i := 0; i < len(segments) && i < 3; i++ {
+		numbers[i], err = strconv.Atoi(segments[i])
+		if err != nil {
+			return 0, 0, 0, "", fmt.Errorf("Version %v is not in the format major.minor.micro[.qualifier]", version)
+		}
+	}
+	if len(segments) == 4 {
+		qualifier = segments[3]
+	}
+	return numbers[0], numbers[1], numbers[2], qualifier, nil
+}
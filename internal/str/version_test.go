@@ -0,0 +1,48 @@
+package str
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBumpVersion(t *testing.T) {
+	now := time.Date(2024, 5, 17, 8, 30, 15, 0, time.UTC)
+	tests := []struct {
+		version  string
+		bumpType string
+		expected string
+	}{
+		{"1.0.3", "patch", "1.0.4"},
+		{"1.2.3", "minor", "1.3.0"},
+		{"1.2.3", "major", "2.0.0"},
+		{"1.0", "patch", "1.0.1"},
+		{"1.0.3.20240101000000", "patch", "1.0.4"},
+		{"1.0.3", "timestamp", "1.0.3.20240517083015"},
+		{"", "patch", "1.0.1"},
+	}
+	for _, test := range tests {
+		bumped, err := BumpVersion(test.version, test.bumpType, now)
+		if err != nil {
+			t.Fatalf("BumpVersion failed with error - %v", err)
+		}
+		assert.Equal(t, test.expected, bumped, "Incorrect bumped version for %v with %v", test.version, test.bumpType)
+	}
+}
+
+func TestBumpVersionInvalid(t *testing.T) {
+	_, err := BumpVersion("1.x.0", "patch", time.Now())
+	assert.Error(t, err, "Expected error for invalid version")
+
+	_, err = BumpVersion("1.0.0", "build", time.Now())
+	assert.Equal(t, "Invalid version bump type build", err.Error(), "Incorrect error message")
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareVersions("1.0.0", "1.0.0"), "Expected equal versions")
+	assert.Equal(t, -1, CompareVersions("1.0.9", "1.0.10"), "Expected numeric comparison")
+	assert.Equal(t, 1, CompareVersions("2.0.0", "1.9.9"), "Expected higher major version")
+	assert.Equal(t, 1, CompareVersions("1.0.0.20240517083015", "1.0.0.20240101000000"), "Expected higher qualifier")
+	assert.Equal(t, 0, CompareVersions("1.0", "1.0.0"), "Expected missing micro treated as 0")
+}
@@ -0,0 +1,259 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/engswee/flashpipe/internal/str"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// apimEntityClient is implemented by the API Management entities that are stored as JSON files in Git
+type apimEntityClient[T any] interface {
+	List() ([]*T, error)
+	Get(id string) (*T, error)
+	Create(entity *T) error
+	Update(entity *T) error
+}
+
+// apimEntity contains the details to synchronise an API Management entity type. Each entity is stored in
+// file <id>.json in a subdirectory of the artifacts directory
+type apimEntity[T any] struct {
+	entityType string
+	dirName    string
+	client     apimEntityClient[T]
+	getId      func(entity *T) string
+	// Optional function to complete the entity before it is created or updated in the tenant
+	prepareUpload func(entity *T, request Request) error
+	// Optional function that returns true if the entity is updated in the tenant even if no changes are found,
+	// e.g. as the values in the tenant cannot be read for comparison
+	alwaysUpdate func(entity *T) bool
+}
+
+func newAPIProductEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.APIProductData] {
+	return apimEntity[api.APIProductData]{
+		entityType: "APIProduct",
+		dirName:    "APIProducts",
+		client:     api.NewAPIProduct(exe),
+		getId:      func(product *api.APIProductData) string { return product.Name },
+	}
+}
+
+func newApplicationEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.ApplicationData] {
+	return apimEntity[api.ApplicationData]{
+		entityType: "Application",
+		dirName:    "Applications",
+		client:     api.NewApplication(exe),
+		getId:      func(application *api.ApplicationData) string { return application.Id },
+	}
+}
+
+func newAPIProviderEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.APIProviderData] {
+	return apimEntity[api.APIProviderData]{
+		entityType: "APIProvider",
+		dirName:    "APIProviders",
+		client:     api.NewAPIProvider(exe),
+		getId:      func(provider *api.APIProviderData) string { return provider.Name },
+	}
+}
+
+func newKeyValueMapEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.KeyValueMapData] {
+	return apimEntity[api.KeyValueMapData]{
+		entityType: "KeyValueMap",
+		dirName:    "KeyValueMaps",
+		client:     api.NewKeyValueMap(exe),
+		getId:      func(kvm *api.KeyValueMapData) string { return kvm.Name },
+		prepareUpload: func(kvm *api.KeyValueMapData, request Request) error {
+			return resolveKeyValueMapSecrets(kvm, request.SecretsFile)
+		},
+		alwaysUpdate: func(kvm *api.KeyValueMapData) bool { return kvm.Encrypted },
+	}
+}
+
+func newRatePlanEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.RatePlanData] {
+	return apimEntity[api.RatePlanData]{
+		entityType: "RatePlan",
+		dirName:    "RatePlans",
+		client:     api.NewRatePlan(exe),
+		getId:      func(ratePlan *api.RatePlanData) string { return ratePlan.Name },
+	}
+}
+
+func newDeveloperEntity(exe *httpclnt.HTTPExecuter) apimEntity[api.DeveloperData] {
+	return apimEntity[api.DeveloperData]{
+		entityType: "Developer",
+		dirName:    "Developers",
+		client:     api.NewDeveloper(exe),
+		getId:      func(developer *api.DeveloperData) string { return developer.Email },
+	}
+}
+
+type APIMEntityGitSynchroniser[T any] struct {
+	apimEntity[T]
+}
+
+type APIMEntityTenantSynchroniser[T any] struct {
+	apimEntity[T]
+}
+
+func (s *APIMEntityGitSynchroniser[T]) Exec(request Request) error {
+	log.Info().Msgf("Sync %vs to Git", s.entityType)
+
+	entities, err := s.client.List()
+	if err != nil {
+		return err
+	}
+
+	gitDir := fmt.Sprintf("%v/%v", request.ArtifactsDir, s.dirName)
+	if len(entities) > 0 {
+		err = os.MkdirAll(gitDir, os.ModePerm)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	for _, entity := range entities {
+		id := s.getId(entity)
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("📢 Begin processing for %v %v", s.entityType, id)
+
+		// Filter in/out artifacts
+		if str.FilterIDs(id, request.IncludedIds, request.ExcludedIds) {
+			continue
+		}
+
+		content, err := json.MarshalIndent(entity, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		gitFile := fmt.Sprintf("%v/%v.json", gitDir, id)
+		if file.Exists(gitFile) {
+			log.Info().Msg("Comparing content from tenant against Git")
+			gitContent, err := os.ReadFile(gitFile)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+			if bytes.Equal(gitContent, content) {
+				log.Info().Msg("🏆 No changes detected. Update to Git not required")
+				continue
+			}
+			log.Info().Msg("🏆 Changes detected and will be updated to Git")
+		} else {
+			log.Info().Msgf("🏆 %v %v does not exist, and will be added to Git", s.entityType, id)
+		}
+		err = os.WriteFile(gitFile, content, 0644)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msgf("🏆 Completed processing of %vs", s.entityType)
+	return nil
+}
+
+func (s *APIMEntityTenantSynchroniser[T]) Exec(request Request) error {
+	gitDir := fmt.Sprintf("%v/%v", filepath.Clean(request.ArtifactsDir), s.dirName)
+	if !file.Exists(gitDir) {
+		log.Warn().Msgf("No directory with %v contents found in %v", s.entityType, gitDir)
+		return nil
+	}
+	entries, err := os.ReadDir(gitDir)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		gitFile := fmt.Sprintf("%v/%v", gitDir, entry.Name())
+		content, err := os.ReadFile(gitFile)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		entity := new(T)
+		err = json.Unmarshal(content, entity)
+		if err != nil {
+			return fmt.Errorf("Error parsing %v file %v: %w", s.entityType, gitFile, err)
+		}
+		id := s.getId(entity)
+
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("Processing file %v", gitFile)
+
+		// Filter in/out artifacts
+		if str.FilterIDs(id, request.IncludedIds, request.ExcludedIds) {
+			continue
+		}
+
+		log.Info().Msgf("📢 Begin processing for %v %v", s.entityType, id)
+		tenantEntity, err := s.client.Get(id)
+		if err != nil {
+			return err
+		}
+		// Changes are compared before the entity is prepared, as the prepared content (e.g. secrets) is not available from the tenant
+		upload := func(upsert func(entity *T) error) error {
+			if s.prepareUpload != nil {
+				err := s.prepareUpload(entity, request)
+				if err != nil {
+					return err
+				}
+			}
+			return upsert(entity)
+		}
+		if tenantEntity == nil {
+			log.Info().Msgf("%v %v will be created", s.entityType, id)
+			err = upload(s.client.Create)
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("🏆 %v created successfully", s.entityType)
+			continue
+		}
+
+		log.Info().Msg("Comparing content from tenant against Git")
+		differ, err := entitiesDiffer(tenantEntity, entity)
+		if err != nil {
+			return err
+		}
+		if differ {
+			log.Info().Msgf("Changes found in %v. %v will be updated in tenant", s.entityType, s.entityType)
+		} else if s.alwaysUpdate != nil && s.alwaysUpdate(entity) {
+			log.Info().Msgf("Values of %v cannot be compared. %v will be updated in tenant", s.entityType, s.entityType)
+			differ = true
+		}
+		if differ {
+			err = upload(s.client.Update)
+			if err != nil {
+				return err
+			}
+			log.Info().Msgf("🏆 %v updated successfully", s.entityType)
+		} else {
+			log.Info().Msgf("🏆 No changes detected. %v does not need to be updated", s.entityType)
+		}
+	}
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msgf("🏆 Completed processing of %vs", s.entityType)
+	return nil
+}
+
+func entitiesDiffer(first any, second any) (bool, error) {
+	firstContent, err := json.Marshal(first)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	secondContent, err := json.Marshal(second)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	return !bytes.Equal(firstContent, secondContent), nil
+}
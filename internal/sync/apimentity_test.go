@@ -0,0 +1,133 @@
+package sync
+
+import (
+	"os"
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDeveloperClient struct {
+	developers map[string]*api.DeveloperData
+	created    []string
+	updated    []string
+}
+
+func (c *fakeDeveloperClient) List() ([]*api.DeveloperData, error) {
+	var developers []*api.DeveloperData
+	for _, developer := range c.developers {
+		developers = append(developers, developer)
+	}
+	return developers, nil
+}
+
+func (c *fakeDeveloperClient) Get(id string) (*api.DeveloperData, error) {
+	return c.developers[id], nil
+}
+
+func (c *fakeDeveloperClient) Create(developer *api.DeveloperData) error {
+	c.created = append(c.created, developer.Email)
+	return nil
+}
+
+func (c *fakeDeveloperClient) Update(developer *api.DeveloperData) error {
+	c.updated = append(c.updated, developer.Email)
+	return nil
+}
+
+func newFakeDeveloperEntity(client *fakeDeveloperClient) apimEntity[api.DeveloperData] {
+	return apimEntity[api.DeveloperData]{
+		entityType: "Developer",
+		dirName:    "Developers",
+		client:     client,
+		getId:      func(developer *api.DeveloperData) string { return developer.Email },
+	}
+}
+
+func TestAPIMEntitySyncToGit(t *testing.T) {
+	artifactsDir := t.TempDir()
+	client := &fakeDeveloperClient{developers: map[string]*api.DeveloperData{
+		"alice@example.com": {Email: "alice@example.com", FirstName: "Alice", LastName: "Smith"},
+		"bob@example.com":   {Email: "bob@example.com", FirstName: "Bob", LastName: "Jones"},
+	}}
+	syncer := &APIMEntityGitSynchroniser[api.DeveloperData]{newFakeDeveloperEntity(client)}
+
+	err := syncer.Exec(Request{ArtifactsDir: artifactsDir, ExcludedIds: []string{"bob@example.com"}})
+	if err != nil {
+		t.Fatalf("Exec failed with error - %v", err)
+	}
+	content, err := os.ReadFile(artifactsDir + "/Developers/alice@example.com.json")
+	if err != nil {
+		t.Fatalf("Developer file not written - %v", err)
+	}
+	assert.Contains(t, string(content), `"firstName": "Alice"`, "Expected developer details in file")
+	assert.NoFileExists(t, artifactsDir+"/Developers/bob@example.com.json", "Expected excluded developer not written")
+}
+
+func TestAPIMEntitySyncToTenant(t *testing.T) {
+	artifactsDir := t.TempDir()
+	_ = os.MkdirAll(artifactsDir+"/Developers", os.ModePerm)
+	_ = os.WriteFile(artifactsDir+"/Developers/alice@example.com.json", []byte(`{"email":"alice@example.com","firstName":"Alice","lastName":"Smith"}`), 0644)
+	_ = os.WriteFile(artifactsDir+"/Developers/bob@example.com.json", []byte(`{"email":"bob@example.com","firstName":"Robert","lastName":"Jones"}`), 0644)
+	_ = os.WriteFile(artifactsDir+"/Developers/carol@example.com.json", []byte(`{"email":"carol@example.com","firstName":"Carol","lastName":"White"}`), 0644)
+	client := &fakeDeveloperClient{developers: map[string]*api.DeveloperData{
+		"alice@example.com": {Email: "alice@example.com", FirstName: "Alice", LastName: "Smith"},
+		"bob@example.com":   {Email: "bob@example.com", FirstName: "Bob", LastName: "Jones"},
+	}}
+	syncer := &APIMEntityTenantSynchroniser[api.DeveloperData]{newFakeDeveloperEntity(client)}
+
+	err := syncer.Exec(Request{ArtifactsDir: artifactsDir})
+	if err != nil {
+		t.Fatalf("Exec failed with error - %v", err)
+	}
+	assert.Equal(t, []string{"carol@example.com"}, client.created, "Expected new developer created")
+	assert.Equal(t, []string{"bob@example.com"}, client.updated, "Expected changed developer updated")
+}
+
+type fakeKeyValueMapClient struct {
+	kvms    map[string]*api.KeyValueMapData
+	updated []*api.KeyValueMapData
+}
+
+func (c *fakeKeyValueMapClient) List() ([]*api.KeyValueMapData, error) {
+	return nil, nil
+}
+
+func (c *fakeKeyValueMapClient) Get(id string) (*api.KeyValueMapData, error) {
+	return c.kvms[id], nil
+}
+
+func (c *fakeKeyValueMapClient) Create(kvm *api.KeyValueMapData) error {
+	return nil
+}
+
+func (c *fakeKeyValueMapClient) Update(kvm *api.KeyValueMapData) error {
+	c.updated = append(c.updated, kvm)
+	return nil
+}
+
+func TestAPIMEntitySyncToTenantEncryptedKeyValueMap(t *testing.T) {
+	artifactsDir := t.TempDir()
+	_ = os.MkdirAll(artifactsDir+"/KeyValueMaps", os.ModePerm)
+	_ = os.WriteFile(artifactsDir+"/KeyValueMaps/Backend.Credentials.json", []byte(`{"name":"Backend.Credentials","encrypted":true,"keyMapEntryValues":[{"name":"password"}]}`), 0644)
+	_ = os.WriteFile(artifactsDir+"/KeyValueMaps/Backend.Config.json", []byte(`{"name":"Backend.Config","encrypted":false,"keyMapEntryValues":[{"name":"url","value":"https://example.com"}]}`), 0644)
+	t.Setenv("FLASHPIPE_KVM_BACKEND_CREDENTIALS_PASSWORD", "rotated")
+	client := &fakeKeyValueMapClient{kvms: map[string]*api.KeyValueMapData{
+		// Values of encrypted key value maps are always empty when retrieved from the tenant
+		"Backend.Credentials": {Name: "Backend.Credentials", Encrypted: true, Entries: []*api.KeyValueMapEntry{{Name: "password"}}},
+		"Backend.Config":      {Name: "Backend.Config", Entries: []*api.KeyValueMapEntry{{Name: "url", Value: "https://example.com"}}},
+	}}
+	entity := newKeyValueMapEntity(nil)
+	entity.client = client
+	syncer := &APIMEntityTenantSynchroniser[api.KeyValueMapData]{entity}
+
+	err := syncer.Exec(Request{ArtifactsDir: artifactsDir})
+	if err != nil {
+		t.Fatalf("Exec failed with error - %v", err)
+	}
+	if assert.Len(t, client.updated, 1, "Expected only encrypted key value map updated") {
+		assert.Equal(t, "Backend.Credentials", client.updated[0].Name, "Expected encrypted key value map updated")
+		assert.Equal(t, "rotated", client.updated[0].Entries[0].Value, "Expected secret resolved before update")
+	}
+}
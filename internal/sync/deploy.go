@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/rs/zerolog/log"
+)
+
+// CheckDeploymentStatus polls the runtime status of the artifact until it is started, or returns an error when
+// the deployment fails or the artifact is not started after the max number of checks
+func CheckDeploymentStatus(runtime *api.Runtime, delayLength int, maxCheckLimit int, id string) error {
+	log.Info().Msgf("Checking runtime status for artifact %v every %d seconds up to %d times", id, delayLength, maxCheckLimit)
+
+	for i := 0; i < maxCheckLimit; i++ {
+		version, status, err := runtime.Get(id)
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("Check %d - Current artifact runtime status = %s", i+1, status)
+		if version == "NOT_DEPLOYED" {
+			time.Sleep(time.Duration(delayLength) * time.Second)
+			continue
+		}
+		if status == "STARTED" {
+			return nil
+		} else if status != "STARTING" {
+			// If there is an error, delay before getting the error details as it sometimes return 204 when the error details are not available yet
+			time.Sleep(time.Duration(delayLength) * time.Second)
+			errorMessage, err := runtime.GetErrorInfo(id)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("Artifact deployment unsuccessful, ended with status %s. Error message = %s", status, errorMessage)
+		}
+		if i == (maxCheckLimit - 1) {
+			return fmt.Errorf("Artifact status remained in %s after %d checks", status, maxCheckLimit)
+		}
+		time.Sleep(time.Duration(delayLength) * time.Second)
+	}
+	return nil
+}
+
+const (
+	APIProxyDeployed   = "Deployed"
+	APIProxyUndeployed = "Undeployed"
+)
+
+type apiProxyStateGetter interface {
+	GetMetadata(id string) (*api.APIProxyMetadata, error)
+}
+
+// DeployAPIProxies deploys the APIProxies and polls their state until they are deployed. Failures of
+// individual APIProxies are reported and returned together after all APIProxies are processed
+func DeployAPIProxies(proxy *api.APIProxy, names []string, delayLength int, maxCheckLimit int) error {
+	return changeAPIProxiesState(proxy, proxy.Deploy, APIProxyDeployed, names, delayLength, maxCheckLimit)
+}
+
+// UndeployAPIProxies undeploys the APIProxies and polls their state until they are undeployed
+func UndeployAPIProxies(proxy *api.APIProxy, names []string, delayLength int, maxCheckLimit int) error {
+	return changeAPIProxiesState(proxy, proxy.Undeploy, APIProxyUndeployed, names, delayLength, maxCheckLimit)
+}
+
+func changeAPIProxiesState(proxy apiProxyStateGetter, trigger func(id string) error, expectedState string, names []string, delayLength int, maxCheckLimit int) error {
+	var triggered, failed []string
+	for _, name := range names {
+		err := trigger(name)
+		if err != nil {
+			log.Error().Msgf("APIProxy %v could not be changed to %v - %v", name, expectedState, err)
+			failed = append(failed, name)
+			continue
+		}
+		triggered = append(triggered, name)
+	}
+	for _, name := range triggered {
+		err := checkAPIProxyState(proxy, name, expectedState, delayLength, maxCheckLimit)
+		if err != nil {
+			log.Error().Msgf("%v", err)
+			failed = append(failed, name)
+			continue
+		}
+		log.Info().Msgf("🏆 APIProxy %v %v successfully", name, strings.ToLower(expectedState))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("APIProxy state not changed to %v for: %v", expectedState, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// checkAPIProxyState polls the state of the APIProxy until it matches the expected state, or returns an error when
+// the state indicates a failure or does not match after the max number of checks
+func checkAPIProxyState(proxy apiProxyStateGetter, name string, expectedState string, delayLength int, maxCheckLimit int) error {
+	log.Info().Msgf("Checking state of APIProxy %v every %d seconds up to %d times", name, delayLength, maxCheckLimit)
+
+	var state string
+	for i := 0; i < maxCheckLimit; i++ {
+		metadata, err := proxy.GetMetadata(name)
+		if err != nil {
+			return err
+		}
+		if metadata == nil {
+			return fmt.Errorf("APIProxy %v does not exist", name)
+		}
+		state = metadata.Status
+		log.Info().Msgf("Check %d - Current APIProxy state = %s", i+1, state)
+		if strings.EqualFold(state, expectedState) {
+			return nil
+		}
+		lowerState := strings.ToLower(state)
+		if strings.Contains(lowerState, "fail") || strings.Contains(lowerState, "error") {
+			return fmt.Errorf("APIProxy %v ended with state %s", name, state)
+		}
+		if i < maxCheckLimit-1 {
+			time.Sleep(time.Duration(delayLength) * time.Second)
+		}
+	}
+	return fmt.Errorf("APIProxy %v state remained in %s after %d checks", name, state, maxCheckLimit)
+}
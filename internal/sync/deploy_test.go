@@ -0,0 +1,55 @@
+package sync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPIProxyStates returns the states of each APIProxy in sequence for every check
+type fakeAPIProxyStates map[string][]string
+
+func (f fakeAPIProxyStates) GetMetadata(id string) (*api.APIProxyMetadata, error) {
+	states, ok := f[id]
+	if !ok {
+		return nil, nil
+	}
+	state := states[0]
+	if len(states) > 1 {
+		f[id] = states[1:]
+	}
+	return &api.APIProxyMetadata{Name: id, Status: state}, nil
+}
+
+func TestCheckAPIProxyState(t *testing.T) {
+	states := fakeAPIProxyStates{"Orders": {"Undeployed", "Deploying", "DEPLOYED"}}
+
+	err := checkAPIProxyState(states, "Orders", APIProxyDeployed, 0, 5)
+	assert.NoError(t, err, "Expected APIProxy deployed after third check")
+
+	states = fakeAPIProxyStates{"Orders": {"Deploying"}}
+	err = checkAPIProxyState(states, "Orders", APIProxyDeployed, 0, 3)
+	assert.ErrorContains(t, err, "remained in Deploying after 3 checks", "Expected error when max checks reached")
+
+	states = fakeAPIProxyStates{"Orders": {"Deploying", "Failed"}}
+	err = checkAPIProxyState(states, "Orders", APIProxyDeployed, 0, 5)
+	assert.ErrorContains(t, err, "ended with state Failed", "Expected error for failed deployment")
+}
+
+func TestChangeAPIProxiesStateReportsAllFailures(t *testing.T) {
+	states := fakeAPIProxyStates{"Orders": {"Deployed"}, "Invoices": {"Failed"}}
+	var triggered []string
+	trigger := func(id string) error {
+		triggered = append(triggered, id)
+		if id == "Unknown" {
+			return fmt.Errorf("Deploy APIProxy call failed with response code = 404")
+		}
+		return nil
+	}
+
+	err := changeAPIProxiesState(states, trigger, APIProxyDeployed, []string{"Unknown", "Orders", "Invoices"}, 0, 3)
+	assert.Equal(t, []string{"Unknown", "Orders", "Invoices"}, triggered, "Expected all APIProxies triggered")
+	assert.EqualError(t, err, "APIProxy state not changed to Deployed for: Unknown, Invoices", "Expected failures of all APIProxies reported")
+}
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/drift"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/go-errors/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type gitArtifact struct {
+	dir          string
+	artifactType string
+}
+
+// PackageDrift compares the package in the tenant with the package directory in Git without changing Git, and returns
+// the drift status of the package details and its artifacts. packageDataFromTenant is nil if the package does not
+// exist in the tenant, in which case nil is returned if the directory in Git is not a package directory either.
+// The package details are only compared if the package file exists in Git. The rules convert values in IFlow BPMN2 XML
+// of the tenant content before comparison, as when syncing to Git.
+func (s *Synchroniser) PackageDrift(packageId string, packageDataFromTenant *api.PackageSingleData, artifactsDir string, ignoredFields []string, rules []*file.BPMNRule) (*drift.PackageResult, error) {
+	result := &drift.PackageResult{Id: packageId, Status: drift.StatusUnchanged, Artifacts: []*drift.ArtifactResult{}}
+	gitArtifacts, err := getGitArtifacts(artifactsDir)
+	if err != nil {
+		return nil, err
+	}
+	gitPackageFile := fmt.Sprintf("%v/%v.json", artifactsDir, packageId)
+
+	if packageDataFromTenant == nil {
+		if !file.Exists(gitPackageFile) && len(gitArtifacts) == 0 {
+			return nil, nil
+		}
+		log.Warn().Msgf("Package %v does not exist in tenant", packageId)
+		result.Status = drift.StatusMissingInTenant
+		for id, artifact := range gitArtifacts {
+			result.Artifacts = append(result.Artifacts, &drift.ArtifactResult{Id: id, Type: artifact.artifactType, Status: drift.StatusMissingInTenant})
+		}
+		return result, nil
+	}
+
+	if file.Exists(gitPackageFile) {
+		packageDataFromGit, err := api.GetPackageDetails(gitPackageFile)
+		if err != nil {
+			return nil, err
+		}
+		diffs := DiffPackageDetails(packageDataFromGit, packageDataFromTenant, getPackageIgnoredFields(ignoredFields))
+		if len(diffs) > 0 {
+			logPackageDiffs(packageId, diffs, "Git", "tenant")
+			result.Status = drift.StatusChanged
+			for _, diff := range diffs {
+				result.ChangedFields = append(result.ChangedFields, diff.Field)
+			}
+		}
+	} else if len(gitArtifacts) == 0 {
+		log.Warn().Msgf("Package %v does not exist in Git", packageId)
+		result.Status = drift.StatusMissingInGit
+	} else {
+		log.Info().Msgf("Package file %v not found, package details are not compared", gitPackageFile)
+	}
+
+	artifacts, err := s.artifactsDrift(packageId, gitArtifacts, rules)
+	if err != nil {
+		return nil, err
+	}
+	result.Artifacts = artifacts
+	return result, nil
+}
+
+// artifactsDrift compares the artifacts of the package in the tenant with the artifact directories in Git without
+// changing Git, and returns the drift status of each artifact. Draft artifacts are not compared.
+func (s *Synchroniser) artifactsDrift(packageId string, gitArtifacts map[string]*gitArtifact, rules []*file.BPMNRule) ([]*drift.ArtifactResult, error) {
+	log.Info().Msgf("Getting artifacts in integration package %v", packageId)
+	artifacts, err := s.ip.GetAllArtifacts(packageId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*drift.ArtifactResult{}
+	compared := map[string]bool{}
+	for _, artifact := range artifacts {
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("📢 Checking drift of artifact %v", artifact.Id)
+		result := &drift.ArtifactResult{Id: artifact.Id, Type: artifact.ArtifactType, Status: drift.StatusUnchanged}
+		results = append(results, result)
+		compared[artifact.Id] = true
+
+		if artifact.IsDraft {
+			log.Warn().Msgf("Artifact %v is in draft version in tenant", artifact.Id)
+			result.Status = drift.StatusDraft
+			continue
+		}
+		git := gitArtifacts[artifact.Id]
+		if git == nil {
+			log.Warn().Msgf("Artifact %v does not exist in Git", artifact.Id)
+			result.Status = drift.StatusMissingInGit
+			continue
+		}
+		dt := api.NewDesigntimeArtifact(artifact.ArtifactType, s.exe)
+		_, _, dirDiffer, err := compareWithGit(artifact.Id, git.dir, rules, dt)
+		if err != nil {
+			return nil, err
+		}
+		if dirDiffer {
+			log.Warn().Msgf("Artifact %v in tenant differs from Git", artifact.Id)
+			result.Status = drift.StatusChanged
+		} else {
+			log.Info().Msgf("🏆 Artifact %v in tenant is the same as in Git", artifact.Id)
+		}
+	}
+
+	for id, git := range gitArtifacts {
+		if !compared[id] {
+			log.Warn().Msgf("Artifact %v in %v does not exist in tenant", id, git.dir)
+			results = append(results, &drift.ArtifactResult{Id: id, Type: git.artifactType, Status: drift.StatusMissingInTenant})
+		}
+	}
+	return results, nil
+}
+
+// GitArtifactIds returns the IDs of the artifacts in the subdirectories of artifactsDir, sorted by ID
+func GitArtifactIds(artifactsDir string) ([]string, error) {
+	gitArtifacts, err := getGitArtifacts(artifactsDir)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(gitArtifacts))
+	for id := range gitArtifacts {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// getGitArtifacts returns the artifact directories in Git keyed by the artifact ID in MANIFEST.MF
+func getGitArtifacts(artifactsDir string) (map[string]*gitArtifact, error) {
+	gitArtifacts := map[string]*gitArtifact{}
+	if !file.Exists(artifactsDir) {
+		return gitArtifacts, nil
+	}
+	entries, err := os.ReadDir(artifactsDir)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	for _, entry := range entries {
+		artifactDir := filepath.Join(artifactsDir, entry.Name())
+		manifestPath := filepath.Join(artifactDir, "META-INF", "MANIFEST.MF")
+		if !entry.IsDir() || !file.Exists(manifestPath) {
+			continue
+		}
+		headers, err := file.GetManifestHeaders(manifestPath)
+		if err != nil {
+			return nil, err
+		}
+		artifactType := headers.Get("SAP-BundleType")
+		if artifactType == "IntegrationFlow" {
+			artifactType = "Integration"
+		}
+		gitArtifacts[file.GetArtifactId(headers)] = &gitArtifact{dir: artifactDir, artifactType: artifactType}
+	}
+	return gitArtifacts, nil
+}
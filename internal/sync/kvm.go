@@ -0,0 +1,55 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/go-errors/errors"
+	"github.com/magiconair/properties"
+	"github.com/rs/zerolog/log"
+)
+
+var envNameRegex = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// KeyValueMapSecretEnv returns the name of the environment variable that contains the value of an
+// entry of an encrypted key value map, e.g. FLASHPIPE_KVM_BACKEND_CREDENTIALS_PASSWORD
+func KeyValueMapSecretEnv(mapName string, entryName string) string {
+	return "FLASHPIPE_KVM_" + envNameRegex.ReplaceAllString(strings.ToUpper(mapName+"_"+entryName), "_")
+}
+
+// resolveKeyValueMapSecrets sets the values of the entries of an encrypted key value map from the environment
+// variables, or from key <map>.<entry> of the secrets file. Values of encrypted entries are never read from Git.
+func resolveKeyValueMapSecrets(kvm *api.KeyValueMapData, secretsFile string) error {
+	if !kvm.Encrypted {
+		return nil
+	}
+	secrets := map[string]string{}
+	if secretsFile != "" {
+		p, err := properties.LoadFile(secretsFile, properties.UTF8)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		secrets = p.Map()
+	}
+	var missing []string
+	for _, entry := range kvm.Entries {
+		if entry.Value != "" {
+			log.Warn().Msgf("⚠️ Value of entry %v of encrypted KeyValueMap %v in Git is ignored", entry.Name, kvm.Name)
+		}
+		envName := KeyValueMapSecretEnv(kvm.Name, entry.Name)
+		if value, ok := os.LookupEnv(envName); ok {
+			entry.Value = value
+		} else if value, ok := secrets[fmt.Sprintf("%v.%v", kvm.Name, entry.Name)]; ok {
+			entry.Value = value
+		} else {
+			missing = append(missing, fmt.Sprintf("%v (environment variable %v)", entry.Name, envName))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Values of encrypted KeyValueMap %v not found for entries: %v", kvm.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func newEncryptedKeyValueMap() *api.KeyValueMapData {
+	return &api.KeyValueMapData{
+		Name:      "Backend.Credentials",
+		Encrypted: true,
+		Entries:   []*api.KeyValueMapEntry{{Name: "username"}, {Name: "password"}},
+	}
+}
+
+func TestKeyValueMapSecretEnv(t *testing.T) {
+	assert.Equal(t, "FLASHPIPE_KVM_BACKEND_CREDENTIALS_CLIENT_SECRET", KeyValueMapSecretEnv("Backend.Credentials", "client-secret"), "Incorrect environment variable name")
+}
+
+func TestResolveKeyValueMapSecrets(t *testing.T) {
+	// Environment variables take precedence over the secrets file
+	t.Setenv("FLASHPIPE_KVM_BACKEND_CREDENTIALS_PASSWORD", "from-env")
+	kvm := newEncryptedKeyValueMap()
+
+	err := resolveKeyValueMapSecrets(kvm, "../../test/testdata/apim-secrets/kvm-secrets.properties")
+	if err != nil {
+		t.Fatalf("resolveKeyValueMapSecrets failed with error - %v", err)
+	}
+	assert.Equal(t, "svc_user", kvm.Entries[0].Value, "Expected value from secrets file")
+	assert.Equal(t, "from-env", kvm.Entries[1].Value, "Expected value from environment variable")
+}
+
+func TestResolveKeyValueMapSecretsMissing(t *testing.T) {
+	err := resolveKeyValueMapSecrets(newEncryptedKeyValueMap(), "")
+	assert.ErrorContains(t, err, "FLASHPIPE_KVM_BACKEND_CREDENTIALS_USERNAME", "Expected error for missing value")
+}
+
+func TestResolveKeyValueMapSecretsNotEncrypted(t *testing.T) {
+	kvm := &api.KeyValueMapData{Name: "Settings", Entries: []*api.KeyValueMapEntry{{Name: "timeout", Value: "30"}}}
+
+	err := resolveKeyValueMapSecrets(kvm, "")
+	if err != nil {
+		t.Fatalf("resolveKeyValueMapSecrets failed with error - %v", err)
+	}
+	assert.Equal(t, "30", kvm.Entries[0].Value, "Expected value from Git kept")
+}
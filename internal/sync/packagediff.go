@@ -0,0 +1,52 @@
+package sync
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultPackageIgnoredFields are the fields of integration packages that are maintained by the tenant and are not
+// considered as changes. They are not sent when the package is created or updated
+var DefaultPackageIgnoredFields = []string{"CreatedBy", "CreationDate", "ModifiedBy", "ModifiedDate", "SupportedPlatform"}
+
+// PackageFieldDiff is a field of the integration package details with different values
+type PackageFieldDiff struct {
+	Field  string `json:"field"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// DiffPackageDetails compares all fields of the integration package details except the ignored fields
+func DiffPackageDetails(source *api.PackageSingleData, target *api.PackageSingleData, ignoredFields []string) []*PackageFieldDiff {
+	var diffs []*PackageFieldDiff
+	sourceValue := reflect.ValueOf(source.Root)
+	targetValue := reflect.ValueOf(target.Root)
+	for i := 0; i < sourceValue.NumField(); i++ {
+		field := sourceValue.Type().Field(i).Name
+		if slices.Contains(ignoredFields, field) {
+			continue
+		}
+		sourceField := sourceValue.Field(i).String()
+		targetField := targetValue.Field(i).String()
+		if sourceField != targetField {
+			diffs = append(diffs, &PackageFieldDiff{Field: field, Source: sourceField, Target: targetField})
+		}
+	}
+	return diffs
+}
+
+func logPackageDiffs(packageId string, diffs []*PackageFieldDiff, sourceName string, targetName string) {
+	for _, diff := range diffs {
+		log.Info().Msgf("Package %v field %v changed - %v: %q, %v: %q", packageId, diff.Field, sourceName, diff.Source, targetName, diff.Target)
+	}
+}
+
+func getPackageIgnoredFields(ignoredFields []string) []string {
+	if ignoredFields == nil {
+		return DefaultPackageIgnoredFields
+	}
+	return ignoredFields
+}
@@ -0,0 +1,35 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffPackageDetails(t *testing.T) {
+	source := newPackageDetails("1.0.0", "Description")
+	source.Root.Keywords = "demo"
+	source.Root.ModifiedDate = "/Date(1700000000000)/"
+	target := newPackageDetails("1.0.1", "Description")
+	target.Root.Keywords = "demo,flashpipe"
+	target.Root.SupportedPlatform = "SAP Cloud Integration"
+	target.Root.ModifiedDate = "/Date(1710000000000)/"
+
+	diffs := DiffPackageDetails(source, target, DefaultPackageIgnoredFields)
+
+	assert.Equal(t, 2, len(diffs), "Expected number of diffs = 2")
+	assert.Equal(t, &PackageFieldDiff{Field: "Version", Source: "1.0.0", Target: "1.0.1"}, diffs[0], "Expected diff of Version")
+	assert.Equal(t, "Keywords", diffs[1].Field, "Expected diff of Keywords")
+
+	diffs = DiffPackageDetails(source, target, []string{})
+	assert.Equal(t, "SupportedPlatform", diffs[2].Field, "Expected diff of SupportedPlatform when not ignored")
+}
+
+func TestDiffPackageDetailsIgnoredFields(t *testing.T) {
+	source := newPackageDetails("1.0.0", "Description")
+	target := newPackageDetails("1.0.1", "Description")
+	target.Root.ModifiedBy = "user"
+
+	assert.Equal(t, 0, len(DiffPackageDetails(source, target, []string{"Version", "ModifiedBy"})), "Expected no diffs for ignored fields")
+	assert.Equal(t, 2, len(DiffPackageDetails(source, target, []string{})), "Expected all fields compared with empty ignore list")
+}
@@ -1,9 +1,11 @@
 package sync
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/file"
@@ -23,6 +25,21 @@ type Request struct {
 	IncludedIds  []string
 	ExcludedIds  []string
 	PackageFile  string
+	// Increment package version when changes are found. Allowed values: patch, minor, major, timestamp
+	VersionBump string
+	// Write the bumped package version back to the package file
+	VersionBumpWriteBack bool
+	// Fields that are not compared. If nil, DefaultPackageIgnoredFields is used for packages and
+	// file.DefaultAPIMIgnoredFields is used for APIProxies
+	IgnoredFields []string
+	// Properties file with values of encrypted KeyValueMap entries
+	SecretsFile string
+	// Deploy the APIProxies that are created or updated in the tenant and wait until they are deployed
+	Deploy        bool
+	DelayLength   int
+	MaxCheckLimit int
+	// Environment-specific values of APIProxies, oriented in the direction of the sync
+	Overlay *file.APIProxyOverlay
 }
 
 func NewSyncer(target string, functionType string, exe *httpclnt.HTTPExecuter) Syncer {
@@ -36,6 +53,18 @@ func NewSyncer(target string, functionType string, exe *httpclnt.HTTPExecuter) S
 		default:
 			return nil
 		}
+	case "APIMProduct":
+		return newAPIMEntitySyncer(target, newAPIProductEntity(exe))
+	case "APIMApplication":
+		return newAPIMEntitySyncer(target, newApplicationEntity(exe))
+	case "APIMDeveloper":
+		return newAPIMEntitySyncer(target, newDeveloperEntity(exe))
+	case "APIMProvider":
+		return newAPIMEntitySyncer(target, newAPIProviderEntity(exe))
+	case "APIMKeyValueMap":
+		return newAPIMEntitySyncer(target, newKeyValueMapEntity(exe))
+	case "APIMRatePlan":
+		return newAPIMEntitySyncer(target, newRatePlanEntity(exe))
 	case "CPIPackage":
 		switch target {
 		case "tenant":
@@ -51,6 +80,17 @@ func NewSyncer(target string, functionType string, exe *httpclnt.HTTPExecuter) S
 	}
 }
 
+func newAPIMEntitySyncer[T any](target string, entity apimEntity[T]) Syncer {
+	switch target {
+	case "git":
+		return &APIMEntityGitSynchroniser[T]{entity}
+	case "tenant":
+		return &APIMEntityTenantSynchroniser[T]{entity}
+	default:
+		return nil
+	}
+}
+
 type APIMGitSynchroniser struct {
 	exe *httpclnt.HTTPExecuter
 }
@@ -98,6 +138,16 @@ func (s *APIMGitSynchroniser) Exec(request Request) error {
 		// Compare content and update Git if required
 		gitArtifactPath := fmt.Sprintf("%v/%v", request.ArtifactsDir, artifact.Name)
 		downloadedArtifactPath := fmt.Sprintf("%v/%v", targetRootDir, artifact.Name)
+		// Revert environment-specific values so that they are not stored in Git
+		err = file.ApplyOverlay(downloadedArtifactPath, artifact.Name, request.Overlay.Rules(artifact.Name))
+		if err != nil {
+			return err
+		}
+		// Normalize content so that volatile values are not stored in Git
+		err = file.NormalizeAPIMContent(downloadedArtifactPath, request.IgnoredFields)
+		if err != nil {
+			return err
+		}
 		if file.Exists(fmt.Sprintf("%v/manifest.json", gitArtifactPath)) {
 			// (1) If artifact already exists in Git, then compare and update
 			log.Info().Msg("Comparing content from tenant against Git")
@@ -162,6 +212,7 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 	}
 
 	artifactDirFound := false
+	var importedProxies []string
 	for _, entry := range entries {
 		artifactId := entry.Name()
 		manifestPath := fmt.Sprintf("%v/%v/manifest.json", baseSourceDir, artifactId)
@@ -178,6 +229,19 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 			}
 
 			log.Info().Msgf("📢 Begin processing for APIProxy %v", artifactId)
+			// Apply environment-specific values to a copy of the Git directory
+			if rules := request.Overlay.Rules(artifactId); len(rules) > 0 {
+				overlayArtifactDir := fmt.Sprintf("%v/overlay/%v", request.WorkDir, artifactId)
+				err = file.ReplaceDir(gitArtifactDir, overlayArtifactDir)
+				if err != nil {
+					return err
+				}
+				err = file.ApplyOverlay(overlayArtifactDir, artifactId, rules)
+				if err != nil {
+					return err
+				}
+				gitArtifactDir = overlayArtifactDir
+			}
 			proxyExists, err := proxy.Get(artifactId)
 			if err != nil {
 				return err
@@ -191,6 +255,7 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 				}
 
 				log.Info().Msg("🏆 APIProxy created successfully")
+				importedProxies = append(importedProxies, artifactId)
 			} else {
 				log.Info().Msg("Checking if APIProxy needs to be updated")
 
@@ -201,7 +266,19 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 
 				log.Info().Msg("Comparing content from tenant against Git")
 				downloadArtifactDir := fmt.Sprintf("%v/%v", downloadWorkDir, artifactId)
-				dirDiffer := file.DiffDirectories(downloadArtifactDir, gitArtifactDir)
+				// Compare normalized content, but upload the content from Git as is
+				normalizedArtifactDir := fmt.Sprintf("%v/normalized/%v", request.WorkDir, artifactId)
+				err = file.ReplaceDir(gitArtifactDir, normalizedArtifactDir)
+				if err != nil {
+					return err
+				}
+				for _, dir := range []string{downloadArtifactDir, normalizedArtifactDir} {
+					err = file.NormalizeAPIMContent(dir, request.IgnoredFields)
+					if err != nil {
+						return err
+					}
+				}
+				dirDiffer := file.DiffDirectories(downloadArtifactDir, normalizedArtifactDir)
 				if dirDiffer {
 					log.Info().Msg("Changes found in APIProxy. APIProxy will be updated in tenant")
 
@@ -210,6 +287,7 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 						return err
 					}
 					log.Info().Msg("🏆 APIProxy updated successfully")
+					importedProxies = append(importedProxies, artifactId)
 				} else {
 					log.Info().Msg("🏆 No changes detected. APIProxy does not need to be updated")
 				}
@@ -219,6 +297,14 @@ func (s *APIMTenantSynchroniser) Exec(request Request) error {
 	if !artifactDirFound {
 		log.Warn().Msgf("No directory with APIProxy contents found in %v", baseSourceDir)
 	}
+	if request.Deploy && len(importedProxies) > 0 {
+		log.Info().Msg("---------------------------------------------------------------------------------")
+		log.Info().Msgf("Deploying %d imported APIProxies", len(importedProxies))
+		err = DeployAPIProxies(proxy, importedProxies, request.DelayLength, request.MaxCheckLimit)
+		if err != nil {
+			return err
+		}
+	}
 	log.Info().Msg("---------------------------------------------------------------------------------")
 	log.Info().Msgf("🏆 Completed processing of APIProxies")
 	return nil
@@ -252,7 +338,7 @@ func (s *CPIPackageTenantSynchroniser) Exec(request Request) error {
 	ip := api.NewIntegrationPackage(s.exe)
 
 	packageId := packageDetails.Root.Id
-	_, _, exists, err := ip.Get(packageId)
+	tenantDetails, _, exists, err := ip.Get(packageId)
 	if err != nil {
 		return err
 	}
@@ -264,6 +350,20 @@ func (s *CPIPackageTenantSynchroniser) Exec(request Request) error {
 		}
 		log.Info().Msgf("Package %v created", packageId)
 	} else {
+		// Mode cannot be changed in create/update
+		ignoredFields := append([]string{"Mode"}, getPackageIgnoredFields(request.IgnoredFields)...)
+		diffs := DiffPackageDetails(tenantDetails, packageDetails, ignoredFields)
+		if len(diffs) == 0 {
+			log.Info().Msgf("🏆 No changes to package %v detected. Update not required", packageId)
+			return nil
+		}
+		logPackageDiffs(packageId, diffs, "tenant", "file")
+		if request.VersionBump != "" {
+			err = bumpPackageVersion(packageDetails, tenantDetails, packageFile, request)
+			if err != nil {
+				return err
+			}
+		}
 		// Update integration package
 		err = ip.Update(packageDetails)
 		if err != nil {
@@ -273,3 +373,38 @@ func (s *CPIPackageTenantSynchroniser) Exec(request Request) error {
 	}
 	return nil
 }
+
+// bumpPackageVersion increments the package version based on the version in the tenant when the package details are changed
+func bumpPackageVersion(packageDetails *api.PackageSingleData, tenantDetails *api.PackageSingleData, packageFile string, request Request) error {
+	fileVersion := packageDetails.Root.Version
+	tenantVersion := tenantDetails.Root.Version
+
+	// Compare the package details without the version
+	ignoredFields := append([]string{"Mode", "Version"}, getPackageIgnoredFields(request.IgnoredFields)...)
+	if len(DiffPackageDetails(tenantDetails, packageDetails, ignoredFields)) == 0 {
+		log.Info().Msgf("No changes to package details. Version bump not required")
+		return nil
+	}
+	if str.CompareVersions(fileVersion, tenantVersion) > 0 {
+		log.Info().Msgf("Package version %v is higher than version %v in tenant. Version bump not required", fileVersion, tenantVersion)
+		return nil
+	}
+
+	newVersion, err := str.BumpVersion(tenantVersion, request.VersionBump, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Bumping version of package %v from %v to %v", packageDetails.Root.Id, tenantVersion, newVersion)
+	packageDetails.Root.Version = newVersion
+	if request.VersionBumpWriteBack {
+		content, err := json.MarshalIndent(packageDetails, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		err = os.WriteFile(packageFile, content, 0644)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
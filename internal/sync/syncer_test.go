@@ -0,0 +1,54 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPackageDetails(version string, description string) *api.PackageSingleData {
+	details := new(api.PackageSingleData)
+	details.Root.Id = "FlashPipeDemo"
+	details.Root.Name = "FlashPipe Demo"
+	details.Root.Version = version
+	details.Root.Description = description
+	return details
+}
+
+func TestBumpPackageVersion(t *testing.T) {
+	packageFile := t.TempDir() + "/FlashPipeDemo.json"
+	packageDetails := newPackageDetails("1.0.0", "Updated description")
+
+	err := bumpPackageVersion(packageDetails, newPackageDetails("1.0.2", "Description"), packageFile, Request{VersionBump: "minor", VersionBumpWriteBack: true})
+	if err != nil {
+		t.Fatalf("bumpPackageVersion failed with error - %v", err)
+	}
+	assert.Equal(t, "1.1.0", packageDetails.Root.Version, "Expected version bumped from tenant version")
+
+	written, err := api.GetPackageDetails(packageFile)
+	if err != nil {
+		t.Fatalf("GetPackageDetails failed with error - %v", err)
+	}
+	assert.Equal(t, "1.1.0", written.Root.Version, "Expected bumped version written back to package file")
+}
+
+func TestBumpPackageVersionNoChanges(t *testing.T) {
+	packageDetails := newPackageDetails("1.0.0", "Description")
+
+	err := bumpPackageVersion(packageDetails, newPackageDetails("1.0.2", "Description"), "", Request{VersionBump: "patch"})
+	if err != nil {
+		t.Fatalf("bumpPackageVersion failed with error - %v", err)
+	}
+	assert.Equal(t, "1.0.0", packageDetails.Root.Version, "Expected version unchanged when only version differs")
+}
+
+func TestBumpPackageVersionHigherInFile(t *testing.T) {
+	packageDetails := newPackageDetails("2.0.0", "Updated description")
+
+	err := bumpPackageVersion(packageDetails, newPackageDetails("1.0.2", "Description"), "", Request{VersionBump: "patch"})
+	if err != nil {
+		t.Fatalf("bumpPackageVersion failed with error - %v", err)
+	}
+	assert.Equal(t, "2.0.0", packageDetails.Root.Version, "Expected manually bumped version kept")
+}
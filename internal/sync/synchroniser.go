@@ -1,18 +1,19 @@
 package sync
 
 import (
-	"bufio"
 	"encoding/json"
 	"fmt"
-	"net/textproto"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/engswee/flashpipe/internal/api"
 	"github.com/engswee/flashpipe/internal/file"
 	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/engswee/flashpipe/internal/params"
+	"github.com/engswee/flashpipe/internal/state"
 	"github.com/engswee/flashpipe/internal/str"
 	"github.com/go-errors/errors"
 	"github.com/magiconair/properties"
@@ -20,8 +21,47 @@ import (
 )
 
 type Synchroniser struct {
-	exe *httpclnt.HTTPExecuter
-	ip  *api.IntegrationPackage
+	exe                *httpclnt.HTTPExecuter
+	ip                 *api.IntegrationPackage
+	paramUpdates       map[string][]*ParameterUpdate
+	versionBumpedFiles []string
+	state              *state.State
+	fullSync           bool
+	contentHashes      map[string]string
+}
+
+// ParameterUpdate is a configured parameter of an artifact that was updated in the tenant
+type ParameterUpdate struct {
+	Key      string
+	OldValue string
+	NewValue string
+}
+
+// UploadOptions contains the optional settings for creating/updating artifacts in the tenant
+type UploadOptions struct {
+	// Rules for converting values in IFlow BPMN2 XML
+	Rules []*file.BPMNRule
+	// Cross-check parameters.prop against parameters.propdef and the IFlow before upload. Allowed values: WARN (log
+	// findings), ERROR (stop when there are errors), SKIP or empty (no check)
+	ParamsCheck string
+	// Source of parameters file when syncing multiple artifacts. Allowed values: DEFAULT, ENV, FILE
+	ParamSource string
+	// Environment directory containing <artifact ID>.prop files for ParamSource ENV
+	ParamEnvDir string
+	// Parameters file for ParamSource FILE. {ARTIFACT_ID} is replaced with the artifact ID
+	ParamFile string
+	// Redeploy instead of undeploying the runtime artifact after changes in configured parameters
+	RedeployOnParamChange bool
+	// Redeploy and wait for the runtime artifact after changes in design or configured parameters
+	Redeploy bool
+	// Delay (in seconds) between each check of artifact deployment status when redeploying
+	DelayLength int
+	// Max number of times to check for artifact deployment status when redeploying
+	MaxCheckLimit int
+	// Increment Bundle-Version when changes are found. Allowed values: patch, minor, major, timestamp
+	VersionBump string
+	// Write the bumped Bundle-Version back to MANIFEST.MF in the artifact directory
+	VersionBumpWriteBack bool
 }
 
 func New(exe *httpclnt.HTTPExecuter) *Synchroniser {
@@ -31,7 +71,20 @@ func New(exe *httpclnt.HTTPExecuter) *Synchroniser {
 	return s
 }
 
-func (s *Synchroniser) PackageToGit(packageDataFromTenant *api.PackageSingleData, packageId string, workDir string, artifactsDir string) error {
+// UseState enables incremental sync, where artifacts are not downloaded again unless full is set:
+//   - to Git, if they have the same version and modification date as in the state, and the content hash of the
+//     artifact directory is unchanged
+//   - to the tenant, if they have the same content hash as the last upload, and the version in the tenant is unchanged
+//
+// The state is updated with the artifacts that are synced.
+func (s *Synchroniser) UseState(st *state.State, full bool) {
+	s.state = st
+	s.fullSync = full
+}
+
+// PackageToGit saves the package details from the tenant to Git when any field other than the ignored fields
+// has changed. If ignoredFields is nil, DefaultPackageIgnoredFields is used.
+func (s *Synchroniser) PackageToGit(packageDataFromTenant *api.PackageSingleData, packageId string, workDir string, artifactsDir string, ignoredFields []string) error {
 	// Create temp directory in working dir
 	err := os.MkdirAll(workDir+"/from_tenant", os.ModePerm)
 	if err != nil {
@@ -62,7 +115,9 @@ func (s *Synchroniser) PackageToGit(packageDataFromTenant *api.PackageSingleData
 		if err != nil {
 			return err
 		}
-		if packageContentDiffer(packageDataFromTenant, packageDataFromGit) {
+		diffs := DiffPackageDetails(packageDataFromGit, packageDataFromTenant, getPackageIgnoredFields(ignoredFields))
+		if len(diffs) > 0 {
+			logPackageDiffs(packageId, diffs, "Git", "tenant")
 			log.Info().Msgf("🏆 Changes to package %v detected and will be updated to Git", packageId)
 			err = file.CopyFile(tenantFile, gitSourceFile)
 			if err != nil {
@@ -102,7 +157,7 @@ func (s *Synchroniser) VerifyDownloadablePackage(packageId string) (packageDataF
 	return
 }
 
-func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifactsDir string, includedIds []string, excludedIds []string, draftHandling string, dirNamingType string, scriptCollectionMap []string) error {
+func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifactsDir string, includedIds []string, excludedIds []string, draftHandling string, dirNamingType string, rules []*file.BPMNRule) error {
 	// Get all design time artifacts of package
 	log.Info().Msgf("Getting artifacts in integration package %v", packageId)
 	artifacts, err := s.ip.GetAllArtifacts(packageId)
@@ -110,12 +165,6 @@ func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifact
 		return err
 	}
 
-	// Create temp directories in working dir
-	err = os.MkdirAll(workDir+"/download", os.ModePerm)
-	if err != nil {
-		return errors.Wrap(err, 0)
-	}
-
 	filtered, err := filterArtifacts(artifacts, includedIds, excludedIds)
 	if err != nil {
 		return err
@@ -137,13 +186,6 @@ func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifact
 				return fmt.Errorf("Artifact %v is in draft version. Save Version in Web UI first!", artifact.Id)
 			}
 		}
-		// Download artifact content
-		dt := api.NewDesigntimeArtifact(artifact.ArtifactType, s.exe)
-		targetDownloadFile := fmt.Sprintf("%v/download/%v.zip", workDir, artifact.Id)
-		err = dt.Download(targetDownloadFile, artifact.Id)
-		if err != nil {
-			return err
-		}
 
 		// TODO - override directory name using key value pair - to cater for syncing artifact from different environment
 		var directoryName string
@@ -152,30 +194,34 @@ func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifact
 		} else {
 			directoryName = artifact.Id
 		}
-		// Unzip artifact contents
-		log.Debug().Msgf("Target artifact directory name - %v", directoryName)
-		downloadedArtifactPath := fmt.Sprintf("%v/download/%v", workDir, directoryName)
-		err = file.UnzipSource(targetDownloadFile, downloadedArtifactPath)
-		if err != nil {
-			return err
-		}
-		log.Info().Msgf("Downloaded artifact unzipped to %v", downloadedArtifactPath)
-
 		gitArtifactPath := fmt.Sprintf("%v/%v", artifactsDir, directoryName)
-		if file.Exists(fmt.Sprintf("%v/META-INF/MANIFEST.MF", gitArtifactPath)) {
-			// (1) If artifact already exists in Git, then compare and update
-			log.Info().Msg("Comparing content from tenant against Git")
 
-			// Diff artifact contents
-			dirDiffer, err := dt.CompareContent(downloadedArtifactPath, gitArtifactPath, scriptCollectionMap, "git")
+		dt := api.NewDesigntimeArtifact(artifact.ArtifactType, s.exe)
+		if s.state != nil && !s.fullSync && !s.state.Changed(packageId, artifact) {
+			unchanged, gitHash, err := s.gitContentUnchanged(packageId, artifact.Id, gitArtifactPath, dt)
 			if err != nil {
 				return err
 			}
+			if unchanged {
+				log.Info().Msgf("🏆 Artifact %v has the same version, modification date and content as the previous sync. Download not required", artifact.Id)
+				s.recordContentHash(artifact.Id, gitHash)
+				continue
+			}
+		}
 
+		log.Debug().Msgf("Target artifact directory name - %v", directoryName)
+		downloaded, gitContent, dirDiffer, err := compareWithGit(artifact.Id, gitArtifactPath, rules, dt)
+		if err != nil {
+			return err
+		}
+
+		if gitContent != nil {
+			// (1) If artifact already exists in Git, then update the changes
 			if dirDiffer {
 				log.Info().Msg("🏆 Changes detected and will be updated to Git")
 				// Update the changes into the Git directory
-				err = dt.CopyContent(downloadedArtifactPath, gitArtifactPath)
+				dt.CopyContent(downloaded, gitContent)
+				err = gitContent.WriteDir(gitArtifactPath)
 				if err != nil {
 					return err
 				}
@@ -185,31 +231,81 @@ func (s *Synchroniser) ArtifactsToGit(packageId string, workDir string, artifact
 
 		} else { // (2) If artifact does not exist in Git, then add it
 			log.Info().Msgf("🏆 Artifact %v does not exist, and will be added to Git", artifact.Id)
-			// Update the script collection in IFlow BPMN2 XML before syncing to Git
+			// Convert the references in IFlow BPMN2 XML before syncing to Git
 			if artifact.ArtifactType == "Integration" {
-				err = file.UpdateBPMN(downloadedArtifactPath, scriptCollectionMap)
+				err = file.UpdateBPMNContent(downloaded, rules)
 				if err != nil {
 					return err
 				}
 			}
-			err = file.ReplaceDir(downloadedArtifactPath, gitArtifactPath)
+			err = downloaded.WriteDir(gitArtifactPath)
 			if err != nil {
 				return err
 			}
+			gitContent = downloaded
+		}
+		gitHash := dt.ContentHash(gitContent)
+		s.recordContentHash(artifact.Id, gitHash)
+		if s.state != nil {
+			s.state.Update(packageId, artifact, gitHash)
 		}
 	}
-
-	// Clean up working directory
-	err = os.RemoveAll(workDir + "/download")
-	if err != nil {
-		return errors.Wrap(err, 0)
+	if s.state != nil {
+		s.state.Prune(packageId, artifacts)
 	}
+	s.logContentHashes("Content hashes of artifacts in Git")
 
 	log.Info().Msg("---------------------------------------------------------------------------------")
 	log.Info().Msgf("🏆 Completed processing of artifacts in integration package %v", packageId)
 	return nil
 }
 
+// compareWithGit downloads the content of the artifact from the tenant and compares it with the artifact directory in
+// Git. The content in Git is nil if the artifact directory does not exist in Git.
+func compareWithGit(artifactId string, gitArtifactPath string, rules []*file.BPMNRule, dt api.DesigntimeArtifact) (downloaded *file.Content, gitContent *file.Content, dirDiffer bool, err error) {
+	// Download artifact content
+	downloaded, err = dt.Download(artifactId)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	log.Info().Msgf("Content hash of artifact %v in tenant is %v", artifactId, dt.ContentHash(downloaded))
+
+	if !file.Exists(fmt.Sprintf("%v/META-INF/MANIFEST.MF", gitArtifactPath)) {
+		return downloaded, nil, false, nil
+	}
+	log.Info().Msg("Comparing content from tenant against Git")
+	gitContent, err = file.ReadDirContent(gitArtifactPath)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	// Diff artifact contents
+	dirDiffer, err = dt.CompareContent(downloaded, gitContent, rules, "git")
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return downloaded, gitContent, dirDiffer, nil
+}
+
+// gitContentUnchanged returns true if the artifact directory in Git exists and has the same content hash as after the
+// previous sync. If the hash was not recorded by an earlier version, only the existence of the directory is checked.
+func (s *Synchroniser) gitContentUnchanged(packageId string, artifactId string, gitArtifactPath string, dt api.DesigntimeArtifact) (bool, string, error) {
+	if !file.Exists(fmt.Sprintf("%v/META-INF/MANIFEST.MF", gitArtifactPath)) {
+		return false, "", nil
+	}
+	gitContent, err := file.ReadDirContent(gitArtifactPath)
+	if err != nil {
+		return false, "", err
+	}
+	gitHash := dt.ContentHash(gitContent)
+	previousHash := s.state.GitContentHash(packageId, artifactId)
+	if previousHash != "" && previousHash != gitHash {
+		log.Info().Msgf("Content hash of artifact %v in Git changed from %v to %v since the previous sync", artifactId, previousHash, gitHash)
+		return false, gitHash, nil
+	}
+	return true, gitHash, nil
+}
+
 func filterArtifacts(artifacts []*api.ArtifactDetails, includedIds []string, excludedIds []string) ([]*api.ArtifactDetails, error) {
 	var output []*api.ArtifactDetails
 
@@ -240,44 +336,7 @@ func filterArtifacts(artifacts []*api.ArtifactDetails, includedIds []string, exc
 	return artifacts, nil
 }
 
-func packageContentDiffer(source *api.PackageSingleData, target *api.PackageSingleData) bool {
-	if source.Root.Name != target.Root.Name {
-		return true
-	}
-	if source.Root.Description != target.Root.Description {
-		return true
-	}
-	if source.Root.ShortText != target.Root.ShortText {
-		return true
-	}
-	if source.Root.Version != target.Root.Version {
-		return true
-	}
-	if source.Root.Vendor != target.Root.Vendor {
-		return true
-	}
-	if source.Root.Mode != target.Root.Mode {
-		return true
-	}
-	if source.Root.Products != target.Root.Products {
-		return true
-	}
-	if source.Root.Keywords != target.Root.Keywords {
-		return true
-	}
-	if source.Root.Countries != target.Root.Countries {
-		return true
-	}
-	if source.Root.Industries != target.Root.Industries {
-		return true
-	}
-	if source.Root.LineOfBusiness != target.Root.LineOfBusiness {
-		return true
-	}
-	return false
-}
-
-func (s *Synchroniser) ArtifactsToTenant(packageId string, workDir string, artifactsDir string, includedIds []string, excludedIds []string) error {
+func (s *Synchroniser) ArtifactsToTenant(packageId string, workDir string, artifactsDir string, includedIds []string, excludedIds []string, opts *UploadOptions) error {
 	// Get directory list
 	baseSourceDir := filepath.Clean(artifactsDir)
 	entries, err := os.ReadDir(baseSourceDir)
@@ -293,17 +352,12 @@ func (s *Synchroniser) ArtifactsToTenant(packageId string, workDir string, artif
 			artifactDir := fmt.Sprintf("%v/%v", baseSourceDir, entry.Name())
 			log.Info().Msg("---------------------------------------------------------------------------------")
 			log.Info().Msgf("Processing directory %v", artifactDir)
-			paramFile := fmt.Sprintf("%v/src/main/resouces/parameters/prop", artifactDir)
-
-			headers, err := GetManifestHeaders(manifestPath)
+			headers, err := file.GetManifestHeaders(manifestPath)
 			if err != nil {
 				return err
 			}
 
-			artifactId := headers.Get("Bundle-SymbolicName")
-			// remove spaces then remove ;singleton:=true
-			artifactId = strings.ReplaceAll(artifactId, " ", "")
-			artifactId = strings.ReplaceAll(artifactId, ";singleton:=true", "")
+			artifactId := file.GetArtifactId(headers)
 
 			// Filter in/out artifacts
 			if len(includedIds) > 0 {
@@ -328,7 +382,15 @@ func (s *Synchroniser) ArtifactsToTenant(packageId string, workDir string, artif
 			}
 
 			log.Info().Msgf("📢 Begin processing for artifact %v", artifactId)
-			err = s.SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, paramFile, nil)
+			// Only IFlows have configured parameters
+			paramFile := ""
+			if artifactType == "Integration" {
+				paramFile, err = getParametersFile(artifactId, artifactDir, opts)
+				if err != nil {
+					return err
+				}
+			}
+			err = s.SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, paramFile, opts)
 			if err != nil {
 				return err
 			}
@@ -337,114 +399,259 @@ func (s *Synchroniser) ArtifactsToTenant(packageId string, workDir string, artif
 	if !artifactDirFound {
 		log.Warn().Msgf("No directory with artifact contents found in %v", baseSourceDir)
 	}
+	s.logParameterUpdates()
+	s.logContentHashes("Content hashes of artifacts uploaded to tenant")
 	return nil
 }
 
-func GetManifestHeaders(manifestPath string) (textproto.MIMEHeader, error) {
-	manifestFile, err := os.Open(manifestPath)
-	if err != nil {
-		return nil, errors.Wrap(err, 0)
+// getParametersFile returns the parameters file of the artifact based on the parameter source. An error is returned
+// if the file named explicitly for parameter source FILE does not exist
+func getParametersFile(artifactId string, artifactDir string, opts *UploadOptions) (string, error) {
+	defaultParamFile := fmt.Sprintf("%v/src/main/resources/parameters.prop", artifactDir)
+	if opts == nil {
+		return defaultParamFile, nil
+	}
+	switch opts.ParamSource {
+	case "ENV":
+		envParamFile := fmt.Sprintf("%v/%v.prop", opts.ParamEnvDir, artifactId)
+		if file.Exists(envParamFile) {
+			log.Info().Msgf("Using %v as parameters.prop file", envParamFile)
+			return envParamFile, nil
+		}
+		log.Warn().Msgf("Parameters file %v not found, using default parameters.prop of artifact", envParamFile)
+	case "FILE":
+		paramFile := strings.ReplaceAll(opts.ParamFile, "{ARTIFACT_ID}", artifactId)
+		if !file.Exists(paramFile) {
+			return "", fmt.Errorf("Parameters file %v for artifact %v does not exist", paramFile, artifactId)
+		}
+		log.Info().Msgf("Using %v as parameters.prop file", paramFile)
+		return paramFile, nil
 	}
-	defer manifestFile.Close()
+	return defaultParamFile, nil
+}
 
-	tp := textproto.NewReader(bufio.NewReader(manifestFile))
-	headers, err := tp.ReadMIMEHeader()
-	if err != nil {
-		return nil, errors.Wrap(err, 0)
+// logParameterUpdates outputs the report of configured parameters updated for each artifact
+func (s *Synchroniser) logParameterUpdates() {
+	if len(s.paramUpdates) == 0 {
+		return
+	}
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msg("Configured parameters updated in tenant")
+	artifactIds := make([]string, 0, len(s.paramUpdates))
+	for artifactId := range s.paramUpdates {
+		artifactIds = append(artifactIds, artifactId)
+	}
+	slices.Sort(artifactIds)
+	for _, artifactId := range artifactIds {
+		log.Info().Msgf("Artifact %v - %d parameter(s) updated", artifactId, len(s.paramUpdates[artifactId]))
+		for _, update := range s.paramUpdates[artifactId] {
+			log.Info().Msgf("  %v: %v -> %v", update.Key, update.OldValue, update.NewValue)
+		}
 	}
-	return headers, nil
+	s.paramUpdates = nil
 }
 
-func (s *Synchroniser) SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, parametersFile string, scriptMap []string) error {
+// recordContentHash stores the content hash of the artifact for the report at the end of the run
+func (s *Synchroniser) recordContentHash(artifactId string, contentHash string) {
+	if s.contentHashes == nil {
+		s.contentHashes = map[string]string{}
+	}
+	s.contentHashes[artifactId] = contentHash
+}
+
+// logContentHashes outputs the report of content hashes for each artifact processed
+func (s *Synchroniser) logContentHashes(title string) {
+	if len(s.contentHashes) == 0 {
+		return
+	}
+	log.Info().Msg("---------------------------------------------------------------------------------")
+	log.Info().Msg(title)
+	artifactIds := make([]string, 0, len(s.contentHashes))
+	for artifactId := range s.contentHashes {
+		artifactIds = append(artifactIds, artifactId)
+	}
+	slices.Sort(artifactIds)
+	for _, artifactId := range artifactIds {
+		log.Info().Msgf("Artifact %v - %v", artifactId, s.contentHashes[artifactId])
+	}
+	s.contentHashes = nil
+}
+
+func (s *Synchroniser) SingleArtifactToTenant(artifactId, artifactName, artifactType, packageId, artifactDir, workDir, parametersFile string, opts *UploadOptions) error {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	rules := opts.Rules
+	if artifactType == "Integration" && (opts.ParamsCheck == "WARN" || opts.ParamsCheck == "ERROR") {
+		err := checkParameters(artifactDir, parametersFile, opts.ParamsCheck == "ERROR")
+		if err != nil {
+			return err
+		}
+	}
+
 	dt := api.NewDesigntimeArtifact(artifactType, s.exe)
 
-	exists, err := artifactExists(artifactId, artifactType, packageId, dt, s.ip)
+	exists, tenantVersion, err := artifactExists(artifactId, artifactType, packageId, dt, s.ip)
+	if err != nil {
+		return err
+	}
+
+	gitContent, err := file.ReadDirContent(artifactDir)
 	if err != nil {
 		return err
 	}
+	uploadContent, err := prepareUploadContent(gitContent, artifactType, rules, dt)
+	if err != nil {
+		return err
+	}
+	// The hash is computed before the version bump, so that it stays the same while the artifact directory is unchanged
+	uploadHash := dt.ContentHash(uploadContent)
+	log.Info().Msgf("Content hash of artifact %v is %v", artifactId, uploadHash)
+	s.recordContentHash(artifactId, uploadHash)
 
+	designUpdated := false
 	if !exists {
 		log.Info().Msgf("Artifact %v will be created", artifactId)
-		if artifactType == "Integration" {
-			err = file.UpdateBPMN(artifactDir, scriptMap)
-			if err != nil {
-				return err
-			}
-		}
-
-		err = prepareUploadDir(workDir, artifactDir, dt)
+		err = createArtifact(artifactId, artifactName, packageId, uploadContent, dt)
 		if err != nil {
 			return err
 		}
-
-		err = createArtifact(artifactId, artifactName, packageId, workDir+"/upload", dt)
+		tenantVersion, err = getContentVersion(uploadContent)
 		if err != nil {
 			return err
 		}
 
 		log.Info().Msg("🏆 Designtime artifact created successfully")
+	} else if s.state != nil && !s.fullSync && s.state.Uploaded(packageId, artifactId, uploadHash, tenantVersion) {
+		log.Info().Msgf("🏆 Artifact %v has the same content hash and version %v in tenant as the previous upload. Download not required", artifactId, tenantVersion)
 	} else {
 		log.Info().Msg("Checking if designtime artifact needs to be updated")
 
-		zipFile := fmt.Sprintf("%v/%v.zip", workDir, artifactId)
-		err = dt.Download(zipFile, artifactId)
+		tenantContent, err := dt.Download(artifactId)
 		if err != nil {
 			return err
 		}
 
-		changesFound, err := compareArtifactContents(workDir, zipFile, artifactDir, scriptMap, dt)
+		changesFound, err := compareArtifactContents(uploadContent, tenantContent, opts.VersionBump != "", dt)
 		if err != nil {
 			return err
 		}
 
 		if changesFound {
 			log.Info().Msg("Changes found in designtime artifact. Designtime artifact will be updated in CPI tenant")
-			err = prepareUploadDir(workDir, artifactDir, dt)
-			if err != nil {
-				return err
-			}
-			err = updateArtifact(artifactId, artifactName, packageId, workDir+"/upload", dt)
-			if err != nil {
-				return err
+			if opts.VersionBump != "" {
+				err = s.bumpVersion(artifactId, artifactDir, tenantVersion, uploadContent, opts)
+				if err != nil {
+					return err
+				}
 			}
-
-			designtimeVersion, _, _, err := dt.Get(artifactId, "active")
+			err = updateArtifact(artifactId, artifactName, packageId, uploadContent, dt)
 			if err != nil {
 				return err
 			}
-			r := api.NewRuntime(s.exe)
-			runtimeVersion, _, err := r.Get(artifactId)
+			tenantVersion, err = getContentVersion(uploadContent)
 			if err != nil {
 				return err
 			}
-			if runtimeVersion == designtimeVersion {
-				log.Info().Msg("Undeploying existing runtime artifact with same version number due to changes in design")
-				err = r.UnDeploy(artifactId)
-				if err != nil {
-					return err
-				}
-			}
+			designUpdated = true
 
 			log.Info().Msg("🏆 Designtime artifact updated successfully")
 		} else {
 			log.Info().Msg("🏆 No changes detected. Designtime artifact does not need to be updated")
 		}
 
-		if artifactType == "Integration" && file.Exists(parametersFile) {
-			log.Info().Msg("Updating configured parameter(s) of Integration designtime artifact where necessary")
-			err = updateConfiguration(artifactId, parametersFile, s.exe)
+	}
+	if s.state != nil {
+		s.state.UpdateUpload(packageId, artifactId, artifactType, uploadHash, tenantVersion)
+	}
+
+	paramsUpdated := false
+	if artifactType == "Integration" && file.Exists(parametersFile) {
+		log.Info().Msg("Updating configured parameter(s) of Integration designtime artifact where necessary")
+		updates, err := updateConfiguration(artifactId, parametersFile, s.exe)
+		if err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if s.paramUpdates == nil {
+				s.paramUpdates = map[string][]*ParameterUpdate{}
+			}
+			s.paramUpdates[artifactId] = updates
+			paramsUpdated = true
+		}
+	}
+
+	if designUpdated || paramsUpdated {
+		return s.updateRuntime(artifactId, designUpdated, paramsUpdated, dt, opts)
+	}
+	return nil
+}
+
+// updateRuntime handles the deployed runtime artifact after changes in design or configured parameters.
+// By default, it is undeployed so that the changes are not active until the next deployment.
+func (s *Synchroniser) updateRuntime(artifactId string, designUpdated bool, paramsUpdated bool, dt api.DesigntimeArtifact, opts *UploadOptions) error {
+	r := api.NewRuntime(s.exe)
+	runtimeVersion, _, err := r.Get(artifactId)
+	if err != nil {
+		return err
+	}
+	if runtimeVersion == "NOT_DEPLOYED" {
+		log.Info().Msg("🏆 No existing runtime artifact deployed")
+		return nil
+	}
+
+	if opts.Redeploy || (paramsUpdated && opts.RedeployOnParamChange) {
+		log.Info().Msgf("🚀 Redeploying runtime artifact %v due to changes in design or configured parameters", artifactId)
+		err = dt.Deploy(artifactId)
+		if err != nil {
+			return err
+		}
+		log.Info().Msgf("Artifact %v deployment triggered", artifactId)
+		if opts.MaxCheckLimit > 0 {
+			err = CheckDeploymentStatus(r, opts.DelayLength, opts.MaxCheckLimit, artifactId)
 			if err != nil {
 				return err
 			}
+			log.Info().Msgf("🏆 Artifact %v redeployed successfully", artifactId)
+		}
+		return nil
+	}
+
+	if paramsUpdated {
+		log.Info().Msg("🏆 Undeploying existing runtime artifact due to changes in configured parameters")
+		return r.UnDeploy(artifactId)
+	}
+	designtimeVersion, _, _, err := dt.Get(artifactId, "active")
+	if err != nil {
+		return err
+	}
+	if runtimeVersion == designtimeVersion {
+		log.Info().Msg("Undeploying existing runtime artifact with same version number due to changes in design")
+		return r.UnDeploy(artifactId)
+	}
+	return nil
+}
+
+func checkParameters(artifactDir string, parametersFile string, failOnError bool) error {
+	log.Info().Msg("Checking parameters against parameters.propdef and IFlow")
+	report, err := params.Check(artifactDir, parametersFile)
+	if err != nil {
+		return err
+	}
+	report.Log()
+	if errorCount := report.Count(params.SeverityError); errorCount > 0 {
+		if failOnError {
+			return fmt.Errorf("Parameter check failed with %d error(s)", errorCount)
 		}
+		log.Warn().Msgf("Parameter check found %d error(s). Use --params-check ERROR to stop on errors", errorCount)
 	}
 	return nil
 }
 
-func artifactExists(artifactId string, artifactType string, packageId string, dt api.DesigntimeArtifact, ip *api.IntegrationPackage) (bool, error) {
-	_, _, exists, err := dt.Get(artifactId, "active")
+func artifactExists(artifactId string, artifactType string, packageId string, dt api.DesigntimeArtifact, ip *api.IntegrationPackage) (bool, string, error) {
+	version, _, exists, err := dt.Get(artifactId, "active")
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
 	if exists {
 		log.Info().Msgf("Active version of artifact %v exists", artifactId)
@@ -452,70 +659,128 @@ func artifactExists(artifactId string, artifactType string, packageId string, dt
 		var details []*api.ArtifactDetails
 		details, err = ip.GetArtifactsData(packageId, artifactType)
 		if err != nil {
-			return false, err
+			return false, "", err
 		}
 		artifact := api.FindArtifactById(artifactId, details)
 		if artifact == nil {
-			return false, fmt.Errorf("Artifact %v not found in package %v", artifactId, packageId)
+			return false, "", fmt.Errorf("Artifact %v not found in package %v", artifactId, packageId)
 		}
 		if artifact.IsDraft {
-			return false, fmt.Errorf("Artifact %v is in Draft state. Save Version of artifact in Web UI first!", artifactId)
+			return false, "", fmt.Errorf("Artifact %v is in Draft state. Save Version of artifact in Web UI first!", artifactId)
 		}
-		return true, nil
+		return true, version, nil
 	} else {
 		log.Info().Msgf("Active version of artifact %v does not exist", artifactId)
-		return false, nil
+		return false, "", nil
 	}
 }
 
-func prepareUploadDir(workDir string, artifactDir string, dt api.DesigntimeArtifact) error {
-	// Clean up previous uploads
-	uploadDir := workDir + "/upload"
-	err := os.RemoveAll(uploadDir)
+// getContentVersion returns the Bundle-Version of the artifact content, which is the version in the tenant after upload
+func getContentVersion(content *file.Content) (string, error) {
+	headers, err := file.GetContentManifestHeaders(content)
 	if err != nil {
-		return errors.Wrap(err, 0)
+		return "", err
 	}
-	return dt.CopyContent(artifactDir, uploadDir)
+	return headers.Get("Bundle-Version"), nil
 }
 
-func createArtifact(artifactId string, artifactName string, packageId string, artifactDir string, dt api.DesigntimeArtifact) error {
-	err := dt.Create(artifactId, artifactName, packageId, artifactDir)
+// prepareUploadContent returns the content of the artifact that is uploaded to the tenant, with the references in
+// IFlow BPMN2 XML converted to the tenant values
+func prepareUploadContent(gitContent *file.Content, artifactType string, rules []*file.BPMNRule, dt api.DesigntimeArtifact) (*file.Content, error) {
+	uploadContent := file.NewContent()
+	dt.CopyContent(gitContent, uploadContent)
+	if artifactType == "Integration" {
+		err := file.UpdateBPMNContent(uploadContent, rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uploadContent, nil
+}
+
+func createArtifact(artifactId string, artifactName string, packageId string, content *file.Content, dt api.DesigntimeArtifact) error {
+	err := dt.Create(artifactId, artifactName, packageId, content)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func updateArtifact(artifactId string, artifactName string, packageId string, artifactDir string, dt api.DesigntimeArtifact) error {
-	err := dt.Update(artifactId, artifactName, packageId, artifactDir)
+func updateArtifact(artifactId string, artifactName string, packageId string, content *file.Content, dt api.DesigntimeArtifact) error {
+	err := dt.Update(artifactId, artifactName, packageId, content)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func compareArtifactContents(workDir string, zipFile string, artifactDir string, scriptMap []string, dt api.DesigntimeArtifact) (bool, error) {
-	tgtDir := fmt.Sprintf("%v/download", workDir)
-	err := os.RemoveAll(tgtDir)
+func compareArtifactContents(uploadContent *file.Content, tenantContent *file.Content, ignoreVersion bool, dt api.DesigntimeArtifact) (bool, error) {
+	if ignoreVersion {
+		// Bundle-Version is bumped during upload, so a lower version in the artifact directory is not a change
+		gitHeaders, err := file.GetContentManifestHeaders(uploadContent)
+		if err != nil {
+			return false, err
+		}
+		tenantHeaders, err := file.GetContentManifestHeaders(tenantContent)
+		if err != nil {
+			return false, err
+		}
+		gitVersion := gitHeaders.Get("Bundle-Version")
+		if str.CompareVersions(gitVersion, tenantHeaders.Get("Bundle-Version")) <= 0 {
+			err = file.UpdateContentManifestHeader(tenantContent, "Bundle-Version", gitVersion)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// References in IFlow BPMN2 XML are already converted in the upload content
+	return dt.CompareContent(uploadContent, tenantContent, nil, "tenant")
+}
+
+// bumpVersion increments Bundle-Version of the artifact in the upload content based on the version in the tenant
+func (s *Synchroniser) bumpVersion(artifactId string, artifactDir string, tenantVersion string, uploadContent *file.Content, opts *UploadOptions) error {
+	manifestPath := artifactDir + "/META-INF/MANIFEST.MF"
+	headers, err := file.GetManifestHeaders(manifestPath)
 	if err != nil {
-		return false, errors.Wrap(err, 0)
+		return err
+	}
+	gitVersion := headers.Get("Bundle-Version")
+	if str.CompareVersions(gitVersion, tenantVersion) > 0 {
+		log.Info().Msgf("Bundle-Version %v is higher than version %v in tenant. Version bump not required", gitVersion, tenantVersion)
+		return nil
 	}
 
-	log.Info().Msgf("Unzipping downloaded designtime artifact %v to %v/download", zipFile, workDir)
-	err = file.UnzipSource(zipFile, tgtDir)
+	newVersion, err := str.BumpVersion(tenantVersion, opts.VersionBump, time.Now())
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Bumping Bundle-Version of artifact %v from %v to %v", artifactId, tenantVersion, newVersion)
+	err = file.UpdateContentManifestHeader(uploadContent, "Bundle-Version", newVersion)
 	if err != nil {
-		return false, err
+		return err
+	}
+	if opts.VersionBumpWriteBack {
+		err = file.UpdateManifestHeader(manifestPath, "Bundle-Version", newVersion)
+		if err != nil {
+			return err
+		}
+		s.versionBumpedFiles = append(s.versionBumpedFiles, manifestPath)
 	}
+	return nil
+}
 
-	return dt.CompareContent(artifactDir, tgtDir, scriptMap, "tenant")
+// VersionBumpedFiles returns the MANIFEST.MF files in the artifact directories that were updated with bumped versions
+func (s *Synchroniser) VersionBumpedFiles() []string {
+	return s.versionBumpedFiles
 }
 
-func updateConfiguration(artifactId string, parametersFile string, exe *httpclnt.HTTPExecuter) error {
+func updateConfiguration(artifactId string, parametersFile string, exe *httpclnt.HTTPExecuter) ([]*ParameterUpdate, error) {
 	// Get configured parameters from tenant
 	c := api.NewConfiguration(exe)
 	tenantParameters, err := c.Get(artifactId, "active")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get parameters from parameters.prop file
@@ -523,7 +788,16 @@ func updateConfiguration(artifactId string, parametersFile string, exe *httpclnt
 	fileParameters := properties.MustLoadFile(parametersFile, properties.UTF8).Map()
 
 	log.Info().Msg("Comparing parameters and updating where necessary")
-	atLeastOneUpdated := false
+	tenantKeys := map[string]bool{}
+	for _, result := range tenantParameters.Root.Results {
+		tenantKeys[result.ParameterKey] = true
+	}
+	for key := range fileParameters {
+		if !tenantKeys[key] {
+			log.Warn().Msgf("Parameter %v in %v does not exist in tenant and will be ignored", key, parametersFile)
+		}
+	}
+	var updates []*ParameterUpdate
 	for _, result := range tenantParameters.Root.Results {
 		if result.DataType != "custom:schedule" { // TODO - handle translation to Cron
 			// Skip updating for schedulers which require translation to Cron values
@@ -532,29 +806,14 @@ func updateConfiguration(artifactId string, parametersFile string, exe *httpclnt
 				log.Info().Msgf("Parameter %v to be updated from %v to %v", result.ParameterKey, result.ParameterValue, fileValue)
 				err = c.Update(artifactId, "active", result.ParameterKey, fileValue)
 				if err != nil {
-					return err
+					return nil, err
 				}
-				atLeastOneUpdated = true
+				updates = append(updates, &ParameterUpdate{Key: result.ParameterKey, OldValue: result.ParameterValue, NewValue: fileValue})
 			}
 		}
 	}
-	if atLeastOneUpdated {
-		r := api.NewRuntime(exe)
-		version, _, err := r.Get(artifactId)
-		if err != nil {
-			return err
-		}
-		if version == "NOT_DEPLOYED" {
-			log.Info().Msg("🏆 No existing runtime artifact deployed")
-		} else {
-			log.Info().Msg("🏆 Undeploying existing runtime artifact due to changes in configured parameters")
-			err = r.UnDeploy(artifactId)
-			if err != nil {
-				return err
-			}
-		}
-	} else {
+	if len(updates) == 0 {
 		log.Info().Msg("🏆 No updates required for configured parameters")
 	}
-	return nil
+	return updates, nil
 }
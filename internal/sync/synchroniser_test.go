@@ -1,7 +1,17 @@
 package sync
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
 	"github.com/engswee/flashpipe/internal/api"
+	"github.com/engswee/flashpipe/internal/drift"
+	"github.com/engswee/flashpipe/internal/file"
+	"github.com/engswee/flashpipe/internal/httpclnt"
+	"github.com/engswee/flashpipe/internal/state"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -63,3 +73,219 @@ func TestFilterExcludeInvalidID(t *testing.T) {
 
 	assert.Equal(t, "Artifact DummyIFlow2 in --ids-exclude does not exist", err.Error(), "Incorrect error message")
 }
+
+func TestGetParametersFileDefault(t *testing.T) {
+	paramFile, err := getParametersFile("Param_IFlow", "../../test/testdata/Params/Param_IFlow", &UploadOptions{ParamSource: "DEFAULT"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "../../test/testdata/Params/Param_IFlow/src/main/resources/parameters.prop", paramFile, "Incorrect parameters file")
+}
+
+func TestGetParametersFileEnv(t *testing.T) {
+	opts := &UploadOptions{ParamSource: "ENV", ParamEnvDir: "../../test/testdata/Params/env"}
+
+	paramFile, err := getParametersFile("Param_IFlow", "../../test/testdata/Params/Param_IFlow", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "../../test/testdata/Params/env/Param_IFlow.prop", paramFile, "Incorrect parameters file")
+	paramFile, err = getParametersFile("Other_IFlow", "../../test/testdata/Params/Other_IFlow", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "../../test/testdata/Params/Other_IFlow/src/main/resources/parameters.prop", paramFile, "Expected fallback to default parameters file")
+}
+
+func TestGetParametersFileFile(t *testing.T) {
+	opts := &UploadOptions{ParamSource: "FILE", ParamFile: "../../test/testdata/Params/env/{ARTIFACT_ID}.prop"}
+
+	paramFile, err := getParametersFile("Param_IFlow", "Param_IFlow", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "../../test/testdata/Params/env/Param_IFlow.prop", paramFile, "Incorrect parameters file")
+}
+
+func TestGetParametersFileFileNotFound(t *testing.T) {
+	opts := &UploadOptions{ParamSource: "FILE", ParamFile: "../../test/testdata/Params/env/{ARTIFACT_ID}.prop"}
+
+	_, err := getParametersFile("Other_IFlow", "Other_IFlow", opts)
+	assert.EqualError(t, err, "Parameters file ../../test/testdata/Params/env/Other_IFlow.prop for artifact Other_IFlow does not exist", "Expected error for missing parameters file")
+}
+
+func TestCheckParameters(t *testing.T) {
+	artifactDir := "../../test/testdata/Params/Param_IFlow"
+	paramFile := artifactDir + "/src/main/resources/parameters.prop"
+
+	assert.NoError(t, checkParameters(artifactDir, paramFile, false), "Expected findings only logged by default")
+	assert.EqualError(t, checkParameters(artifactDir, paramFile, true), "Parameter check failed with 3 error(s)")
+}
+
+func TestArtifactsToTenantParamSourceFile(t *testing.T) {
+	paramDir := t.TempDir()
+	_ = os.WriteFile(paramDir+"/Integration_Test_IFlow.prop", []byte("Key=Value\n"), 0644)
+	var created []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			created = append(created, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasSuffix(r.URL.Path, "/Configurations"):
+			w.Write([]byte(`{"d":{"results":[]}}`))
+		case strings.Contains(r.URL.Path, "DesigntimeArtifacts("):
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+	host, port := httpclnt.GetHostPort(svr.URL)
+	s := New(httpclnt.New("", "", "", "", "dummy", "dummy", host, "http", port, true))
+	opts := &UploadOptions{ParamSource: "FILE", ParamFile: paramDir + "/{ARTIFACT_ID}.prop"}
+
+	// Script collections do not have a parameters file
+	err := s.ArtifactsToTenant("PackageA", t.TempDir(), "../../test/testdata/artifacts/create", []string{"Integration_Test_IFlow", "Integration_Test_Script_Collection"}, nil, opts)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"/api/v1/IntegrationDesigntimeArtifacts", "/api/v1/ScriptCollectionDesigntimeArtifacts"}, created, "Expected IFlow and script collection created")
+}
+
+func TestBumpVersion(t *testing.T) {
+	artifactDir := "../../test/testdata/artifacts/update/Integration_Test_IFlow"
+	uploadContent, err := file.ReadDirContent(artifactDir)
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	// The version in the tenant is passed in, so the synchroniser does not need a tenant connection
+	s := &Synchroniser{}
+
+	err = s.bumpVersion("Integration_Test_IFlow", artifactDir, "1.0.5", uploadContent, &UploadOptions{VersionBump: "patch"})
+	assert.NoError(t, err)
+	headers, err := file.GetContentManifestHeaders(uploadContent)
+	if err != nil {
+		t.Fatalf("GetContentManifestHeaders failed with error - %v", err)
+	}
+	assert.Equal(t, "1.0.6", headers.Get("Bundle-Version"), "Expected version bumped from tenant version")
+}
+
+func TestCompareArtifactContentsIgnoreVersion(t *testing.T) {
+	dt := api.NewDesigntimeArtifact("Integration", httpclnt.New("", "", "", "", "dummy", "dummy", "localhost", "http", 8081, false))
+	gitContent, err := file.ReadDirContent("../../test/testdata/artifacts/update/Integration_Test_IFlow")
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	uploadContent, err := prepareUploadContent(gitContent, "Integration", nil, dt)
+	if err != nil {
+		t.Fatalf("prepareUploadContent failed with error - %v", err)
+	}
+	tenantContent := func() *file.Content {
+		content, _ := prepareUploadContent(gitContent, "Integration", nil, dt)
+		err := file.UpdateContentManifestHeader(content, "Bundle-Version", "1.0.5")
+		if err != nil {
+			t.Fatalf("UpdateContentManifestHeader failed with error - %v", err)
+		}
+		return content
+	}
+
+	changed, err := compareArtifactContents(uploadContent, tenantContent(), true, dt)
+	if err != nil {
+		t.Fatalf("compareArtifactContents failed with error - %v", err)
+	}
+	assert.False(t, changed, "Expected lower Bundle-Version to be ignored")
+
+	changed, err = compareArtifactContents(uploadContent, tenantContent(), false, dt)
+	if err != nil {
+		t.Fatalf("compareArtifactContents failed with error - %v", err)
+	}
+	assert.True(t, changed, "Expected different Bundle-Version to be a change")
+}
+
+func TestGitContentUnchanged(t *testing.T) {
+	gitArtifactPath := "../../test/testdata/artifacts/create/Integration_Test_IFlow"
+	dt := api.NewDesigntimeArtifact("Integration", httpclnt.New("", "", "", "", "dummy", "dummy", "localhost", "http", 8081, true))
+	content, err := file.ReadDirContent(gitArtifactPath)
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	hash := dt.ContentHash(content)
+	artifact := &api.ArtifactDetails{Id: "Integration_Test_IFlow", ArtifactType: "Integration", Version: "1.0.0"}
+
+	st, _ := state.Load(t.TempDir() + "/state.json")
+	s := New(nil)
+	s.UseState(st, false)
+
+	st.Update("DummyPackage", artifact, "")
+	unchanged, _, err := s.gitContentUnchanged("DummyPackage", artifact.Id, gitArtifactPath, dt)
+	assert.NoError(t, err)
+	assert.True(t, unchanged, "Expected unchanged when content hash is not recorded")
+
+	st.Update("DummyPackage", artifact, hash)
+	unchanged, gitHash, err := s.gitContentUnchanged("DummyPackage", artifact.Id, gitArtifactPath, dt)
+	assert.NoError(t, err)
+	assert.True(t, unchanged, "Expected unchanged when content hash is the same")
+	assert.Equal(t, hash, gitHash, "Expected content hash of Git directory")
+
+	st.Update("DummyPackage", artifact, "otherhash")
+	unchanged, _, err = s.gitContentUnchanged("DummyPackage", artifact.Id, gitArtifactPath, dt)
+	assert.NoError(t, err)
+	assert.False(t, unchanged, "Expected changed when content hash differs")
+
+	unchanged, _, err = s.gitContentUnchanged("DummyPackage", artifact.Id, t.TempDir(), dt)
+	assert.NoError(t, err)
+	assert.False(t, unchanged, "Expected changed when directory does not exist in Git")
+}
+
+func TestPackageDriftMissingInTenant(t *testing.T) {
+	s := New(httpclnt.New("", "", "", "", "dummy", "dummy", "localhost", "http", 8081, true))
+
+	result, err := s.PackageDrift("DummyPackage", nil, "../../test/testdata/artifacts/create", nil, nil)
+	if err != nil {
+		t.Fatalf("PackageDrift failed with error - %v", err)
+	}
+	assert.Equal(t, drift.StatusMissingInTenant, result.Status, "Expected package missing in tenant")
+	assert.Equal(t, 4, len(result.Artifacts), "Expected all artifacts in Git")
+	for _, artifact := range result.Artifacts {
+		assert.Equal(t, drift.StatusMissingInTenant, artifact.Status, "Expected artifact %v missing in tenant", artifact.Id)
+	}
+
+	result, err = s.PackageDrift("DummyPackage", nil, t.TempDir(), nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, result, "Expected no result for directory that is not a package directory")
+}
+
+func TestCompareWithGitBPMNRules(t *testing.T) {
+	rules, err := file.LoadBPMNRules("../../test/testdata/BPMNRules/rules.json")
+	if err != nil {
+		t.Fatalf("LoadBPMNRules failed with error - %v", err)
+	}
+	// Content in tenant has the values converted from Git
+	tenantContent, err := file.ReadDirContent("../../test/testdata/artifacts/update/Integration_Test_IFlow")
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	if err = file.UpdateBPMNContent(tenantContent, rules); err != nil {
+		t.Fatalf("UpdateBPMNContent failed with error - %v", err)
+	}
+	// Content in Git is synced from the tenant with the reversed rules
+	gitContent, err := file.ReadDirContent("../../test/testdata/artifacts/update/Integration_Test_IFlow")
+	if err != nil {
+		t.Fatalf("ReadDirContent failed with error - %v", err)
+	}
+	gitContent.Replace(tenantContent, "src/main/resources")
+	if err = file.UpdateBPMNContent(gitContent, file.ReverseBPMNRules(rules)); err != nil {
+		t.Fatalf("UpdateBPMNContent failed with error - %v", err)
+	}
+	gitArtifactPath := t.TempDir()
+	if err = gitContent.WriteDir(gitArtifactPath); err != nil {
+		t.Fatalf("WriteDir failed with error - %v", err)
+	}
+	var zipped bytes.Buffer
+	if err = tenantContent.Zip(&zipped); err != nil {
+		t.Fatalf("Zip failed with error - %v", err)
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(zipped.Bytes())
+	}))
+	defer svr.Close()
+	host, port := httpclnt.GetHostPort(svr.URL)
+	dt := api.NewDesigntimeArtifact("Integration", httpclnt.New("", "", "", "", "dummy", "dummy", host, "http", port, true))
+
+	_, _, dirDiffer, err := compareWithGit("Integration_Test_IFlow", gitArtifactPath, nil, dt)
+	assert.NoError(t, err)
+	assert.True(t, dirDiffer, "Expected difference without BPMN rules")
+
+	_, _, dirDiffer, err = compareWithGit("Integration_Test_IFlow", gitArtifactPath, file.ReverseBPMNRules(rules), dt)
+	assert.NoError(t, err)
+	assert.False(t, dirDiffer, "Expected no difference with reversed BPMN rules")
+}
@@ -0,0 +1,60 @@
+package traffic
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-errors/errors"
+)
+
+var columns = []string{"PROXY", "OPERATION", "CALLS", "ERRORS", "ERROR_RATE", "P50_MS", "P95_MS", "P99_MS"}
+
+// Write outputs the report in the format (table, json or csv). In table and csv formats, the total of each APIProxy
+// is shown with operation *.
+func (r *Report) Write(w io.Writer, format string) error {
+	var err error
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, row := range r.rows() {
+			_, err = fmt.Fprintln(tw, strings.Join(row, "\t"))
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+		}
+		err = tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		err = cw.WriteAll(r.rows())
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(r)
+	default:
+		return fmt.Errorf("invalid value for output format = %v", format)
+	}
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+func (r *Report) rows() [][]string {
+	rows := [][]string{columns}
+	for _, proxy := range r.Proxies {
+		rows = append(rows, statsRow(proxy.Proxy, "*", &proxy.Stats))
+		for _, operation := range proxy.Operations {
+			rows = append(rows, statsRow(proxy.Proxy, operation.Operation, &operation.Stats))
+		}
+	}
+	return rows
+}
+
+func statsRow(proxy string, operation string, s *Stats) []string {
+	return []string{proxy, operation, fmt.Sprint(s.Calls), fmt.Sprint(s.Errors), fmt.Sprintf("%.2f", s.ErrorRate),
+		fmt.Sprint(s.LatencyP50), fmt.Sprint(s.LatencyP95), fmt.Sprint(s.LatencyP99)}
+}
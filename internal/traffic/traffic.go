@@ -0,0 +1,162 @@
+package traffic
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/api"
+)
+
+// Stats summarises the calls of an APIProxy or one of its operations. Latencies are in milliseconds and error rate is in percent.
+type Stats struct {
+	Calls      int     `json:"calls"`
+	Errors     int     `json:"errors"`
+	ErrorRate  float64 `json:"errorRate"`
+	LatencyP50 int64   `json:"latencyP50"`
+	LatencyP95 int64   `json:"latencyP95"`
+	LatencyP99 int64   `json:"latencyP99"`
+	latencies  []int64
+}
+
+type OperationStats struct {
+	Operation string `json:"operation"`
+	Stats
+}
+
+type ProxyStats struct {
+	Proxy string `json:"proxy"`
+	Stats
+	Operations []*OperationStats `json:"operations"`
+}
+
+// Thresholds are the limits for the traffic of each APIProxy. Zero values are not checked.
+type Thresholds struct {
+	MinCalls      int
+	MaxErrorRate  float64
+	MaxLatencyP95 int64
+}
+
+type Report struct {
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Proxies    []*ProxyStats `json:"proxies"`
+	Violations []string      `json:"violations,omitempty"`
+}
+
+// Summarise aggregates the calls per APIProxy and operation. All requested APIProxies are included in the report,
+// including those without calls.
+func Summarise(calls []*api.APICallData, proxyNames []string, from time.Time, to time.Time) *Report {
+	proxies := map[string]*ProxyStats{}
+	operations := map[string]map[string]*OperationStats{}
+	for _, name := range proxyNames {
+		proxies[name] = &ProxyStats{Proxy: name}
+		operations[name] = map[string]*OperationStats{}
+	}
+	for _, call := range calls {
+		proxy, ok := proxies[call.APIProxy]
+		if !ok {
+			proxy = &ProxyStats{Proxy: call.APIProxy}
+			proxies[call.APIProxy] = proxy
+			operations[call.APIProxy] = map[string]*OperationStats{}
+		}
+		name := operationName(call)
+		operation, ok := operations[call.APIProxy][name]
+		if !ok {
+			operation = &OperationStats{Operation: name}
+			operations[call.APIProxy][name] = operation
+		}
+		proxy.add(call)
+		operation.add(call)
+	}
+
+	report := &Report{From: from, To: to}
+	for name, proxy := range proxies {
+		proxy.calculate()
+		for _, operation := range operations[name] {
+			operation.calculate()
+			proxy.Operations = append(proxy.Operations, operation)
+		}
+		sort.Slice(proxy.Operations, func(i, j int) bool {
+			return proxy.Operations[i].Operation < proxy.Operations[j].Operation
+		})
+		report.Proxies = append(report.Proxies, proxy)
+	}
+	sort.Slice(report.Proxies, func(i, j int) bool {
+		return report.Proxies[i].Proxy < report.Proxies[j].Proxy
+	})
+	return report
+}
+
+// Check compares the traffic of each APIProxy against the thresholds and records the violations in the report
+func (r *Report) Check(thresholds Thresholds) []string {
+	r.Violations = nil
+	for _, proxy := range r.Proxies {
+		if thresholds.MinCalls > 0 && proxy.Calls < thresholds.MinCalls {
+			r.Violations = append(r.Violations, fmt.Sprintf("%v has %d call(s), less than minimum of %d", proxy.Proxy, proxy.Calls, thresholds.MinCalls))
+		}
+		if thresholds.MaxErrorRate > 0 && proxy.ErrorRate > thresholds.MaxErrorRate {
+			r.Violations = append(r.Violations, fmt.Sprintf("%v has error rate of %.2f%%, more than maximum of %.2f%%", proxy.Proxy, proxy.ErrorRate, thresholds.MaxErrorRate))
+		}
+		if thresholds.MaxLatencyP95 > 0 && proxy.LatencyP95 > thresholds.MaxLatencyP95 {
+			r.Violations = append(r.Violations, fmt.Sprintf("%v has p95 latency of %d ms, more than maximum of %d ms", proxy.Proxy, proxy.LatencyP95, thresholds.MaxLatencyP95))
+		}
+	}
+	return r.Violations
+}
+
+func operationName(call *api.APICallData) string {
+	resource := call.APIResource
+	if resource == "" {
+		resource = "(unknown)"
+	}
+	if call.RequestVerb == "" {
+		return resource
+	}
+	return call.RequestVerb + " " + resource
+}
+
+// add records the call. Calls flagged as errors and calls with 4xx/5xx status codes are counted as errors.
+func (s *Stats) add(call *api.APICallData) {
+	s.Calls++
+	if call.IsError || call.StatusCode >= 400 {
+		s.Errors++
+	}
+	s.latencies = append(s.latencies, call.ResponseTime)
+}
+
+func (s *Stats) calculate() {
+	if s.Calls == 0 {
+		return
+	}
+	s.ErrorRate = math.Round(float64(s.Errors)/float64(s.Calls)*10000) / 100
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	s.LatencyP50 = percentile(s.latencies, 50)
+	s.LatencyP95 = percentile(s.latencies, 95)
+	s.LatencyP99 = percentile(s.latencies, 99)
+}
+
+// percentile returns the value at the percentile of the sorted values using the nearest-rank method
+func percentile(sorted []int64, p int) int64 {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// ParseTime parses a timestamp in RFC3339 format (e.g. 2024-05-01T08:00:00Z), a date (e.g. 2024-05-01, in UTC) or a
+// duration before the reference time (e.g. 24h)
+func ParseTime(value string, reference time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return reference.Add(-duration), nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("%v is not a timestamp, date or duration", value)
+}
@@ -0,0 +1,99 @@
+package traffic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/engswee/flashpipe/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCalls() []*api.APICallData {
+	var calls []*api.APICallData
+	for i := 1; i <= 20; i++ {
+		calls = append(calls, &api.APICallData{APIProxy: "Orders", APIResource: "orders", RequestVerb: "GET", StatusCode: 200, ResponseTime: int64(i * 10)})
+	}
+	calls = append(calls, &api.APICallData{APIProxy: "Orders", APIResource: "orders", RequestVerb: "POST", StatusCode: 500, ResponseTime: 1000})
+	calls = append(calls, &api.APICallData{APIProxy: "Orders", APIResource: "orders", RequestVerb: "POST", StatusCode: 201, ResponseTime: 300, IsError: true})
+	calls = append(calls, &api.APICallData{APIProxy: "Orders", APIResource: "orders", RequestVerb: "POST", StatusCode: 201, ResponseTime: 200})
+	calls = append(calls, &api.APICallData{APIProxy: "Orders", APIResource: "orders", RequestVerb: "POST", StatusCode: 201, ResponseTime: 100})
+	return calls
+}
+
+func TestSummarise(t *testing.T) {
+	report := Summarise(testCalls(), []string{"Orders", "Customers"}, time.Now().Add(-time.Hour), time.Now())
+
+	assert.Equal(t, 2, len(report.Proxies), "Expected requested proxies without calls to be included")
+	assert.Equal(t, "Customers", report.Proxies[0].Proxy, "Expected proxies sorted by name")
+	assert.Equal(t, 0, report.Proxies[0].Calls, "Expected no calls")
+
+	orders := report.Proxies[1]
+	assert.Equal(t, 24, orders.Calls, "Incorrect number of calls")
+	assert.Equal(t, 2, orders.Errors, "Expected error flag and 5xx status code to be counted as errors")
+	assert.Equal(t, 8.33, orders.ErrorRate, "Incorrect error rate")
+	assert.Equal(t, 2, len(orders.Operations), "Expected number of operations = 2")
+
+	get := orders.Operations[0]
+	assert.Equal(t, "GET orders", get.Operation, "Incorrect operation")
+	assert.Equal(t, int64(100), get.LatencyP50, "Incorrect p50 latency")
+	assert.Equal(t, int64(190), get.LatencyP95, "Incorrect p95 latency")
+	assert.Equal(t, int64(200), get.LatencyP99, "Incorrect p99 latency")
+	post := orders.Operations[1]
+	assert.Equal(t, 50.0, post.ErrorRate, "Incorrect error rate")
+	assert.Equal(t, int64(1000), post.LatencyP99, "Incorrect p99 latency")
+}
+
+func TestCheck(t *testing.T) {
+	report := Summarise(testCalls(), []string{"Orders", "Customers"}, time.Now().Add(-time.Hour), time.Now())
+
+	violations := report.Check(Thresholds{})
+	assert.Empty(t, violations, "Expected no violations without thresholds")
+
+	violations = report.Check(Thresholds{MinCalls: 1, MaxErrorRate: 5, MaxLatencyP95: 500})
+	assert.Equal(t, []string{
+		"Customers has 0 call(s), less than minimum of 1",
+		"Orders has error rate of 8.33%, more than maximum of 5.00%",
+	}, violations, "Unexpected violations")
+	assert.Equal(t, violations, report.Violations, "Expected violations recorded in report")
+}
+
+func TestWrite(t *testing.T) {
+	report := Summarise(testCalls(), []string{"Orders"}, time.Now().Add(-time.Hour), time.Now())
+
+	var buf bytes.Buffer
+	err := report.Write(&buf, "csv")
+	if err != nil {
+		t.Fatalf("Write failed with error - %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 4, len(lines), "Expected header, proxy total and 2 operations")
+	assert.Equal(t, "Orders,*,24,2,8.33,110,300,1000", lines[1], "Incorrect proxy total")
+
+	buf.Reset()
+	err = report.Write(&buf, "table")
+	if err != nil {
+		t.Fatalf("Write failed with error - %v", err)
+	}
+	assert.True(t, strings.HasPrefix(buf.String(), "PROXY   OPERATION"), "Expected aligned table header")
+
+	err = report.Write(&buf, "xml")
+	assert.ErrorContains(t, err, "invalid value for output format")
+}
+
+func TestParseTime(t *testing.T) {
+	reference := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+
+	parsed, err := ParseTime("24h", reference)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), parsed, "Expected duration before reference time")
+	parsed, err = ParseTime("2024-05-01", reference)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), parsed, "Incorrect date")
+	parsed, err = ParseTime("2024-05-01T08:30:00+02:00", reference)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 6, 30, 0, 0, time.UTC), parsed.UTC(), "Incorrect timestamp")
+	_, err = ParseTime("yesterday", reference)
+	assert.Error(t, err)
+}
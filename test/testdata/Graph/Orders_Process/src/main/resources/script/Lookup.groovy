@@ -0,0 +1,14 @@
+import com.sap.gateway.ip.core.customdev.util.Message
+import com.sap.it.api.ITApiFactory
+import com.sap.it.api.mapping.ValueMappingApi
+
+Message processData(Message message) {
+    def valueMapping = ITApiFactory.getApi(ValueMappingApi.class, null)
+    // Only the target agency and identifier are maintained in the value mapping artifact
+    def code = valueMapping.getMappedValue("Partner", "Country", message.getProperty("CountryName") as String, "Legacy", "CountryName")
+    // Lookups of unknown value mappings are not linked
+    def unit = valueMapping.getMappedValue("SAP", "Unit", message.getProperty("Unit") as String, "Legacy", "Unit")
+    message.setProperty("Country", code)
+    message.setProperty("LegacyUnit", unit)
+    return message
+}
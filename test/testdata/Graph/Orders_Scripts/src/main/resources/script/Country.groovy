@@ -0,0 +1,10 @@
+import com.sap.gateway.ip.core.customdev.util.Message
+import com.sap.it.api.ITApiFactory
+import com.sap.it.api.mapping.ValueMappingApi
+
+Message processData(Message message) {
+    def valueMapping = ITApiFactory.getApi(ValueMappingApi.class, null)
+    def country = message.getProperty('Country')
+    message.setProperty('CountryName', valueMapping.getMappedValue('SAP', 'Country', country, 'Legacy', 'CountryName'))
+    return message
+}